@@ -10,7 +10,6 @@ import (
 	"math/rand"
 	"os"
 
-	"github.com/segmentio/kafka-go"
 	"gitlab.com/around25/products/matching-engine/model"
 	"gitlab.com/around25/products/matching-engine/net"
 )
@@ -51,7 +50,7 @@ func GenerateOrdersInKafka(broker, topic string, n int) {
 	defer fh.Close()
 	bf := bufio.NewReader(fh)
 
-	batch := make([]kafka.Message, 0, 20000)
+	batch := make([]net.Message, 0, 20000)
 
 	for j := 0; j < n; j++ {
 		msg, _, err := bf.ReadLine()
@@ -66,7 +65,7 @@ func GenerateOrdersInKafka(broker, topic string, n int) {
 		// if err != nil {
 		// 	log.Fatal(err)
 		// }
-		batch = append(batch, kafka.Message{
+		batch = append(batch, net.Message{
 			Value: data,
 		})
 		if len(batch) == cap(batch) {
@@ -74,7 +73,7 @@ func GenerateOrdersInKafka(broker, topic string, n int) {
 			if err != nil {
 				log.Fatal(err)
 			}
-			batch = make([]kafka.Message, 0, 20000)
+			batch = make([]net.Message, 0, 20000)
 		}
 	}
 	log.Println("Generated ", n, "orders in ", topic)
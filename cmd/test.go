@@ -14,7 +14,6 @@ import (
 	"gitlab.com/around25/products/matching-engine/net"
 	"gitlab.com/around25/products/matching-engine/server"
 
-	"github.com/segmentio/kafka-go"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -48,11 +47,11 @@ func test_gen_orders(timeout, delay, topicCount int) {
 	// load server configuration from server
 	cfg := server.LoadConfig(viper.GetViper())
 
-	producers := make([]net.KafkaProducer, 0, len(cfg.Brokers.Consumers))
+	producers := make([]net.Producer, 0, len(cfg.Brokers.Consumers))
 	for _, consumer := range cfg.Brokers.Consumers {
 		producer := net.NewKafkaProducer(cfg.Kafka.Writer, consumer.Hosts, false, "testing-producer")
 		producer.Start()
-		go func(producer net.KafkaProducer, topics []string) {
+		go func(producer net.Producer, topics []string) {
 			maxTopics := len(topics)
 			if topicCount > 0 {
 				maxTopics = topicCount
@@ -78,7 +77,7 @@ func test_gen_orders(timeout, delay, topicCount int) {
 					Type:      model.OrderType_Limit,
 				}
 				data, _ := order.ToBinary()
-				producer.WriteMessages(context.Background(), kafka.Message{Value: data})
+				producer.WriteMessages(context.Background(), net.Message{Value: data})
 				index++
 				if delay > 0 {
 					log.Println(topics[topicIndex], order)
@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"hash/crc32"
+	"strconv"
+
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+// DefaultChecksumLevels is the number of price levels on each side included by default in the depth checksum
+const DefaultChecksumLevels = 10
+
+// DepthChecksum computes the CRC32 checksum of the top price levels of an order book
+//
+// The bids must be sorted from the highest price and the asks from the lowest price, like in a depth snapshot.
+// Only the first levels on each side are used and the levels with a zero amount are skipped.
+// The checksum is computed over the asks followed by the bids by concatenating the decimal representation of the
+// price and the amount of each level, so clients can use the same function to verify their local copy of the book.
+func DepthChecksum(bids, asks []*model.DepthLevel, levels int) uint32 {
+	buffer := make([]byte, 0, 2*levels*40)
+	buffer = appendChecksumLevels(buffer, asks, levels)
+	buffer = appendChecksumLevels(buffer, bids, levels)
+	return crc32.ChecksumIEEE(buffer)
+}
+
+func appendChecksumLevels(buffer []byte, list []*model.DepthLevel, levels int) []byte {
+	count := 0
+	for _, level := range list {
+		if count == levels {
+			break
+		}
+		if level.Amount == 0 {
+			continue
+		}
+		buffer = strconv.AppendUint(buffer, level.Price, 10)
+		buffer = strconv.AppendUint(buffer, level.Amount, 10)
+		count++
+	}
+	return buffer
+}
+
+// GetDepthChecksum returns the checksum of the given number of top price levels on each side of the order book
+func (book orderBook) GetDepthChecksum(levels int) uint32 {
+	if levels <= 0 {
+		levels = DefaultChecksumLevels
+	}
+	snapshot := book.GetDepthSnapshot(levels)
+	return DepthChecksum(snapshot.Bids, snapshot.Asks, levels)
+}
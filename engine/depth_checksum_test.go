@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDepthChecksum(t *testing.T) {
+	Convey("Given a list of price levels", t, func() {
+		bids := []*model.DepthLevel{{Price: 100000000, Amount: 300000000}, {Price: 90000000, Amount: 300000000}}
+		asks := []*model.DepthLevel{{Price: 110000000, Amount: 400000000}, {Price: 120000000, Amount: 500000000}}
+
+		Convey("The checksum should be computed over the asks followed by the bids", func() {
+			So(DepthChecksum(bids, asks, 1), ShouldEqual, 3379827614)
+			So(DepthChecksum(nil, asks, 1), ShouldEqual, 1454345341)
+		})
+
+		Convey("Levels with a zero amount should be skipped", func() {
+			withRemoved := []*model.DepthLevel{{Price: 105000000, Amount: 0}, bids[0], bids[1]}
+			So(DepthChecksum(withRemoved, asks, 1), ShouldEqual, DepthChecksum(bids, asks, 1))
+		})
+	})
+
+	Convey("Given an order book", t, func() {
+		book := NewOrderBook("btcusd", 8, 8)
+		events := make([]model.Event, 0, 5)
+		book.Process(model.NewOrder(1, uint64(100000000), uint64(300000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		book.Process(model.NewOrder(2, uint64(90000000), uint64(300000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		book.Process(model.NewOrder(3, uint64(110000000), uint64(400000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+
+		Convey("The checksum should match the one computed from the snapshot", func() {
+			snapshot := book.GetDepthSnapshot(0)
+			So(book.GetDepthChecksum(1), ShouldEqual, 3379827614)
+			So(book.GetDepthChecksum(0), ShouldEqual, DepthChecksum(snapshot.Bids, snapshot.Asks, DefaultChecksumLevels))
+		})
+
+		Convey("The checksum should change when the order book changes", func() {
+			before := book.GetDepthChecksum(10)
+			book.Process(model.NewOrder(4, uint64(100000000), uint64(100000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			So(book.GetDepthChecksum(10), ShouldNotEqual, before)
+		})
+	})
+}
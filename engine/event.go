@@ -1,25 +1,31 @@
 package engine
 
 import (
-	"github.com/segmentio/kafka-go"
 	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
 )
 
 // Event structure for order execution
 type Event struct {
-	Msg    kafka.Message
+	Msg    net.Message
 	Order  model.Order
 	Events []model.Event
+	// Depth contains the price levels changed by the order when they are forwarded with the events
+	Depth *model.DepthUpdate
+	// Err is set when the message could not be decoded as an order
+	Err error
 }
 
 // NewEvent Create a new event
-func NewEvent(msg kafka.Message) Event {
+func NewEvent(msg net.Message) Event {
 	return Event{Msg: msg}
 }
 
 // Decode the contained message into a proper order
-func (event *Event) Decode() {
-	event.Order.FromBinary(event.Msg.Value)
+// - The error is also kept on the event so the message can be handled after it reaches the trading engine
+func (event *Event) Decode() error {
+	event.Err = event.Order.FromBinary(event.Msg.Value)
+	return event.Err
 }
 
 // SetEvents - sets the generated events from that order on the current market
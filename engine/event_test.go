@@ -3,20 +3,21 @@ package engine_test
 import (
 	"testing"
 
-	"github.com/segmentio/kafka-go"
 	. "github.com/smartystreets/goconvey/convey"
 	"gitlab.com/around25/products/matching-engine/engine"
 	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
 )
 
 func TestEventsUsage(t *testing.T) {
 	Convey("Create a new event", t, func() {
 		order := model.Order{ID: 1, Price: 1200000000, Amount: 121300000000}
 		encoded, _ := order.ToBinary()
-		msg := kafka.Message{Value: encoded}
+		msg := net.Message{Value: encoded}
 		event := engine.NewEvent(msg)
 		Convey("I should be able to decode the message as an order", func() {
-			event.Decode()
+			So(event.Decode(), ShouldBeNil)
+			So(event.Err, ShouldBeNil)
 			So(event.Order.ID, ShouldEqual, 1)
 			So(event.Order.Price, ShouldEqual, 1200000000)
 			So(event.Order.Amount, ShouldEqual, 121300000000)
@@ -26,4 +27,13 @@ func TestEventsUsage(t *testing.T) {
 			So(event.HasEvents(), ShouldEqual, true)
 		})
 	})
+
+	Convey("Create an event from a corrupted message", t, func() {
+		event := engine.NewEvent(net.Message{Value: []byte{0xff, 0xff, 0xff}})
+		Convey("Decoding should fail and keep the error on the event", func() {
+			err := event.Decode()
+			So(err, ShouldNotBeNil)
+			So(event.Err, ShouldEqual, err)
+		})
+	})
 }
@@ -18,6 +18,7 @@ type OrderBook interface {
 	GetLastTradeSeqID() uint64
 	Load(model.MarketBackup) error
 	Backup() model.MarketBackup
+	GetOpenOrders() model.MarketBackup
 	GetMarket() []*SkipList
 	GetMarketID() string
 	GetPricePrecision() int
@@ -26,12 +27,30 @@ type OrderBook interface {
 	GetLowestEntryPrice() uint64
 	GetMarketOrders() ([]model.Order, []model.Order)
 	AppendErrorEvent(*[]model.Event, model.ErrorCode, model.Order)
+	SetRecentOrdersWindow(size int)
+	SetRecentTradesWindow(size int)
+	GetRecentTrades() ([]model.Trade, uint64)
+	SetTradeOperators(operators []uint64)
+	BustTrade(model.Order, *[]model.Event)
+	CorrectTrade(model.Order, *[]model.Event)
+	MassCancel(model.Order, *[]model.Event)
+	GetDepthLevel(model.MarketSide, uint64) uint64
+	GetDepthSnapshot(levels int) model.DepthSnapshot
+	FlushDepthUpdate() (model.DepthUpdate, bool)
+	GetDepthChecksum(levels int) uint32
+	SetBBOEvents(enabled bool)
+	SetIncrements(price, amount uint64)
+	GetOrderFeedSnapshot() model.OrderFeedSnapshot
+	FlushOrderFeed() []*model.OrderFeedUpdate
 }
 
 type orderBook struct {
 	MarketID        string
 	PricePrecision  int
 	VolumePrecision int
+	// smallest step of the prices and amounts of the new orders
+	PriceIncrement  uint64
+	AmountIncrement uint64
 
 	// sequence ids
 	LastEventSeqID uint64
@@ -52,6 +71,27 @@ type orderBook struct {
 	StopLossOrders   *SkipList
 	LowestEntryPrice uint64
 	HighestLossPrice uint64
+
+	// open orders indexed by owner and client order id
+	ClientOrders map[clientOrderKey]clientOrder
+
+	// orders last received used to detect duplicates
+	RecentOrders *recentOrders
+
+	// trades last generated that can be busted or corrected
+	RecentTrades *recentTrades
+	// operators allowed to bust or correct trades
+	TradeOperators map[uint64]bool
+
+	// aggregated amount of the open orders at each price
+	Depth *marketDepth
+
+	// changes of the open orders since the last flush
+	OrderFeed *orderFeed
+
+	// last best bid and offer for which an event was generated
+	BBOEvents bool
+	LastBBO   topOfBook
 }
 
 // NewOrderBook Creates a new empty order book for the trading engine
@@ -76,6 +116,17 @@ func NewOrderBook(marketID string, pricePrecision, volumePrecision int) OrderBoo
 		StopLossOrders:   NewPricePoints(),
 		LowestEntryPrice: 0,
 		HighestLossPrice: 0,
+		// Client order data
+		ClientOrders: make(map[clientOrderKey]clientOrder),
+		// Duplicate order detection
+		RecentOrders: newRecentOrders(DefaultRecentOrdersWindow),
+		// Trade bust and correction
+		RecentTrades:   newRecentTrades(DefaultRecentTradesWindow),
+		TradeOperators: make(map[uint64]bool),
+		// Market depth
+		Depth: newMarketDepth(),
+		// Order feed
+		OrderFeed: newOrderFeed(),
 	}
 }
 
@@ -133,23 +184,53 @@ func (book orderBook) GetMarketOrders() ([]model.Order, []model.Order) {
 func (book *orderBook) Process(order model.Order, events *[]model.Event) {
 	switch order.EventType {
 	case model.CommandType_NewOrder:
-		// add acknowledgement event with status pending for stop orders and status untouched for limit/market orders
-		order = book.ackOrder(order, events)
-		// process the order normally
-		book.processOrder(order, events)
-		// process activated stop orders
-		if events != nil && len(*events) > 0 {
-			stopOrders := book.ActivateStopOrders(events)
-			if stopOrders != nil && len(*stopOrders) > 0 {
-				for _, stopOrder := range *stopOrders {
-					stopOrder.Stop = model.StopLoss_None
-					// recursively process the activated order as a normal order
-					book.Process(stopOrder, events)
-				}
-			}
+		if book.rejectUnknownMarket(order, events) {
+			return
+		}
+		if book.rejectWrongPrecision(order, events) {
+			return
+		}
+		// ignore orders that were already received by the market
+		if book.handleDuplicateOrder(order, events) {
+			return
+		}
+		// reject orders that reuse the client order id of another open order of the same owner
+		if book.hasClientOrder(order) {
+			book.AppendErrorEvent(events, model.ErrorCode_DuplicateClientOrderID, order)
+			return
 		}
+		ackIndex := len(*events)
+		book.processNewOrder(order, events)
+		// remember the order and the generated acknowledgement to detect duplicates
+		book.RecentOrders.add(order, (*events)[ackIndex])
 	case model.CommandType_CancelOrder:
 		book.Cancel(order, events)
+	case model.CommandType_TradeBust:
+		book.BustTrade(order, events)
+	case model.CommandType_TradeCorrect:
+		book.CorrectTrade(order, events)
+	case model.CommandType_CancelAll:
+		book.MassCancel(order, events)
+	}
+	book.appendBBOEvent(events)
+}
+
+// Acknowledge and process a new order along with any stop orders activated by it
+func (book *orderBook) processNewOrder(order model.Order, events *[]model.Event) {
+	// add acknowledgement event with status pending for stop orders and status untouched for limit/market orders
+	order = book.ackOrder(order, events)
+	// process the order normally
+	book.processOrder(order, events)
+	// process activated stop orders
+	if events != nil && len(*events) > 0 {
+		stopOrders := book.ActivateStopOrders(events)
+		if stopOrders != nil && len(*stopOrders) > 0 {
+			for _, stopOrder := range *stopOrders {
+				stopOrder.Stop = model.StopLoss_None
+				// recursively process the activated order as a normal order
+				book.processNewOrder(stopOrder, events)
+			}
+		}
 	}
 }
 
@@ -158,7 +239,8 @@ func (book *orderBook) ackOrder(order model.Order, events *[]model.Event) model.
 		order.Status = model.OrderStatus_Untouched
 	}
 	book.LastEventSeqID++
-	event := model.NewOrderStatusEvent(book.LastEventSeqID, order.Market, order.Type, order.Side, order.ID, order.OwnerID, order.Price, order.Amount, order.Funds, order.Status, order.FilledAmount, order.UsedFunds)
+	order.PlacedSeqID = book.LastEventSeqID
+	event := model.NewOrderStatusEvent(book.LastEventSeqID, order.Market, order.Type, order.Side, order.ID, order.OwnerID, order.ClientOrderID, order.Price, order.Amount, order.Funds, order.Status, order.FilledAmount, order.UsedFunds)
 	*events = append(*events, event)
 	return order
 }
@@ -197,6 +279,19 @@ func (book *orderBook) processOrder(order model.Order, events *[]model.Event) {
 
 // Cancel an order from the order book based on the order price and ID
 func (book *orderBook) Cancel(order model.Order, events *[]model.Event) {
+	if book.rejectUnknownMarket(order, events) {
+		return
+	}
+	defer book.appendBBOEvent(events)
+	// load the order details when cancelling by the owner and client order id
+	if order.CancelByClientOrderID() {
+		found, ok := book.resolveClientOrder(order)
+		if !ok {
+			book.AppendErrorEvent(events, model.ErrorCode_UnknownOrder, order)
+			return
+		}
+		order = found
+	}
 	// cancel stop orders
 	if order.Stop != model.StopLoss_None {
 		book.cancelStopOrder(order, events)
@@ -205,15 +300,15 @@ func (book *orderBook) Cancel(order model.Order, events *[]model.Event) {
 	// ignore market orders since they are executed immediatly or cancelled
 	// try to cancel limit order if any
 	if order.Type == model.OrderType_Limit {
-		if book.cancelLimitOrder(order, events) {
+		if book.cancelLimitOrder(order, model.CancelReason_UserRequest, events) {
 			return
 		}
-		book.AppendErrorEvent(events, model.ErrorCode_CancelFailed, order)
+		book.AppendErrorEvent(events, book.cancelFailedCode(order), order)
 	}
 }
 
 // Cancel a stop loss order and return true if the order was found
-func (book *orderBook) cancelStopLossOrder(order model.Order, events *[]model.Event) bool {
+func (book *orderBook) cancelStopLossOrder(order model.Order, reason model.CancelReason, events *[]model.Event) bool {
 	price := order.StopPrice
 	iterator := book.StopLossOrders.Seek(price)
 	// price is outside the bounds of the list
@@ -230,9 +325,9 @@ func (book *orderBook) cancelStopLossOrder(order model.Order, events *[]model.Ev
 		if pricePoint.Entries[i].ID == order.ID {
 			ord := pricePoint.Entries[i]
 			ord.SetStatus(model.OrderStatus_Cancelled)
-			book.LastEventSeqID++
-			*events = append(*events, model.NewOrderStatusEvent(book.LastEventSeqID, book.MarketID, ord.Type, ord.Side, ord.ID, ord.OwnerID, ord.Price, ord.Amount, ord.Funds, ord.Status, ord.FilledAmount, ord.UsedFunds))
+			book.generateCancelOrderEvent(ord, reason, events)
 			book.StopLossOrders.removeEntryByPriceAndIndex(price, pricePoint, i)
+			book.unregisterClientOrder(ord)
 			if len(pricePoint.Entries) == 0 && book.HighestLossPrice == price {
 				if ok := iterator.Previous(); ok {
 					book.HighestLossPrice = iterator.Key()
@@ -249,7 +344,7 @@ func (book *orderBook) cancelStopLossOrder(order model.Order, events *[]model.Ev
 }
 
 // Cancel stop entry order and return true if found
-func (book *orderBook) cancelStopEntryOrder(order model.Order, events *[]model.Event) bool {
+func (book *orderBook) cancelStopEntryOrder(order model.Order, reason model.CancelReason, events *[]model.Event) bool {
 	price := order.StopPrice
 	iterator := book.StopEntryOrders.Seek(price)
 	// price is outside the bounds of the list
@@ -266,9 +361,9 @@ func (book *orderBook) cancelStopEntryOrder(order model.Order, events *[]model.E
 		if pricePoint.Entries[i].ID == order.ID {
 			ord := pricePoint.Entries[i]
 			ord.SetStatus(model.OrderStatus_Cancelled)
-			book.LastEventSeqID++
-			*events = append(*events, model.NewOrderStatusEvent(book.LastEventSeqID, book.MarketID, ord.Type, ord.Side, ord.ID, ord.OwnerID, ord.Price, ord.Amount, ord.Funds, ord.Status, ord.FilledAmount, ord.UsedFunds))
+			book.generateCancelOrderEvent(ord, reason, events)
 			book.StopEntryOrders.removeEntryByPriceAndIndex(price, pricePoint, i)
+			book.unregisterClientOrder(ord)
 			if len(pricePoint.Entries) == 0 && book.LowestEntryPrice == price {
 				if ok := iterator.Next(); ok {
 					book.LowestEntryPrice = iterator.Key()
@@ -289,22 +384,32 @@ func (book *orderBook) cancelStopOrder(order model.Order, events *[]model.Event)
 	if order.Stop == model.StopLoss_None {
 		return
 	}
-	if order.Stop == model.StopLoss_Loss && book.cancelStopLossOrder(order, events) {
+	if order.Stop == model.StopLoss_Loss && book.cancelStopLossOrder(order, model.CancelReason_UserRequest, events) {
 		return
 	}
-	if order.Stop == model.StopLoss_Entry && book.cancelStopEntryOrder(order, events) {
+	if order.Stop == model.StopLoss_Entry && book.cancelStopEntryOrder(order, model.CancelReason_UserRequest, events) {
 		return
 	}
 	// if nothing was cancelled is possible the order was already activated and we should cancel it from the market
 	order.Stop = model.StopLoss_None
-	if book.cancelLimitOrder(order, events) {
+	if book.cancelLimitOrder(order, model.CancelReason_UserRequest, events) {
 		return
 	}
-	book.AppendErrorEvent(events, model.ErrorCode_CancelFailed, order)
+	book.AppendErrorEvent(events, book.cancelFailedCode(order), order)
+}
+
+// cancelFailedCode returns the error code of a cancel request whose order is not open in the order book
+// - CancelFailed if the order was received by the market, since it was already filled or cancelled
+// - UnknownOrder if the id is not among the orders last received by the market
+func (book *orderBook) cancelFailedCode(order model.Order) model.ErrorCode {
+	if _, ok := book.RecentOrders.get(order.ID); ok {
+		return model.ErrorCode_CancelFailed
+	}
+	return model.ErrorCode_UnknownOrder
 }
 
 // Cancel a limit order based on a given order ID and set price
-func (book *orderBook) cancelLimitOrder(order model.Order, events *[]model.Event) bool {
+func (book *orderBook) cancelLimitOrder(order model.Order, reason model.CancelReason, events *[]model.Event) bool {
 	if order.Side == model.MarketSide_Buy {
 		iterator := book.BuyEntries.Seek(order.Price)
 		// price is outside the bounds of the list
@@ -321,8 +426,7 @@ func (book *orderBook) cancelLimitOrder(order model.Order, events *[]model.Event
 			if pricePoint.Entries[i].ID == order.ID {
 				ord := pricePoint.Entries[i]
 				ord.SetStatus(model.OrderStatus_Cancelled)
-				book.LastEventSeqID++
-				*events = append(*events, model.NewOrderStatusEvent(book.LastEventSeqID, book.MarketID, ord.Type, ord.Side, ord.ID, ord.OwnerID, ord.Price, ord.Amount, ord.Funds, ord.Status, ord.FilledAmount, ord.UsedFunds))
+				book.generateCancelOrderEvent(ord, reason, events)
 				book.removeBuyBookEntry(ord.Price, pricePoint, i)
 				// adjust highest bid
 				if len(pricePoint.Entries) == 0 && book.HighestBid == ord.Price {
@@ -354,8 +458,7 @@ func (book *orderBook) cancelLimitOrder(order model.Order, events *[]model.Event
 		if pricePoint.Entries[i].ID == order.ID {
 			ord := pricePoint.Entries[i]
 			ord.SetStatus(model.OrderStatus_Cancelled)
-			book.LastEventSeqID++
-			*events = append(*events, model.NewOrderStatusEvent(book.LastEventSeqID, book.MarketID, ord.Type, ord.Side, ord.ID, ord.OwnerID, ord.Price, ord.Amount, ord.Funds, ord.Status, ord.FilledAmount, ord.UsedFunds))
+			book.generateCancelOrderEvent(ord, reason, events)
 			book.removeSellBookEntry(ord.Price, pricePoint, i)
 			// adjust lowest ask
 			if len(pricePoint.Entries) == 0 && book.LowestAsk == ord.Price {
@@ -370,28 +473,38 @@ func (book *orderBook) cancelLimitOrder(order model.Order, events *[]model.Event
 	return false
 }
 
+// Reject orders that are meant for another market than the one handled by the order book
+func (book *orderBook) rejectUnknownMarket(order model.Order, events *[]model.Event) bool {
+	if order.Market == "" || order.Market == book.MarketID {
+		return false
+	}
+	book.AppendErrorEvent(events, model.ErrorCode_UnknownMarket, order)
+	return true
+}
+
 // Append an error event and increases alst event seq id
 func (book *orderBook) AppendErrorEvent(events *[]model.Event, code model.ErrorCode, order model.Order) {
 	book.LastEventSeqID++
-	*events = append(*events, model.NewErrorEvent(book.LastEventSeqID, book.MarketID, code, order.Type, order.Side, order.ID, order.OwnerID, order.Price, order.Amount, order.Funds))
+	*events = append(*events, model.NewErrorEvent(book.LastEventSeqID, book.MarketID, code, order.Type, order.Side, order.ID, order.OwnerID, order.ClientOrderID, order.Price, order.Amount, order.Funds))
 }
 
-// Generate cancel order event, add it to the list of events and increment the LastEventSeqID
-func (book *orderBook) generateCancelOrderEvent(order model.Order, events *[]model.Event) {
+// Generate cancel order event with the reason of the cancellation, add it to the list of events and increment the LastEventSeqID
+func (book *orderBook) generateCancelOrderEvent(order model.Order, reason model.CancelReason, events *[]model.Event) {
 	book.LastEventSeqID++
-	*events = append(*events, model.NewOrderStatusEvent(
+	*events = append(*events, model.NewOrderCancelledEvent(
 		book.LastEventSeqID,
 		book.MarketID,
 		order.Type,
 		order.Side,
 		order.ID,
 		order.OwnerID,
+		order.ClientOrderID,
 		order.Price,
 		order.Amount,
 		order.Funds,
-		model.OrderStatus_Cancelled,
 		order.FilledAmount,
 		order.UsedFunds,
+		reason,
 	))
 }
 
@@ -400,19 +513,31 @@ func (book *orderBook) generateCancelOrderEvent(order model.Order, events *[]mod
 // If the price point does not exist yet it will be created
 func (book *orderBook) addBuyBookEntry(order model.Order) {
 	book.BuyEntries.addOrder(order.Price, order)
+	book.registerClientOrder(order)
+	book.Depth.add(model.MarketSide_Buy, order.Price, order.GetUnfilledAmount())
+	book.appendOrderFeedUpdate(model.OrderFeedAction_AddOrder, order, order.GetUnfilledAmount(), order.GetUnfilledAmount(), 0)
 }
 
 func (book *orderBook) addSellBookEntry(order model.Order) {
 	book.SellEntries.addOrder(order.Price, order)
+	book.registerClientOrder(order)
+	book.Depth.add(model.MarketSide_Sell, order.Price, order.GetUnfilledAmount())
+	book.appendOrderFeedUpdate(model.OrderFeedAction_AddOrder, order, order.GetUnfilledAmount(), order.GetUnfilledAmount(), 0)
 }
 
 // Remove a book entry from the order book
 // The method will also remove the price point entry if both book entry lists are empty
 func (book *orderBook) removeBuyBookEntry(price uint64, pricePoint *PricePoint, index int) {
+	book.unregisterClientOrder(pricePoint.Entries[index])
+	book.Depth.remove(model.MarketSide_Buy, price, pricePoint.Entries[index].GetUnfilledAmount())
+	book.appendOrderFeedDelete(pricePoint.Entries[index])
 	book.BuyEntries.removeEntryByPriceAndIndex(price, pricePoint, index)
 }
 
 func (book *orderBook) removeSellBookEntry(price uint64, pricePoint *PricePoint, index int) {
+	book.unregisterClientOrder(pricePoint.Entries[index])
+	book.Depth.remove(model.MarketSide_Sell, price, pricePoint.Entries[index].GetUnfilledAmount())
+	book.appendOrderFeedDelete(pricePoint.Entries[index])
 	book.SellEntries.removeEntryByPriceAndIndex(price, pricePoint, index)
 }
 
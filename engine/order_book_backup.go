@@ -37,22 +37,68 @@ func (book *orderBook) Load(market model.MarketBackup) error {
 	// load stop orders
 	for _, order := range market.StopEntryOrders {
 		book.StopEntryOrders.addOrder(order.StopPrice, *order)
+		book.registerClientOrder(*order)
 	}
 	for _, order := range market.StopLossOrders {
 		book.StopLossOrders.addOrder(order.StopPrice, *order)
+		book.registerClientOrder(*order)
 	}
 
+	// load the orders last received by the market
+	for _, recent := range market.RecentOrders {
+		if recent.Order == nil || recent.Ack == nil {
+			continue
+		}
+		book.RecentOrders.add(*recent.Order, *recent.Ack)
+	}
+
+	// load the trades that can still be busted or corrected
+	for _, recent := range market.RecentTrades {
+		if recent.Trade == nil {
+			continue
+		}
+		book.RecentTrades.add(recentTrade{Trade: *recent.Trade, AskPrice: recent.AskPrice, BidPrice: recent.BidPrice})
+	}
+
+	// the loaded orders are already included in the first depth snapshot published by the market
+	book.Depth.reset(book.LastEventSeqID)
+	book.OrderFeed = newOrderFeed()
+	book.OrderFeed.lastSeqID = market.OrderFeedSeqID
+	book.LastBBO = book.getTopOfBook()
+
 	return nil
 }
 
 // Backup the order book in another structure for exporting
 func (book *orderBook) Backup() model.MarketBackup {
+	market := book.GetOpenOrders()
+	market.RecentOrders = make([]*model.RecentOrder, 0, book.RecentOrders.count)
+	market.RecentTrades = make([]*model.RecentTrade, 0, len(book.RecentTrades.index))
+
+	// backup the orders last received by the market
+	for _, entry := range book.RecentOrders.list() {
+		var order, ack = entry.Order, entry.Ack
+		market.RecentOrders = append(market.RecentOrders, &model.RecentOrder{Order: &order, Ack: &ack})
+	}
+
+	// backup the trades that can still be busted or corrected
+	for _, entry := range book.RecentTrades.list() {
+		var trade = entry.Trade
+		market.RecentTrades = append(market.RecentTrades, &model.RecentTrade{Trade: &trade, AskPrice: entry.AskPrice, BidPrice: entry.BidPrice})
+	}
+	return market
+}
+
+// GetOpenOrders copies the open orders and the sequence ids of the order book
+// - Unlike Backup it does not copy the windows of recent orders and trades so it can be called often
+func (book *orderBook) GetOpenOrders() model.MarketBackup {
 	market := model.MarketBackup{
 		MarketID:          book.MarketID,
 		PricePrecision:    int32(book.PricePrecision),
 		VolumePrecision:   int32(book.VolumePrecision),
 		EventSeqID:        book.LastEventSeqID,
 		TradeSeqID:        book.LastTradeSeqID,
+		OrderFeedSeqID:    book.OrderFeed.lastSeqID,
 		LowestAsk:         book.LowestAsk,
 		HighestBid:        book.HighestBid,
 		LowestEntryPrice:  book.LowestEntryPrice,
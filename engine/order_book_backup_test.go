@@ -3,6 +3,9 @@ package engine_test
 import (
 	"testing"
 
+	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/model"
+
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -25,3 +28,29 @@ func TestOrderBookBackupWithMarket(t *testing.T) {
 		// So(book.GetLowestAsk(), ShouldEqual, book2.GetLowestAsk())
 	})
 }
+
+func TestOrderBookOpenOrders(t *testing.T) {
+	Convey("Given an order book with open orders and a trade", t, func() {
+		book := engine.NewOrderBook("btcusd", 8, 8)
+		events := make([]model.Event, 0, 5)
+		book.Process(model.NewOrder(1, uint64(100000000), uint64(1000000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		book.Process(model.NewOrder(2, uint64(100000000), uint64(400000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		book.Process(model.NewOrder(3, uint64(90000000), uint64(100000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+
+		Convey("The open orders should be copied without the recent orders and trades", func() {
+			market := book.GetOpenOrders()
+			So(market.EventSeqID, ShouldEqual, book.GetLastEventSeqID())
+			So(market.SellOrders, ShouldHaveLength, 1)
+			So(market.SellOrders[0].GetUnfilledAmount(), ShouldEqual, 600000000)
+			So(market.BuyOrders, ShouldHaveLength, 1)
+			So(market.BuyOrders[0].ID, ShouldEqual, 3)
+			So(market.RecentOrders, ShouldBeEmpty)
+			So(market.RecentTrades, ShouldBeEmpty)
+
+			backup := book.Backup()
+			So(backup.SellOrders, ShouldHaveLength, 1)
+			So(backup.RecentOrders, ShouldHaveLength, 3)
+			So(backup.RecentTrades, ShouldHaveLength, 1)
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+// topOfBook is the best bid and the best ask of the order book along with the amount at each price
+type topOfBook struct {
+	BidPrice  uint64
+	BidAmount uint64
+	AskPrice  uint64
+	AskAmount uint64
+}
+
+// SetBBOEvents enables or disables the events generated when the best bid or the best ask changes
+func (book *orderBook) SetBBOEvents(enabled bool) {
+	book.BBOEvents = enabled
+	book.LastBBO = book.getTopOfBook()
+}
+
+func (book *orderBook) getTopOfBook() topOfBook {
+	return topOfBook{
+		BidPrice:  book.HighestBid,
+		BidAmount: book.Depth.Bids[book.HighestBid],
+		AskPrice:  book.LowestAsk,
+		AskAmount: book.Depth.Asks[book.LowestAsk],
+	}
+}
+
+// append a BBO event if the price or the amount of the best bid or the best ask changed since the last one
+func (book *orderBook) appendBBOEvent(events *[]model.Event) {
+	if !book.BBOEvents {
+		return
+	}
+	top := book.getTopOfBook()
+	if top == book.LastBBO {
+		return
+	}
+	book.LastBBO = top
+	book.LastEventSeqID++
+	*events = append(*events, model.NewBBOEvent(book.LastEventSeqID, book.MarketID, top.BidPrice, top.BidAmount, top.AskPrice, top.AskAmount))
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderBookBBOEvents(t *testing.T) {
+	Convey("Given an order book with BBO events enabled", t, func() {
+		book := NewOrderBook("btcusd", 8, 8)
+		book.SetBBOEvents(true)
+		events := make([]model.Event, 0, 5)
+
+		Convey("A new best bid should generate a BBO event after the order events", func() {
+			book.Process(model.NewOrder(1, uint64(100000000), uint64(300000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			So(len(events), ShouldEqual, 2)
+			So(events[1].Type, ShouldEqual, model.EventType_BestBidOffer)
+			So(events[1].SeqID, ShouldEqual, 2)
+			bbo := events[1].GetBBO()
+			So(bbo.BidPrice, ShouldEqual, 100000000)
+			So(bbo.BidAmount, ShouldEqual, 300000000)
+			So(bbo.AskPrice, ShouldEqual, 0)
+			So(bbo.AskAmount, ShouldEqual, 0)
+
+			Convey("an order below the best bid should not generate a BBO event", func() {
+				events = events[0:0]
+				book.Process(model.NewOrder(2, uint64(90000000), uint64(300000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+				So(len(events), ShouldEqual, 1)
+			})
+
+			Convey("an order at the best bid should change the amount of the best bid", func() {
+				events = events[0:0]
+				book.Process(model.NewOrder(2, uint64(100000000), uint64(200000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+				So(len(events), ShouldEqual, 2)
+				So(events[1].GetBBO().BidAmount, ShouldEqual, 500000000)
+			})
+
+			Convey("a trade with the best bid should change its amount", func() {
+				events = events[0:0]
+				book.Process(model.NewOrder(2, uint64(100000000), uint64(100000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+				last := events[len(events)-1]
+				So(last.Type, ShouldEqual, model.EventType_BestBidOffer)
+				So(last.GetBBO().BidAmount, ShouldEqual, 200000000)
+				So(last.GetBBO().AskPrice, ShouldEqual, 0)
+			})
+
+			Convey("cancelling the best bid should clear it", func() {
+				events = events[0:0]
+				book.Cancel(model.NewOrder(1, uint64(100000000), 0, model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_CancelOrder), &events)
+				So(len(events), ShouldEqual, 2)
+				So(events[1].GetBBO().BidPrice, ShouldEqual, 0)
+				So(events[1].GetBBO().BidAmount, ShouldEqual, 0)
+			})
+
+			Convey("a new best ask should generate a BBO event with both sides", func() {
+				events = events[0:0]
+				book.Process(model.NewOrder(2, uint64(110000000), uint64(100000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+				So(len(events), ShouldEqual, 2)
+				So(events[1].GetBBO().BidPrice, ShouldEqual, 100000000)
+				So(events[1].GetBBO().AskPrice, ShouldEqual, 110000000)
+				So(events[1].GetBBO().AskAmount, ShouldEqual, 100000000)
+			})
+
+			Convey("loading the market from a backup should not generate a BBO event for the loaded orders", func() {
+				restored := NewOrderBook("btcusd", 8, 8)
+				restored.SetBBOEvents(true)
+				restored.Load(book.Backup())
+				events = events[0:0]
+				restored.Process(model.NewOrder(2, uint64(90000000), uint64(300000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+				So(len(events), ShouldEqual, 1)
+			})
+		})
+	})
+
+	Convey("Given an order book with BBO events disabled", t, func() {
+		book := NewOrderBook("btcusd", 8, 8)
+		events := make([]model.Event, 0, 5)
+		book.Process(model.NewOrder(1, uint64(100000000), uint64(300000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		So(len(events), ShouldEqual, 1)
+	})
+}
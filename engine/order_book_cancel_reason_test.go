@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderBookCancelReasons(t *testing.T) {
+	Convey("Given an empty order book", t, func() {
+		book := NewOrderBook("btcusd", 8, 8)
+		events := make([]model.Event, 0, 5)
+
+		Convey("A user cancel should have the user request reason", func() {
+			book.Process(model.NewOrder(1, uint64(100000000), uint64(12000000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			events = events[0:0]
+			book.Cancel(model.NewOrder(1, uint64(100000000), 0, model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_CancelOrder), &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetOrderStatus().Status, ShouldEqual, model.OrderStatus_Cancelled)
+			So(events[0].GetOrderStatus().Reason, ShouldEqual, model.CancelReason_UserRequest)
+		})
+
+		Convey("A market order without liquidity should be cancelled with the no liquidity reason", func() {
+			order := model.NewOrder(1, 0, uint64(100000000), model.MarketSide_Sell, model.OrderType_Market, model.CommandType_NewOrder)
+			order.Funds = uint64(100000000)
+			book.Process(order, &events)
+			So(len(events), ShouldEqual, 2)
+			So(events[0].GetOrderStatus().Reason, ShouldEqual, model.CancelReason_NoReason)
+			So(events[1].GetOrderStatus().Status, ShouldEqual, model.OrderStatus_Cancelled)
+			So(events[1].GetOrderStatus().Reason, ShouldEqual, model.CancelReason_NoLiquidity)
+		})
+
+		Convey("Cancelling a missing order should be rejected as an unknown order", func() {
+			book.Cancel(model.NewOrder(1, uint64(100000000), 0, model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_CancelOrder), &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_UnknownOrder)
+
+			events = events[0:0]
+			order := model.NewOrder(2, uint64(100000000), 0, model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_CancelOrder)
+			order.Stop = model.StopLoss_Loss
+			order.StopPrice = uint64(90000000)
+			book.Cancel(order, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_UnknownOrder)
+		})
+
+		Convey("Cancelling an order that is no longer open should fail", func() {
+			book.Process(model.NewOrder(1, uint64(100000000), uint64(12000000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			cancel := model.NewOrder(1, uint64(100000000), 0, model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_CancelOrder)
+			book.Cancel(cancel, &events)
+			events = events[0:0]
+			book.Cancel(cancel, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_CancelFailed)
+
+			events = events[0:0]
+			stop := model.NewOrder(2, uint64(100000000), uint64(12000000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder)
+			stop.Stop = model.StopLoss_Loss
+			stop.StopPrice = uint64(90000000)
+			book.Process(stop, &events)
+			stop.EventType = model.CommandType_CancelOrder
+			book.Cancel(stop, &events)
+			events = events[0:0]
+			book.Cancel(stop, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_CancelFailed)
+		})
+
+		Convey("Orders with a price or an amount that is not a multiple of the increments should be rejected", func() {
+			book.SetIncrements(1000000, 10000)
+			book.Process(model.NewOrder(1, uint64(100500000), uint64(100000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_WrongPrecision)
+
+			events = events[0:0]
+			book.Process(model.NewOrder(2, uint64(100000000), uint64(100005000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_WrongPrecision)
+
+			events = events[0:0]
+			stop := model.NewOrder(3, uint64(100000000), uint64(100000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder)
+			stop.Stop = model.StopLoss_Loss
+			stop.StopPrice = uint64(90000001)
+			book.Process(stop, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_WrongPrecision)
+
+			events = events[0:0]
+			book.Process(model.NewOrder(4, uint64(101000000), uint64(100010000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].Type, ShouldEqual, model.EventType_OrderStatusChange)
+			So(book.GetHighestBid(), ShouldEqual, 101000000)
+		})
+
+		Convey("Orders for another market should be rejected", func() {
+			order := model.NewOrder(1, uint64(100000000), uint64(12000000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder)
+			order.Market = "ethbtc"
+			book.Process(order, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_UnknownMarket)
+			So(book.GetHighestBid(), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+/**
+Client Order IDs
+================
+
+Every order can optionally contain a ClientOrderID defined by the user that added it.
+The engine echoes it back in every event generated for the order and keeps an index of all open orders
+that have one set, keyed by the owner of the order and the client order id.
+
+The index is used to:
+- reject new orders that reuse the client order id of another open order of the same owner
+- cancel an order based on the OwnerID and ClientOrderID without knowing the ID given to the order
+
+An order is considered open while it's resting in the order book or waiting as a pending stop order.
+The index is not part of the market backup since it can be rebuilt from the open orders when the market is loaded.
+*/
+
+// clientOrderKey identifies an open order by the owner and the id given by the user
+type clientOrderKey struct {
+	OwnerID       uint64
+	ClientOrderID string
+}
+
+// clientOrder holds the fields needed to find an open order in the order book
+type clientOrder struct {
+	ID        uint64
+	Type      model.OrderType
+	Side      model.MarketSide
+	Price     uint64
+	Stop      model.StopLoss
+	StopPrice uint64
+}
+
+// register an open order in the client order index
+func (book *orderBook) registerClientOrder(order model.Order) {
+	if order.ClientOrderID == "" {
+		return
+	}
+	book.ClientOrders[clientOrderKey{OwnerID: order.OwnerID, ClientOrderID: order.ClientOrderID}] = clientOrder{
+		ID:        order.ID,
+		Type:      order.Type,
+		Side:      order.Side,
+		Price:     order.Price,
+		Stop:      order.Stop,
+		StopPrice: order.StopPrice,
+	}
+}
+
+// remove an order from the client order index once it's no longer open
+func (book *orderBook) unregisterClientOrder(order model.Order) {
+	if order.ClientOrderID == "" {
+		return
+	}
+	key := clientOrderKey{OwnerID: order.OwnerID, ClientOrderID: order.ClientOrderID}
+	if entry, ok := book.ClientOrders[key]; ok && entry.ID == order.ID {
+		delete(book.ClientOrders, key)
+	}
+}
+
+// check if the owner of the order already has another open order with the same client order id
+func (book *orderBook) hasClientOrder(order model.Order) bool {
+	if order.ClientOrderID == "" {
+		return false
+	}
+	_, ok := book.ClientOrders[clientOrderKey{OwnerID: order.OwnerID, ClientOrderID: order.ClientOrderID}]
+	return ok
+}
+
+// resolveClientOrder completes a cancel request received with the OwnerID and ClientOrderID with the
+// details of the open order so that it can be cancelled like any other order
+func (book *orderBook) resolveClientOrder(order model.Order) (model.Order, bool) {
+	entry, ok := book.ClientOrders[clientOrderKey{OwnerID: order.OwnerID, ClientOrderID: order.ClientOrderID}]
+	if !ok {
+		return order, false
+	}
+	order.ID = entry.ID
+	order.Type = entry.Type
+	order.Side = entry.Side
+	order.Price = entry.Price
+	order.Stop = entry.Stop
+	order.StopPrice = entry.StopPrice
+	return order, true
+}
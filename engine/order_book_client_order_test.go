@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newClientOrder(id, ownerID uint64, clientOrderID string, price, amount uint64, side model.MarketSide) model.Order {
+	order := model.NewOrder(id, price, amount, side, model.OrderType_Limit, model.CommandType_NewOrder)
+	order.OwnerID = ownerID
+	order.ClientOrderID = clientOrderID
+	return order
+}
+
+func TestOrderBookClientOrderIDs(t *testing.T) {
+	Convey("Given an order book with client order ids", t, func() {
+		book := NewOrderBook("btcusd", 8, 8)
+		events := make([]model.Event, 0, 5)
+
+		Convey("The client order id should be echoed in status events and trades", func() {
+			book.Process(newClientOrder(1, 10, "buy-1", 100000000, 1000000000, model.MarketSide_Buy), &events)
+			So(events[0].GetOrderStatus().ClientOrderID, ShouldEqual, "buy-1")
+			events = events[0:0]
+			book.Process(newClientOrder(2, 20, "sell-1", 100000000, 400000000, model.MarketSide_Sell), &events)
+			So(len(events), ShouldEqual, 4)
+			So(events[0].GetOrderStatus().ClientOrderID, ShouldEqual, "sell-1")
+			So(events[1].GetTrade().AskClientOrderID, ShouldEqual, "sell-1")
+			So(events[1].GetTrade().BidClientOrderID, ShouldEqual, "buy-1")
+			So(events[3].GetOrderStatus().ClientOrderID, ShouldEqual, "buy-1")
+		})
+
+		Convey("A duplicate client order id of the same owner should be rejected while the order is open", func() {
+			book.Process(newClientOrder(1, 10, "buy-1", 100000000, 1000000000, model.MarketSide_Buy), &events)
+			events = events[0:0]
+			book.Process(newClientOrder(2, 10, "buy-1", 90000000, 1000000000, model.MarketSide_Buy), &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].Type, ShouldEqual, model.EventType_Error)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_DuplicateClientOrderID)
+			So(events[0].GetError().ClientOrderID, ShouldEqual, "buy-1")
+
+			Convey("but not for another owner", func() {
+				events = events[0:0]
+				book.Process(newClientOrder(3, 11, "buy-1", 90000000, 1000000000, model.MarketSide_Buy), &events)
+				So(len(events), ShouldEqual, 1)
+				So(events[0].Type, ShouldEqual, model.EventType_OrderStatusChange)
+			})
+
+			Convey("and it can be reused once the order is filled", func() {
+				book.Process(newClientOrder(4, 20, "", 100000000, 1000000000, model.MarketSide_Sell), &events)
+				events = events[0:0]
+				book.Process(newClientOrder(5, 10, "buy-1", 90000000, 1000000000, model.MarketSide_Buy), &events)
+				So(len(events), ShouldEqual, 1)
+				So(events[0].Type, ShouldEqual, model.EventType_OrderStatusChange)
+			})
+		})
+
+		Convey("An open order should be cancelled by the owner and client order id", func() {
+			book.Process(newClientOrder(1, 10, "sell-1", 110000000, 1000000000, model.MarketSide_Sell), &events)
+			events = events[0:0]
+			cancel := model.Order{EventType: model.CommandType_CancelOrder, OwnerID: 10, ClientOrderID: "sell-1"}
+			So(cancel.Valid(), ShouldBeTrue)
+			book.Cancel(cancel, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetOrderStatus().ID, ShouldEqual, 1)
+			So(events[0].GetOrderStatus().Status, ShouldEqual, model.OrderStatus_Cancelled)
+			So(events[0].GetOrderStatus().ClientOrderID, ShouldEqual, "sell-1")
+			So(book.GetLowestAsk(), ShouldEqual, 0)
+
+			Convey("and cancelling it again should fail", func() {
+				events = events[0:0]
+				book.Cancel(cancel, &events)
+				So(len(events), ShouldEqual, 1)
+				So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_UnknownOrder)
+				So(events[0].GetError().ClientOrderID, ShouldEqual, "sell-1")
+			})
+		})
+
+		Convey("A pending stop order should be cancelled by the owner and client order id", func() {
+			order := newClientOrder(1, 10, "stop-1", 110000000, 1000000000, model.MarketSide_Sell)
+			order.Stop = model.StopLoss_Loss
+			order.StopPrice = 100000000
+			book.Process(order, &events)
+			events = events[0:0]
+			book.Cancel(model.Order{EventType: model.CommandType_CancelOrder, OwnerID: 10, ClientOrderID: "stop-1"}, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetOrderStatus().Status, ShouldEqual, model.OrderStatus_Cancelled)
+			So(book.GetHighestLossPrice(), ShouldEqual, 0)
+		})
+
+		Convey("The index should be rebuilt when the market is loaded from a backup", func() {
+			book.Process(newClientOrder(1, 10, "buy-1", 100000000, 1000000000, model.MarketSide_Buy), &events)
+			restored := NewOrderBook("btcusd", 8, 8)
+			restored.Load(book.Backup())
+			events = events[0:0]
+			restored.Cancel(model.Order{EventType: model.CommandType_CancelOrder, OwnerID: 10, ClientOrderID: "buy-1"}, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetOrderStatus().ID, ShouldEqual, 1)
+			So(events[0].GetOrderStatus().Status, ShouldEqual, model.OrderStatus_Cancelled)
+		})
+	})
+
+	Convey("A cancel by client order id should require the owner and the client order id", t, func() {
+		So((&model.Order{EventType: model.CommandType_CancelOrder, OwnerID: 10, ClientOrderID: "buy-1"}).Valid(), ShouldBeTrue)
+		So((&model.Order{EventType: model.CommandType_CancelOrder, ClientOrderID: "buy-1"}).Valid(), ShouldBeFalse)
+		So((&model.Order{EventType: model.CommandType_CancelOrder, OwnerID: 10}).Valid(), ShouldBeFalse)
+	})
+}
@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"sort"
+
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+/**
+Market Depth
+============
+
+The order book keeps the total amount left to be filled by the open limit orders at each price along with the
+list of prices changed since the last depth update was generated.
+
+The levels are updated every time an order is added to or removed from the order book, when a resting order is
+filled by a trade and when the amount of a busted trade is restored on a resting order.
+
+After each command the market engine flushes the changed levels into a DepthUpdate that carries the sequence id of the
+last event generated by the market and the sequence id of the previous update, so a consumer can apply the updates on
+top of a snapshot and detect any gap.
+*/
+
+// marketDepth keeps the aggregated amount at each price for both sides of the order book
+type marketDepth struct {
+	Bids        map[uint64]uint64
+	Asks        map[uint64]uint64
+	changedBids map[uint64]struct{}
+	changedAsks map[uint64]struct{}
+	lastSeqID   uint64
+}
+
+func newMarketDepth() *marketDepth {
+	return &marketDepth{
+		Bids:        make(map[uint64]uint64),
+		Asks:        make(map[uint64]uint64),
+		changedBids: make(map[uint64]struct{}),
+		changedAsks: make(map[uint64]struct{}),
+	}
+}
+
+// add an amount to the price level of the given side
+func (depth *marketDepth) add(side model.MarketSide, price, amount uint64) {
+	if amount == 0 {
+		return
+	}
+	if side == model.MarketSide_Buy {
+		depth.Bids[price] += amount
+		depth.changedBids[price] = struct{}{}
+		return
+	}
+	depth.Asks[price] += amount
+	depth.changedAsks[price] = struct{}{}
+}
+
+// remove an amount from the price level of the given side and delete the level once empty
+func (depth *marketDepth) remove(side model.MarketSide, price, amount uint64) {
+	if amount == 0 {
+		return
+	}
+	levels, changed := depth.Asks, depth.changedAsks
+	if side == model.MarketSide_Buy {
+		levels, changed = depth.Bids, depth.changedBids
+	}
+	if levels[price] <= amount {
+		delete(levels, price)
+	} else {
+		levels[price] -= amount
+	}
+	changed[price] = struct{}{}
+}
+
+// reset the list of changed levels
+func (depth *marketDepth) reset(seqID uint64) {
+	depth.changedBids = make(map[uint64]struct{})
+	depth.changedAsks = make(map[uint64]struct{})
+	depth.lastSeqID = seqID
+}
+
+// list the changed levels of one side with their current amount sorted from the best price
+func changedLevels(levels map[uint64]uint64, changed map[uint64]struct{}, descending bool) []*model.DepthLevel {
+	prices := make([]uint64, 0, len(changed))
+	for price := range changed {
+		prices = append(prices, price)
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		if descending {
+			return prices[i] > prices[j]
+		}
+		return prices[i] < prices[j]
+	})
+	list := make([]*model.DepthLevel, len(prices))
+	for i, price := range prices {
+		list[i] = &model.DepthLevel{Price: price, Amount: levels[price]}
+	}
+	return list
+}
+
+// GetDepthLevel returns the total amount of the open orders at the given price
+func (book orderBook) GetDepthLevel(side model.MarketSide, price uint64) uint64 {
+	if side == model.MarketSide_Buy {
+		return book.Depth.Bids[price]
+	}
+	return book.Depth.Asks[price]
+}
+
+// GetDepthSnapshot returns the price levels of the order book sorted from the best price
+// If levels is greater than zero only the given number of levels is returned for each side
+func (book orderBook) GetDepthSnapshot(levels int) model.DepthSnapshot {
+	snapshot := model.DepthSnapshot{
+		SeqID: book.LastEventSeqID,
+		Bids:  make([]*model.DepthLevel, 0),
+		Asks:  make([]*model.DepthLevel, 0),
+	}
+	if book.HighestBid != 0 {
+		iterator := book.BuyEntries.Seek(book.HighestBid)
+		if iterator != nil {
+			for levels <= 0 || len(snapshot.Bids) < levels {
+				if amount := book.Depth.Bids[iterator.Key()]; amount != 0 {
+					snapshot.Bids = append(snapshot.Bids, &model.DepthLevel{Price: iterator.Key(), Amount: amount})
+				}
+				if ok := iterator.Previous(); !ok {
+					break
+				}
+			}
+			iterator.Close()
+		}
+	}
+	if book.LowestAsk != 0 {
+		iterator := book.SellEntries.Seek(book.LowestAsk)
+		if iterator != nil {
+			for levels <= 0 || len(snapshot.Asks) < levels {
+				if amount := book.Depth.Asks[iterator.Key()]; amount != 0 {
+					snapshot.Asks = append(snapshot.Asks, &model.DepthLevel{Price: iterator.Key(), Amount: amount})
+				}
+				if ok := iterator.Next(); !ok {
+					break
+				}
+			}
+			iterator.Close()
+		}
+	}
+	return snapshot
+}
+
+// FlushDepthUpdate returns the price levels changed since the last call and resets the list of changes
+// Returns false if no level was changed
+func (book *orderBook) FlushDepthUpdate() (model.DepthUpdate, bool) {
+	depth := book.Depth
+	if len(depth.changedBids) == 0 && len(depth.changedAsks) == 0 {
+		return model.DepthUpdate{}, false
+	}
+	update := model.DepthUpdate{
+		SeqID:     book.LastEventSeqID,
+		PrevSeqID: depth.lastSeqID,
+		Bids:      changedLevels(depth.Bids, depth.changedBids, true),
+		Asks:      changedLevels(depth.Asks, depth.changedAsks, false),
+	}
+	depth.reset(book.LastEventSeqID)
+	return update, true
+}
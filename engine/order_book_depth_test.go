@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderBookDepth(t *testing.T) {
+	Convey("Given an order book with orders on both sides", t, func() {
+		book := NewOrderBook("btcusd", 8, 8)
+		events := make([]model.Event, 0, 5)
+		book.Process(model.NewOrder(1, uint64(100000000), uint64(100000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		book.Process(model.NewOrder(2, uint64(100000000), uint64(200000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		book.Process(model.NewOrder(3, uint64(90000000), uint64(300000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		book.Process(model.NewOrder(4, uint64(110000000), uint64(400000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		book.Process(model.NewOrder(5, uint64(120000000), uint64(500000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		firstUpdate, changed := book.FlushDepthUpdate()
+		events = events[0:0]
+
+		Convey("The first update should contain all the added levels", func() {
+			So(changed, ShouldBeTrue)
+			So(firstUpdate.SeqID, ShouldEqual, book.GetLastEventSeqID())
+			So(firstUpdate.PrevSeqID, ShouldEqual, 0)
+			So(firstUpdate.Bids, ShouldResemble, []*model.DepthLevel{{Price: 100000000, Amount: 300000000}, {Price: 90000000, Amount: 300000000}})
+			So(firstUpdate.Asks, ShouldResemble, []*model.DepthLevel{{Price: 110000000, Amount: 400000000}, {Price: 120000000, Amount: 500000000}})
+			_, changed = book.FlushDepthUpdate()
+			So(changed, ShouldBeFalse)
+		})
+
+		Convey("The snapshot should contain the levels sorted from the best price", func() {
+			snapshot := book.GetDepthSnapshot(0)
+			So(snapshot.SeqID, ShouldEqual, book.GetLastEventSeqID())
+			So(snapshot.Bids, ShouldResemble, []*model.DepthLevel{{Price: 100000000, Amount: 300000000}, {Price: 90000000, Amount: 300000000}})
+			So(snapshot.Asks, ShouldResemble, []*model.DepthLevel{{Price: 110000000, Amount: 400000000}, {Price: 120000000, Amount: 500000000}})
+
+			limited := book.GetDepthSnapshot(1)
+			So(len(limited.Bids), ShouldEqual, 1)
+			So(len(limited.Asks), ShouldEqual, 1)
+			So(limited.Bids[0].Price, ShouldEqual, 100000000)
+		})
+
+		Convey("A trade should reduce the level of the maker order", func() {
+			book.Process(model.NewOrder(6, uint64(100000000), uint64(150000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			update, changed := book.FlushDepthUpdate()
+			So(changed, ShouldBeTrue)
+			So(update.PrevSeqID, ShouldEqual, firstUpdate.SeqID)
+			So(update.SeqID, ShouldEqual, book.GetLastEventSeqID())
+			So(update.Bids, ShouldResemble, []*model.DepthLevel{{Price: 100000000, Amount: 150000000}})
+			So(update.Asks, ShouldBeEmpty)
+			So(book.GetDepthLevel(model.MarketSide_Buy, 100000000), ShouldEqual, 150000000)
+		})
+
+		Convey("Removing the last order at a price should report the level with a zero amount", func() {
+			book.Process(model.NewOrder(7, uint64(90000000), uint64(350000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			update, _ := book.FlushDepthUpdate()
+			So(update.Bids, ShouldResemble, []*model.DepthLevel{{Price: 100000000, Amount: 0}, {Price: 90000000, Amount: 250000000}})
+			So(book.GetDepthLevel(model.MarketSide_Buy, 100000000), ShouldEqual, 0)
+
+			events = events[0:0]
+			book.Cancel(model.NewOrder(5, uint64(120000000), 0, model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_CancelOrder), &events)
+			update, _ = book.FlushDepthUpdate()
+			So(update.Asks, ShouldResemble, []*model.DepthLevel{{Price: 120000000, Amount: 0}})
+			So(book.GetDepthSnapshot(0).Asks, ShouldResemble, []*model.DepthLevel{{Price: 110000000, Amount: 400000000}})
+		})
+
+		Convey("The levels should be rebuilt when the market is loaded from a backup", func() {
+			restored := NewOrderBook("btcusd", 8, 8)
+			restored.Load(book.Backup())
+			So(restored.GetDepthSnapshot(0), ShouldResemble, book.GetDepthSnapshot(0))
+			_, changed := restored.FlushDepthUpdate()
+			So(changed, ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,28 @@
+package engine
+
+import (
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+// SetIncrements changes the smallest step of the prices and of the amounts of the new orders, in units of the market
+// - An increment of 0 or 1 accepts any value with the precision of the market
+func (book *orderBook) SetIncrements(price, amount uint64) {
+	book.PriceIncrement = price
+	book.AmountIncrement = amount
+}
+
+// rejectWrongPrecision appends a WrongPrecision error if the price, the stop price or the amount of a new order is
+// not a multiple of the increments of the market and returns true if the order was rejected
+func (book *orderBook) rejectWrongPrecision(order model.Order, events *[]model.Event) bool {
+	if multipleOf(order.Price, book.PriceIncrement) && multipleOf(order.StopPrice, book.PriceIncrement) &&
+		multipleOf(order.Amount, book.AmountIncrement) {
+		return false
+	}
+	book.AppendErrorEvent(events, model.ErrorCode_WrongPrecision, order)
+	return true
+}
+
+// multipleOf checks if the value is a multiple of the increment
+func multipleOf(value, increment uint64) bool {
+	return increment <= 1 || value%increment == 0
+}
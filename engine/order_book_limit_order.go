@@ -6,54 +6,82 @@ import (
 )
 
 // append a new order status to the list of events with the current state of the order
-func (book *orderBook) appendOrderStatusEvent(events *[]model.Event, order model.Order) {
+// and the liquidity flag when the status was changed by a trade
+func (book *orderBook) appendOrderStatusEvent(events *[]model.Event, order model.Order, liquidity model.LiquidityFlag) {
 	book.LastEventSeqID++
-	*events = append(*events, model.NewOrderStatusEvent(
+	event := model.NewOrderStatusEvent(
 		book.LastEventSeqID,
 		order.Market,
 		order.Type,
 		order.Side,
 		order.ID,
 		order.OwnerID,
+		order.ClientOrderID,
 		order.Price,
 		order.Amount,
 		order.Funds,
 		order.Status,
 		order.FilledAmount,
 		order.UsedFunds,
-	))
+	)
+	event.GetOrderStatus().Liquidity = liquidity
+	*events = append(*events, event)
 }
 
+// append a new trade between the taker order and the existing maker order
+// The event is generated before the amount is filled on the orders
 func (book *orderBook) appendTradeEvent(events *[]model.Event, order, existingOrder model.Order, amount uint64) {
 	book.LastEventSeqID++
 	book.LastTradeSeqID++
+	var event model.Event
 	if order.Side == model.MarketSide_Buy {
-		*events = append(*events, model.NewTradeEvent(
+		event = model.NewTradeEvent(
 			book.LastEventSeqID,
-			order.Market,
+			book.MarketID,
 			book.LastTradeSeqID,
 			order.Side,
 			existingOrder.ID,
 			order.ID,
 			existingOrder.OwnerID,
 			order.OwnerID,
+			existingOrder.ClientOrderID,
+			order.ClientOrderID,
 			amount,
 			existingOrder.Price,
-		))
+		)
+		event.GetTrade().AskRemaining = existingOrder.GetUnfilledAmount() - amount
+		event.GetTrade().BidRemaining = order.GetUnfilledAmount() - amount
 	} else {
-		*events = append(*events, model.NewTradeEvent(
+		event = model.NewTradeEvent(
 			book.LastEventSeqID,
-			order.Market,
+			book.MarketID,
 			book.LastTradeSeqID,
 			order.Side,
 			order.ID,
 			existingOrder.ID,
 			order.OwnerID,
 			existingOrder.OwnerID,
+			order.ClientOrderID,
+			existingOrder.ClientOrderID,
 			amount,
 			existingOrder.Price,
-		))
+		)
+		event.GetTrade().AskRemaining = order.GetUnfilledAmount() - amount
+		event.GetTrade().BidRemaining = existingOrder.GetUnfilledAmount() - amount
 	}
+	event.GetTrade().MakerPlacedSeqID = existingOrder.PlacedSeqID
+	*events = append(*events, event)
+	// remember the trade and the prices of the orders so it can be busted or corrected
+	entry := recentTrade{Trade: *event.GetTrade()}
+	if order.Side == model.MarketSide_Buy {
+		entry.AskPrice, entry.BidPrice = existingOrder.Price, order.Price
+	} else {
+		entry.AskPrice, entry.BidPrice = order.Price, existingOrder.Price
+	}
+	book.RecentTrades.add(entry)
+	// the maker order is always resting in the order book
+	book.Depth.remove(existingOrder.Side, existingOrder.Price, amount)
+	book.appendOrderFeedUpdate(model.OrderFeedAction_ExecuteOrder, existingOrder, amount, existingOrder.GetUnfilledAmount()-amount, book.LastTradeSeqID)
 }
 
 func (book *orderBook) processLimitBuy(order model.Order, events *[]model.Event) {
@@ -80,16 +108,16 @@ func (book *orderBook) processLimitBuy(order model.Order, events *[]model.Event)
 						order.SetStatus(model.OrderStatus_Filled)
 
 						// Add updates to the events for the filled orders
-						book.appendOrderStatusEvent(events, order) // order is filled
+						book.appendOrderStatusEvent(events, order, model.LiquidityFlag_Taker) // order is filled
 
 						if sellEntry.GetUnfilledAmount() == 0 {
 							sellEntry.SetStatus(model.OrderStatus_Filled)
-							book.appendOrderStatusEvent(events, *sellEntry) // order is filled or partially filled
+							book.appendOrderStatusEvent(events, *sellEntry, model.LiquidityFlag_Maker) // order is filled or partially filled
 
 							book.removeSellBookEntry(sellEntry.Price, pricePoint, index)
 						} else {
 							sellEntry.SetStatus(model.OrderStatus_PartiallyFilled)
-							book.appendOrderStatusEvent(events, *sellEntry) // order is filled or partially filled
+							book.appendOrderStatusEvent(events, *sellEntry, model.LiquidityFlag_Maker) // order is filled or partially filled
 						}
 
 						complete = true
@@ -108,7 +136,7 @@ func (book *orderBook) processLimitBuy(order model.Order, events *[]model.Event)
 					sellEntry.SetStatus(model.OrderStatus_Filled)
 
 					// Add updates to the events for the filled orders
-					book.appendOrderStatusEvent(events, *sellEntry) // order is filled
+					book.appendOrderStatusEvent(events, *sellEntry, model.LiquidityFlag_Maker) // order is filled
 
 					book.removeSellBookEntry(sellEntry.Price, pricePoint, index)
 					index--
@@ -135,7 +163,7 @@ func (book *orderBook) processLimitBuy(order model.Order, events *[]model.Event)
 	book.addBuyBookEntry(order)
 	// Add updates to the events for the added order
 	if order.Status != model.OrderStatus_Untouched {
-		book.appendOrderStatusEvent(events, order) // order is partially filled
+		book.appendOrderStatusEvent(events, order, model.LiquidityFlag_Taker) // order is partially filled
 	}
 
 	if book.HighestBid < order.Price || book.HighestBid == 0 {
@@ -166,15 +194,15 @@ func (book *orderBook) processLimitSell(order model.Order, events *[]model.Event
 						order.UsedFunds += funds
 						order.SetStatus(model.OrderStatus_Filled)
 						// Add updates to the events for the filled orders
-						book.appendOrderStatusEvent(events, order) // order is filled
+						book.appendOrderStatusEvent(events, order, model.LiquidityFlag_Taker) // order is filled
 
 						if buyEntry.GetUnfilledAmount() == 0 {
 							buyEntry.SetStatus(model.OrderStatus_Filled)
-							book.appendOrderStatusEvent(events, *buyEntry) // order is filled or partially filled
+							book.appendOrderStatusEvent(events, *buyEntry, model.LiquidityFlag_Maker) // order is filled or partially filled
 							book.removeBuyBookEntry(buyEntry.Price, pricePoint, index)
 						} else {
 							buyEntry.SetStatus(model.OrderStatus_PartiallyFilled)
-							book.appendOrderStatusEvent(events, *buyEntry) // order is filled or partially filled
+							book.appendOrderStatusEvent(events, *buyEntry, model.LiquidityFlag_Maker) // order is filled or partially filled
 						}
 
 						complete = true
@@ -192,7 +220,7 @@ func (book *orderBook) processLimitSell(order model.Order, events *[]model.Event
 					buyEntry.FilledAmount += buyEntryUnfilledAmount
 					buyEntry.UsedFunds += funds
 					buyEntry.SetStatus(model.OrderStatus_Filled)
-					book.appendOrderStatusEvent(events, *buyEntry) // order is filled
+					book.appendOrderStatusEvent(events, *buyEntry, model.LiquidityFlag_Maker) // order is filled
 					book.removeBuyBookEntry(buyEntry.Price, pricePoint, index)
 					index--
 				}
@@ -218,7 +246,7 @@ func (book *orderBook) processLimitSell(order model.Order, events *[]model.Event
 
 	// Add updates to the events for the added order
 	if order.Status != model.OrderStatus_Untouched {
-		book.appendOrderStatusEvent(events, order) // order is partially filled
+		book.appendOrderStatusEvent(events, order, model.LiquidityFlag_Taker) // order is partially filled
 	}
 
 	if book.LowestAsk > order.Price || book.LowestAsk == 0 {
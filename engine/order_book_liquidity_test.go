@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderBookExecutionReports(t *testing.T) {
+	Convey("Given an order book with a resting sell order", t, func() {
+		book := NewOrderBook("btcusd", 8, 8)
+		events := make([]model.Event, 0, 5)
+		book.Process(model.NewOrder(1, uint64(100000000), uint64(1000000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		makerSeqID := events[0].SeqID
+		events = events[0:0]
+
+		Convey("A buy order that partially fills the sell order should report the taker and maker fills", func() {
+			book.Process(model.NewOrder(2, uint64(100000000), uint64(400000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			So(len(events), ShouldEqual, 4)
+			So(events[0].GetOrderStatus().Liquidity, ShouldEqual, model.LiquidityFlag_NotFilled)
+
+			trade := events[1].GetTrade()
+			So(events[1].Market, ShouldEqual, "btcusd")
+			So(trade.AskRemaining, ShouldEqual, 600000000)
+			So(trade.BidRemaining, ShouldEqual, 0)
+			So(trade.MakerPlacedSeqID, ShouldEqual, makerSeqID)
+
+			So(events[2].GetOrderStatus().ID, ShouldEqual, 2)
+			So(events[2].GetOrderStatus().Liquidity, ShouldEqual, model.LiquidityFlag_Taker)
+			So(events[3].GetOrderStatus().ID, ShouldEqual, 1)
+			So(events[3].GetOrderStatus().Liquidity, ShouldEqual, model.LiquidityFlag_Maker)
+		})
+
+		Convey("A market sell order should report the remaining amount of both orders", func() {
+			book.Process(model.NewOrder(2, uint64(90000000), uint64(300000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			buySeqID := events[0].SeqID
+			events = events[0:0]
+			order := model.NewOrder(3, 0, uint64(200000000), model.MarketSide_Sell, model.OrderType_Market, model.CommandType_NewOrder)
+			order.Funds = uint64(100000000)
+			book.Process(order, &events)
+			trade := events[1].GetTrade()
+			So(trade.AskRemaining, ShouldEqual, 0)
+			So(trade.BidRemaining, ShouldEqual, 100000000)
+			So(trade.MakerPlacedSeqID, ShouldEqual, buySeqID)
+			So(events[2].GetOrderStatus().Liquidity, ShouldEqual, model.LiquidityFlag_Taker)
+			So(events[3].GetOrderStatus().Liquidity, ShouldEqual, model.LiquidityFlag_Maker)
+		})
+
+		Convey("The placement sequence should be kept in the market backup", func() {
+			restored := NewOrderBook("btcusd", 8, 8)
+			restored.Load(book.Backup())
+			restored.Process(model.NewOrder(2, uint64(100000000), uint64(400000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			So(events[1].GetTrade().MakerPlacedSeqID, ShouldEqual, makerSeqID)
+		})
+	})
+}
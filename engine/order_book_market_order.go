@@ -68,14 +68,14 @@ removed from the pending list and since the limit order was filled it would not
 // all market orders are completed
 func (book *orderBook) processMarketBuy(order model.Order, events *[]model.Event) model.Order {
 	if book.LowestAsk == 0 {
-		book.generateCancelOrderEvent(order, events) // cancel the market order
+		book.generateCancelOrderEvent(order, model.CancelReason_NoLiquidity, events) // cancel the market order
 		return order
 	}
 
 	iterator := book.SellEntries.Seek(book.LowestAsk)
 
 	if iterator == nil {
-		book.generateCancelOrderEvent(order, events) // cancel the market order
+		book.generateCancelOrderEvent(order, model.CancelReason_NoLiquidity, events) // cancel the market order
 		return order
 	}
 
@@ -96,23 +96,21 @@ func (book *orderBook) processMarketBuy(order model.Order, events *[]model.Event
 
 			if sellEntryUnfilledAmount >= amount {
 				funds := utils.Multiply(amount, sellEntry.Price, book.VolumePrecision, book.PricePrecision, book.PricePrecision)
-				book.LastEventSeqID++
-				book.LastTradeSeqID++
-				*events = append(*events, model.NewTradeEvent(book.LastEventSeqID, book.MarketID, book.LastTradeSeqID, model.MarketSide_Buy, sellEntry.ID, order.ID, sellEntry.OwnerID, order.OwnerID, amount, sellEntry.Price))
+				book.appendTradeEvent(events, order, *sellEntry, amount)
 				sellEntry.FilledAmount += amount
 				sellEntry.UsedFunds += funds
 				order.FilledAmount += amount
 				order.UsedFunds += funds
 				order.SetStatus(model.OrderStatus_Filled)
 				// Add updates to the events for the filled orders
-				book.appendOrderStatusEvent(events, order) // order is filled
+				book.appendOrderStatusEvent(events, order, model.LiquidityFlag_Taker) // order is filled
 				if sellEntry.GetUnfilledAmount() == 0 {
 					sellEntry.SetStatus(model.OrderStatus_Filled)
-					book.appendOrderStatusEvent(events, *sellEntry) // order is filled or partially filled
+					book.appendOrderStatusEvent(events, *sellEntry, model.LiquidityFlag_Maker) // order is filled or partially filled
 					book.removeSellBookEntry(sellEntry.Price, pricePoint, index)
 				} else {
 					sellEntry.SetStatus(model.OrderStatus_PartiallyFilled)
-					book.appendOrderStatusEvent(events, *sellEntry) // order is filled or partially filled
+					book.appendOrderStatusEvent(events, *sellEntry, model.LiquidityFlag_Maker) // order is filled or partially filled
 				}
 
 				complete = true
@@ -123,9 +121,7 @@ func (book *orderBook) processMarketBuy(order model.Order, events *[]model.Event
 			// we complete the sell order and we move to the next order
 			// @todo CH: check for overflow issues
 			funds := utils.Multiply(sellEntryUnfilledAmount, sellEntry.Price, book.VolumePrecision, book.PricePrecision, book.PricePrecision)
-			book.LastEventSeqID++
-			book.LastTradeSeqID++
-			*events = append(*events, model.NewTradeEvent(book.LastEventSeqID, book.MarketID, book.LastTradeSeqID, model.MarketSide_Buy, sellEntry.ID, order.ID, sellEntry.OwnerID, order.OwnerID, sellEntryUnfilledAmount, sellEntry.Price))
+			book.appendTradeEvent(events, order, *sellEntry, sellEntryUnfilledAmount)
 			amountAffordable -= sellEntryUnfilledAmount
 			order.FilledAmount += sellEntryUnfilledAmount
 			order.SetStatus(model.OrderStatus_PartiallyFilled)
@@ -136,7 +132,7 @@ func (book *orderBook) processMarketBuy(order model.Order, events *[]model.Event
 			sellEntry.SetStatus(model.OrderStatus_Filled)
 
 			// Add updates to the events for the filled orders
-			book.appendOrderStatusEvent(events, *sellEntry) // order is filled
+			book.appendOrderStatusEvent(events, *sellEntry, model.LiquidityFlag_Maker) // order is filled
 
 			book.removeSellBookEntry(sellEntry.Price, pricePoint, index)
 			index--
@@ -158,9 +154,9 @@ func (book *orderBook) processMarketBuy(order model.Order, events *[]model.Event
 	iterator.Close()
 
 	// Add updates to the events for the added order
-	book.appendOrderStatusEvent(events, order) // order is partially filled
+	book.appendOrderStatusEvent(events, order, model.LiquidityFlag_Taker) // order is partially filled
 
-	book.generateCancelOrderEvent(order, events) // cancel the market order
+	book.generateCancelOrderEvent(order, model.CancelReason_NoLiquidity, events) // cancel the market order
 	return order
 }
 
@@ -173,13 +169,13 @@ func (book *orderBook) processMarketBuy(order model.Order, events *[]model.Event
 // all market orders are completed
 func (book *orderBook) processMarketSell(order model.Order, events *[]model.Event) model.Order {
 	if book.HighestBid == 0 {
-		book.generateCancelOrderEvent(order, events) // cancel the market order
+		book.generateCancelOrderEvent(order, model.CancelReason_NoLiquidity, events) // cancel the market order
 		return order
 	}
 
 	iterator := book.BuyEntries.Seek(book.HighestBid)
 	if iterator == nil {
-		book.generateCancelOrderEvent(order, events) // cancel the market order
+		book.generateCancelOrderEvent(order, model.CancelReason_NoLiquidity, events) // cancel the market order
 		return order
 	}
 
@@ -194,24 +190,22 @@ func (book *orderBook) processMarketSell(order model.Order, events *[]model.Even
 			buyEntryUnfilledAmount := buyEntry.GetUnfilledAmount()
 			// if we can fill the trade instantly then we add the trade and complete the order
 			if buyEntryUnfilledAmount >= orderUnfilledAmount {
-				book.LastEventSeqID++
-				book.LastTradeSeqID++
 				funds := utils.Multiply(orderUnfilledAmount, buyEntry.Price, book.VolumePrecision, book.PricePrecision, book.PricePrecision)
-				*events = append(*events, model.NewTradeEvent(book.LastEventSeqID, book.MarketID, book.LastTradeSeqID, model.MarketSide_Sell, order.ID, buyEntry.ID, order.OwnerID, buyEntry.OwnerID, orderUnfilledAmount, buyEntry.Price))
+				book.appendTradeEvent(events, order, *buyEntry, orderUnfilledAmount)
 				buyEntry.FilledAmount += orderUnfilledAmount
 				buyEntry.UsedFunds += funds
 				order.FilledAmount += orderUnfilledAmount
 				order.UsedFunds += funds
 				order.SetStatus(model.OrderStatus_Filled)
 				// Add updates to the events for the filled orders
-				book.appendOrderStatusEvent(events, order) // order is filled
+				book.appendOrderStatusEvent(events, order, model.LiquidityFlag_Taker) // order is filled
 				if buyEntry.GetUnfilledAmount() == 0 {
 					buyEntry.SetStatus(model.OrderStatus_Filled)
-					book.appendOrderStatusEvent(events, *buyEntry) // order is filled or partially filled
+					book.appendOrderStatusEvent(events, *buyEntry, model.LiquidityFlag_Maker) // order is filled or partially filled
 					book.removeBuyBookEntry(buyEntry.Price, pricePoint, index)
 				} else {
 					buyEntry.SetStatus(model.OrderStatus_PartiallyFilled)
-					book.appendOrderStatusEvent(events, *buyEntry) // order is filled or partially filled
+					book.appendOrderStatusEvent(events, *buyEntry, model.LiquidityFlag_Maker) // order is filled or partially filled
 				}
 
 				complete = true
@@ -220,10 +214,8 @@ func (book *orderBook) processMarketSell(order model.Order, events *[]model.Even
 
 			// if the sell order has a lower amount than what the buy order is then we fill only what we can from the sell order,
 			// we complete the sell order and we move to the next order
-			book.LastEventSeqID++
-			book.LastTradeSeqID++
 			funds := utils.Multiply(buyEntryUnfilledAmount, buyEntry.Price, book.VolumePrecision, book.PricePrecision, book.PricePrecision)
-			*events = append(*events, model.NewTradeEvent(book.LastEventSeqID, book.MarketID, book.LastTradeSeqID, model.MarketSide_Sell, order.ID, buyEntry.ID, order.OwnerID, buyEntry.OwnerID, buyEntryUnfilledAmount, buyEntry.Price))
+			book.appendTradeEvent(events, order, *buyEntry, buyEntryUnfilledAmount)
 			order.FilledAmount += buyEntryUnfilledAmount
 			order.UsedFunds += funds
 			order.SetStatus(model.OrderStatus_PartiallyFilled)
@@ -231,7 +223,7 @@ func (book *orderBook) processMarketSell(order model.Order, events *[]model.Even
 			buyEntry.UsedFunds += funds
 			buyEntry.SetStatus(model.OrderStatus_Filled)
 
-			book.appendOrderStatusEvent(events, *buyEntry) // order is filled
+			book.appendOrderStatusEvent(events, *buyEntry, model.LiquidityFlag_Maker) // order is filled
 
 			book.removeBuyBookEntry(buyEntry.Price, pricePoint, index)
 			index--
@@ -253,7 +245,7 @@ func (book *orderBook) processMarketSell(order model.Order, events *[]model.Even
 	iterator.Close()
 
 	// Add updates to the events for the added order
-	book.appendOrderStatusEvent(events, order)   // order is partially filled
-	book.generateCancelOrderEvent(order, events) // cancel the market order
+	book.appendOrderStatusEvent(events, order, model.LiquidityFlag_Taker)        // order is partially filled
+	book.generateCancelOrderEvent(order, model.CancelReason_NoLiquidity, events) // cancel the market order
 	return order
 }
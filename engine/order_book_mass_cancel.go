@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+/**
+Mass Cancel
+===========
+
+A CancelAll command cancels all the open orders of the owner of the command in the market, including the pending
+stop orders. The orders are resolved from the order book when the command is processed, so the orders added through
+any input are cancelled regardless of the gateway that sends the command.
+
+Each cancelled order generates an order status event with the MassCancel reason. A command for an owner without
+open orders does not generate any event.
+*/
+
+// MassCancel cancels all the open orders of the owner of the command
+func (book *orderBook) MassCancel(command model.Order, events *[]model.Event) {
+	if book.rejectUnknownMarket(command, events) {
+		return
+	}
+	// the orders are collected first since cancelling them changes the price points of the lists
+	for _, order := range appendOwnerOrders(nil, book.BuyEntries, command.OwnerID) {
+		book.cancelLimitOrder(order, model.CancelReason_MassCancel, events)
+	}
+	for _, order := range appendOwnerOrders(nil, book.SellEntries, command.OwnerID) {
+		book.cancelLimitOrder(order, model.CancelReason_MassCancel, events)
+	}
+	for _, order := range appendOwnerOrders(nil, book.StopLossOrders, command.OwnerID) {
+		book.cancelStopLossOrder(order, model.CancelReason_MassCancel, events)
+	}
+	for _, order := range appendOwnerOrders(nil, book.StopEntryOrders, command.OwnerID) {
+		book.cancelStopEntryOrder(order, model.CancelReason_MassCancel, events)
+	}
+}
+
+// appendOwnerOrders adds the orders of the owner found in the price points of the list
+func appendOwnerOrders(orders []model.Order, list *SkipList, ownerID uint64) []model.Order {
+	iterator := list.SeekToFirst()
+	if iterator == nil {
+		return orders
+	}
+	defer iterator.Close()
+	for {
+		for _, order := range iterator.Value().Entries {
+			if order.OwnerID == ownerID {
+				orders = append(orders, order)
+			}
+		}
+		if !iterator.Next() {
+			return orders
+		}
+	}
+}
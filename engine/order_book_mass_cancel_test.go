@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderBookMassCancel(t *testing.T) {
+	Convey("Given an order book with the orders of two owners", t, func() {
+		book := NewOrderBook("btcusd", 8, 8)
+		events := make([]model.Event, 0, 10)
+		for _, order := range []model.Order{
+			model.NewOrder(1, uint64(100000000), uint64(100000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder),
+			model.NewOrder(2, uint64(90000000), uint64(100000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder),
+			model.NewOrder(3, uint64(110000000), uint64(100000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder),
+			model.NewOrder(4, uint64(120000000), uint64(100000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder),
+		} {
+			order.OwnerID = 10 + order.ID%2
+			book.Process(order, &events)
+		}
+		stop := model.NewOrder(5, uint64(80000000), uint64(100000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder)
+		stop.OwnerID = 11
+		stop.Stop = model.StopLoss_Loss
+		stop.StopPrice = uint64(85000000)
+		book.Process(stop, &events)
+		events = events[0:0]
+
+		Convey("All the open orders of the owner should be cancelled with the mass cancel reason", func() {
+			book.Process(model.Order{ID: 100, OwnerID: 11, EventType: model.CommandType_CancelAll}, &events)
+			cancelled := make([]uint64, 0, 3)
+			for _, event := range events {
+				if event.Type != model.EventType_OrderStatusChange {
+					continue
+				}
+				So(event.GetOrderStatus().Status, ShouldEqual, model.OrderStatus_Cancelled)
+				So(event.GetOrderStatus().Reason, ShouldEqual, model.CancelReason_MassCancel)
+				cancelled = append(cancelled, event.GetOrderStatus().ID)
+			}
+			So(cancelled, ShouldResemble, []uint64{1, 3, 5})
+			So(book.GetHighestBid(), ShouldEqual, 90000000)
+			So(book.GetLowestAsk(), ShouldEqual, 120000000)
+			So(book.GetHighestLossPrice(), ShouldEqual, 0)
+			So(book.GetDepthLevel(model.MarketSide_Buy, 100000000), ShouldEqual, 0)
+		})
+
+		Convey("A mass cancel for an owner without open orders should not generate events", func() {
+			book.Process(model.Order{ID: 100, OwnerID: 12, EventType: model.CommandType_CancelAll}, &events)
+			So(events, ShouldBeEmpty)
+			So(book.GetHighestBid(), ShouldEqual, 100000000)
+		})
+	})
+}
@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+/**
+Order Feed
+==========
+
+The order book records every change of the open limit orders directly from the matching loops:
+- AddOrder when an order is added at the end of the queue of its price
+- ExecuteOrder when a resting order is matched by a trade, the order leaves the book once nothing is left to fill
+- DeleteOrder when an order is removed from the book before being filled
+- ModifyOrder when the amount left to fill changes without a trade, like when a busted trade is restored
+
+The updates are numbered with a sequence kept in the market backup, so the updates generated while the orders received
+after the last backup are replayed have the same numbers as the ones published before the market was restarted.
+*/
+
+// orderFeed keeps the updates generated since the last flush
+type orderFeed struct {
+	lastSeqID uint64
+	updates   []*model.OrderFeedUpdate
+}
+
+func newOrderFeed() *orderFeed {
+	return &orderFeed{
+		updates: make([]*model.OrderFeedUpdate, 0),
+	}
+}
+
+// append a new update to the order feed for the given resting order
+func (book *orderBook) appendOrderFeedUpdate(action model.OrderFeedAction, order model.Order, amount, remaining, tradeSeqID uint64) {
+	book.OrderFeed.lastSeqID++
+	book.OrderFeed.updates = append(book.OrderFeed.updates, &model.OrderFeedUpdate{
+		SeqID:      book.OrderFeed.lastSeqID,
+		EventSeqID: book.LastEventSeqID,
+		Action:     action,
+		OrderID:    order.ID,
+		Side:       order.Side,
+		Price:      order.Price,
+		Amount:     amount,
+		Remaining:  remaining,
+		TradeSeqID: tradeSeqID,
+	})
+}
+
+// append a delete update for an order removed from the order book
+// Filled orders are already removed by the last execution so no update is needed for them
+func (book *orderBook) appendOrderFeedDelete(order model.Order) {
+	if order.GetUnfilledAmount() == 0 {
+		return
+	}
+	book.appendOrderFeedUpdate(model.OrderFeedAction_DeleteOrder, order, order.GetUnfilledAmount(), 0, 0)
+}
+
+// GetOrderFeedSnapshot returns all open limit orders in the priority in which they are matched
+func (book orderBook) GetOrderFeedSnapshot() model.OrderFeedSnapshot {
+	snapshot := model.OrderFeedSnapshot{
+		SeqID:      book.OrderFeed.lastSeqID,
+		EventSeqID: book.LastEventSeqID,
+		Bids:       make([]*model.OrderFeedOrder, 0),
+		Asks:       make([]*model.OrderFeedOrder, 0),
+	}
+	if book.HighestBid != 0 {
+		iterator := book.BuyEntries.Seek(book.HighestBid)
+		if iterator != nil {
+			for {
+				for _, order := range iterator.Value().Entries {
+					snapshot.Bids = append(snapshot.Bids, &model.OrderFeedOrder{OrderID: order.ID, Price: order.Price, Remaining: order.GetUnfilledAmount()})
+				}
+				if ok := iterator.Previous(); !ok {
+					break
+				}
+			}
+			iterator.Close()
+		}
+	}
+	if book.LowestAsk != 0 {
+		iterator := book.SellEntries.Seek(book.LowestAsk)
+		if iterator != nil {
+			for {
+				for _, order := range iterator.Value().Entries {
+					snapshot.Asks = append(snapshot.Asks, &model.OrderFeedOrder{OrderID: order.ID, Price: order.Price, Remaining: order.GetUnfilledAmount()})
+				}
+				if ok := iterator.Next(); !ok {
+					break
+				}
+			}
+			iterator.Close()
+		}
+	}
+	return snapshot
+}
+
+// FlushOrderFeed returns the order feed updates generated since the last call and resets the list of updates
+func (book *orderBook) FlushOrderFeed() []*model.OrderFeedUpdate {
+	updates := book.OrderFeed.updates
+	book.OrderFeed.updates = make([]*model.OrderFeedUpdate, 0)
+	return updates
+}
@@ -0,0 +1,99 @@
+package engine
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderBookOrderFeed(t *testing.T) {
+	Convey("Given an order book with two buy orders at the same price", t, func() {
+		book := NewOrderBook("btcusd", 8, 8)
+		events := make([]model.Event, 0, 5)
+		book.Process(model.NewOrder(1, uint64(100000000), uint64(100000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		book.Process(model.NewOrder(2, uint64(100000000), uint64(200000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		added := book.FlushOrderFeed()
+		events = events[0:0]
+
+		Convey("Each order should generate an add update with consecutive sequence numbers", func() {
+			So(len(added), ShouldEqual, 2)
+			So(added[0].Action, ShouldEqual, model.OrderFeedAction_AddOrder)
+			So(added[0].OrderID, ShouldEqual, 1)
+			So(added[0].SeqID, ShouldEqual, 1)
+			So(added[0].EventSeqID, ShouldEqual, 1)
+			So(added[1].OrderID, ShouldEqual, 2)
+			So(added[1].SeqID, ShouldEqual, 2)
+			So(added[1].Remaining, ShouldEqual, 200000000)
+			So(book.FlushOrderFeed(), ShouldBeEmpty)
+		})
+
+		Convey("A sell order should execute the orders in their priority and add the rest in the book", func() {
+			book.Process(model.NewOrder(3, uint64(100000000), uint64(400000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			updates := book.FlushOrderFeed()
+			So(len(updates), ShouldEqual, 3)
+			So(updates[0].Action, ShouldEqual, model.OrderFeedAction_ExecuteOrder)
+			So(updates[0].OrderID, ShouldEqual, 1)
+			So(updates[0].Amount, ShouldEqual, 100000000)
+			So(updates[0].Remaining, ShouldEqual, 0)
+			So(updates[0].TradeSeqID, ShouldEqual, 1)
+			So(updates[1].Action, ShouldEqual, model.OrderFeedAction_ExecuteOrder)
+			So(updates[1].OrderID, ShouldEqual, 2)
+			So(updates[1].TradeSeqID, ShouldEqual, 2)
+			So(updates[2].Action, ShouldEqual, model.OrderFeedAction_AddOrder)
+			So(updates[2].OrderID, ShouldEqual, 3)
+			So(updates[2].Side, ShouldEqual, model.MarketSide_Sell)
+			So(updates[2].Remaining, ShouldEqual, 100000000)
+			So(updates[2].SeqID, ShouldEqual, 5)
+		})
+
+		Convey("A partial execution followed by a cancel should generate an execute and a delete update", func() {
+			book.Process(model.NewOrder(3, uint64(100000000), uint64(50000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			book.Cancel(model.NewOrder(1, uint64(100000000), 0, model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_CancelOrder), &events)
+			updates := book.FlushOrderFeed()
+			So(len(updates), ShouldEqual, 2)
+			So(updates[0].Action, ShouldEqual, model.OrderFeedAction_ExecuteOrder)
+			So(updates[0].Remaining, ShouldEqual, 50000000)
+			So(updates[1].Action, ShouldEqual, model.OrderFeedAction_DeleteOrder)
+			So(updates[1].OrderID, ShouldEqual, 1)
+			So(updates[1].Amount, ShouldEqual, 50000000)
+		})
+
+		Convey("A restored trade should modify the order while keeping its priority", func() {
+			book.Process(model.NewOrder(3, uint64(100000000), uint64(50000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			book.FlushOrderFeed()
+			book.SetTradeOperators([]uint64{99})
+			book.Process(model.Order{ID: 4, OwnerID: 99, EventType: model.CommandType_TradeBust, TradeSeqID: 1, RestoreOrders: true}, &events)
+			updates := book.FlushOrderFeed()
+			So(len(updates), ShouldEqual, 1)
+			So(updates[0].Action, ShouldEqual, model.OrderFeedAction_ModifyOrder)
+			So(updates[0].Remaining, ShouldEqual, 100000000)
+			So(book.GetOrderFeedSnapshot().Bids[0].OrderID, ShouldEqual, 1)
+		})
+
+		Convey("The snapshot should contain the orders in their priority", func() {
+			book.Process(model.NewOrder(3, uint64(110000000), uint64(300000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			book.Process(model.NewOrder(4, uint64(90000000), uint64(300000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			book.FlushOrderFeed()
+			snapshot := book.GetOrderFeedSnapshot()
+			So(snapshot.SeqID, ShouldEqual, 4)
+			So(snapshot.EventSeqID, ShouldEqual, book.GetLastEventSeqID())
+			So(len(snapshot.Bids), ShouldEqual, 3)
+			So(snapshot.Bids[0].OrderID, ShouldEqual, 1)
+			So(snapshot.Bids[1].OrderID, ShouldEqual, 2)
+			So(snapshot.Bids[2].OrderID, ShouldEqual, 4)
+			So(len(snapshot.Asks), ShouldEqual, 1)
+			So(snapshot.Asks[0].Remaining, ShouldEqual, 300000000)
+		})
+
+		Convey("The sequence should continue after the market is loaded from a backup", func() {
+			restored := NewOrderBook("btcusd", 8, 8)
+			restored.Load(book.Backup())
+			So(restored.FlushOrderFeed(), ShouldBeEmpty)
+			So(restored.GetOrderFeedSnapshot().SeqID, ShouldEqual, 2)
+			restored.Process(model.NewOrder(3, uint64(90000000), uint64(100000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			So(restored.FlushOrderFeed()[0].SeqID, ShouldEqual, 3)
+		})
+	})
+}
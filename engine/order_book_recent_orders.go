@@ -0,0 +1,122 @@
+package engine
+
+import (
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+/**
+Duplicate Orders
+================
+
+The transports used to send orders to the engine offer at least once delivery, so the same order can be
+received more than once, for example after a gateway retries a request that already reached the market.
+
+To keep the order book consistent the engine keeps a bounded window with the orders last received by the market
+along with the acknowledgement generated for each of them. When a new order is received with an ID found in the window:
+- if the payload matches the original order then the original acknowledgement is replayed and the order is ignored.
+  The replayed acknowledgement is marked as a replay since it keeps the sequence id of the original event and is
+  not part of the sequence of events generated by the market.
+- otherwise the order is rejected with a DuplicateOrder error
+
+The window is saved in the market backup so that duplicates are still detected after the market is restarted
+and the orders received since the last backup are replayed.
+*/
+
+// DefaultRecentOrdersWindow is the number of orders kept by default to detect duplicate submissions
+const DefaultRecentOrdersWindow = 10000
+
+// recentOrder is an order received by the market along with the acknowledgement event generated for it
+type recentOrder struct {
+	Order model.Order
+	Ack   model.Event
+}
+
+// recentOrders is a fixed size window with the last orders received by the market
+type recentOrders struct {
+	entries []recentOrder
+	index   map[uint64]int
+	next    int
+	count   int
+}
+
+func newRecentOrders(size int) *recentOrders {
+	if size <= 0 {
+		size = DefaultRecentOrdersWindow
+	}
+	return &recentOrders{
+		entries: make([]recentOrder, size),
+		index:   make(map[uint64]int, size),
+	}
+}
+
+// add a new order in the window and evict the oldest one if the window is full
+func (recent *recentOrders) add(order model.Order, ack model.Event) {
+	if recent.count == len(recent.entries) {
+		delete(recent.index, recent.entries[recent.next].Order.ID)
+	} else {
+		recent.count++
+	}
+	recent.entries[recent.next] = recentOrder{Order: order, Ack: ack}
+	recent.index[order.ID] = recent.next
+	recent.next = (recent.next + 1) % len(recent.entries)
+}
+
+// get a previously received order by ID
+func (recent *recentOrders) get(id uint64) (recentOrder, bool) {
+	position, ok := recent.index[id]
+	if !ok {
+		return recentOrder{}, false
+	}
+	return recent.entries[position], true
+}
+
+// list all orders in the window from the oldest to the newest
+func (recent *recentOrders) list() []recentOrder {
+	list := make([]recentOrder, 0, recent.count)
+	start := (recent.next - recent.count + len(recent.entries)) % len(recent.entries)
+	for i := 0; i < recent.count; i++ {
+		list = append(list, recent.entries[(start+i)%len(recent.entries)])
+	}
+	return list
+}
+
+// SetRecentOrdersWindow changes the number of orders kept to detect duplicate submissions
+func (book *orderBook) SetRecentOrdersWindow(size int) {
+	previous := book.RecentOrders.list()
+	book.RecentOrders = newRecentOrders(size)
+	for _, entry := range previous {
+		book.RecentOrders.add(entry.Order, entry.Ack)
+	}
+}
+
+// check if the order was already received by the market and append the events for the duplicate order
+// Returns true if the order is a duplicate and should not be processed
+func (book *orderBook) handleDuplicateOrder(order model.Order, events *[]model.Event) bool {
+	original, ok := book.RecentOrders.get(order.ID)
+	if !ok {
+		return false
+	}
+	if samePayload(original.Order, order) {
+		// replay the original acknowledgement with the same sequence id so consumers can ignore it
+		*events = append(*events, original.Ack)
+		(*events)[len(*events)-1].Replay = true
+		return true
+	}
+	book.AppendErrorEvent(events, model.ErrorCode_DuplicateOrder, order)
+	return true
+}
+
+// compare the fields sent by the user for two orders
+func samePayload(original, order model.Order) bool {
+	return original.EventType == order.EventType &&
+		original.Market == order.Market &&
+		original.Type == order.Type &&
+		original.Side == order.Side &&
+		original.Amount == order.Amount &&
+		original.Price == order.Price &&
+		original.Funds == order.Funds &&
+		original.Stop == order.Stop &&
+		original.StopPrice == order.StopPrice &&
+		original.OwnerID == order.OwnerID &&
+		original.ClientOrderID == order.ClientOrderID
+}
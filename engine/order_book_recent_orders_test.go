@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderBookDuplicateOrders(t *testing.T) {
+	Convey("Given an order book that already received an order", t, func() {
+		book := NewOrderBook("btcusd", 8, 8)
+		events := make([]model.Event, 0, 5)
+		order := model.NewOrder(1, uint64(100000000), uint64(12000000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder)
+		book.Process(order, &events)
+		ack := events[0]
+
+		Convey("The same order received again should replay the original acknowledgement", func() {
+			events = events[0:0]
+			book.Process(order, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].SeqID, ShouldEqual, ack.SeqID)
+			So(events[0].GetOrderStatus().ID, ShouldEqual, 1)
+			So(events[0].GetOrderStatus().Status, ShouldEqual, model.OrderStatus_Untouched)
+			So(events[0].Replay, ShouldBeTrue)
+			So(ack.Replay, ShouldBeFalse)
+			So(book.GetLastEventSeqID(), ShouldEqual, ack.SeqID)
+
+			Convey("And the replay should not change the acknowledgement kept for the order", func() {
+				events = events[0:0]
+				book.Process(order, &events)
+				So(events[0].Replay, ShouldBeTrue)
+				So(book.Backup().RecentOrders[0].Ack.Replay, ShouldBeFalse)
+			})
+			So(book.GetMarket()[0].Len(), ShouldEqual, 1)
+		})
+
+		Convey("An order with the same ID and a different payload should be rejected", func() {
+			events = events[0:0]
+			book.Process(model.NewOrder(1, uint64(110000000), uint64(12000000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].Type, ShouldEqual, model.EventType_Error)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_DuplicateOrder)
+			So(book.GetLowestAsk(), ShouldEqual, 0)
+		})
+
+		Convey("Duplicates should still be detected after the order is filled", func() {
+			book.Process(model.NewOrder(2, uint64(100000000), uint64(12000000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			events = events[0:0]
+			book.Process(order, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].SeqID, ShouldEqual, ack.SeqID)
+			So(book.GetHighestBid(), ShouldEqual, 0)
+		})
+
+		Convey("Duplicates should be detected after the market is loaded from a backup", func() {
+			restored := NewOrderBook("btcusd", 8, 8)
+			restored.Load(book.Backup())
+			events = events[0:0]
+			restored.Process(order, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].SeqID, ShouldEqual, ack.SeqID)
+			So(restored.GetMarket()[0].Len(), ShouldEqual, 1)
+		})
+
+		Convey("Orders evicted from the window should no longer be detected", func() {
+			book.SetRecentOrdersWindow(2)
+			book.Process(model.NewOrder(2, uint64(90000000), uint64(100000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			book.Process(model.NewOrder(3, uint64(90000000), uint64(100000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			So(len(book.Backup().RecentOrders), ShouldEqual, 2)
+			events = events[0:0]
+			book.Process(model.NewOrder(1, uint64(90000000), uint64(100000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].Type, ShouldEqual, model.EventType_OrderStatusChange)
+			So(events[0].SeqID, ShouldBeGreaterThan, ack.SeqID)
+		})
+	})
+}
@@ -83,6 +83,7 @@ func (book *orderBook) addStopOrder(order model.Order) {
 	if order.Stop == model.StopLoss_None || order.StopPrice == 0 {
 		return
 	}
+	book.registerClientOrder(order)
 	switch order.Stop {
 	case model.StopLoss_Loss:
 		book.StopLossOrders.addOrder(order.StopPrice, order)
@@ -177,8 +178,10 @@ func (book *orderBook) activateStopEntryOrders(lastPrice uint64, events *[]model
 		pricePoint := iterator.Value()
 		*orders = append(*orders, pricePoint.Entries...)
 		for _, order := range pricePoint.Entries {
+			// activated orders are registered again if they are added in the order book
+			book.unregisterClientOrder(order)
 			book.LastEventSeqID++
-			*events = append(*events, model.NewOrderActivatedEvent(book.LastEventSeqID, order.Market, order.Type, order.Side, order.ID, order.OwnerID, order.Price, order.Amount, order.Funds, order.Status))
+			*events = append(*events, model.NewOrderActivatedEvent(book.LastEventSeqID, order.Market, order.Type, order.Side, order.ID, order.OwnerID, order.ClientOrderID, order.Price, order.Amount, order.Funds, order.Status))
 		}
 		book.StopEntryOrders.Delete(price)
 
@@ -207,8 +210,10 @@ func (book *orderBook) activateStopLossOrders(lastPrice uint64, events *[]model.
 		pricePoint := iterator.Value()
 		*orders = append(*orders, pricePoint.Entries...)
 		for _, order := range pricePoint.Entries {
+			// activated orders are registered again if they are added in the order book
+			book.unregisterClientOrder(order)
 			book.LastEventSeqID++
-			*events = append(*events, model.NewOrderActivatedEvent(book.LastEventSeqID, order.Market, order.Type, order.Side, order.ID, order.OwnerID, order.Price, order.Amount, order.Funds, order.Status))
+			*events = append(*events, model.NewOrderActivatedEvent(book.LastEventSeqID, order.Market, order.Type, order.Side, order.ID, order.OwnerID, order.ClientOrderID, order.Price, order.Amount, order.Funds, order.Status))
 		}
 		book.StopLossOrders.Delete(price)
 
@@ -127,7 +127,7 @@ func TestOrderBookProcessing(t *testing.T) {
 		// 1.20 130     1.30 10
 		// 1.11 20
 		Convey("Add two sell orders at the same price without matching", func() {
-			book.Process(model.NewOrder(7, uint64(130000000), uint64(1000000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+			book.Process(model.NewOrder(70, uint64(130000000), uint64(1000000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
 			So(len(events), ShouldEqual, 1)
 			events = events[0:0]
 			book.Process(model.NewOrder(8, uint64(130000000), uint64(1000000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
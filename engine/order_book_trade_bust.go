@@ -0,0 +1,283 @@
+package engine
+
+import (
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/utils"
+)
+
+/**
+Trade Bust and Correction
+=========================
+
+Operators can reverse an erroneous trade with a TradeBust command or change its price or amount with a
+TradeCorrect command. Both commands reference the trade by its sequence id and are processed in the same
+stream as the orders, so the correction is recorded in the event stream together with the trades it changes:
+- a bust generates a TradeBusted event with the trade as it was last reported
+- a correction generates a TradeCorrected event with the original and the corrected trade, the corrected trade
+  keeps the sequence id of the original one and can itself be busted or corrected later
+
+Only the operators configured for the market can bust or correct trades. The operator is identified by the OwnerID
+of the command and commands sent by any other owner are rejected with an UnauthorizedOperator error.
+
+The engine keeps a bounded window with the trades last generated by the market along with the prices of the
+matched orders. Trades outside of the window or already busted are rejected with an UnknownTrade error.
+
+When the RestoreOrders flag is set on the command, the busted amount is added back to the orders of the trade that
+are still resting in the order book and an order status event is generated for each of them. Orders that were
+completely filled or cancelled in the meantime are no longer in the order book and are not restored.
+
+The trade sequence id is not changed by a bust or correction and the window is saved in the market backup so that
+the commands can still be applied after the market is restarted.
+*/
+
+// DefaultRecentTradesWindow is the number of trades kept by default that can be busted or corrected
+const DefaultRecentTradesWindow = 10000
+
+// recentTrade is a trade generated by the market along with the limit prices of the matched orders
+type recentTrade struct {
+	Trade    model.Trade
+	AskPrice uint64
+	BidPrice uint64
+}
+
+// recentTrades is a fixed size window with the last trades generated by the market
+type recentTrades struct {
+	entries []recentTrade
+	index   map[uint64]int
+	next    int
+	count   int
+}
+
+func newRecentTrades(size int) *recentTrades {
+	if size <= 0 {
+		size = DefaultRecentTradesWindow
+	}
+	return &recentTrades{
+		entries: make([]recentTrade, size),
+		index:   make(map[uint64]int, size),
+	}
+}
+
+// add a new trade in the window and evict the oldest one if the window is full
+func (recent *recentTrades) add(entry recentTrade) {
+	if recent.count == len(recent.entries) {
+		evicted := recent.entries[recent.next].Trade.SeqID
+		if position, ok := recent.index[evicted]; ok && position == recent.next {
+			delete(recent.index, evicted)
+		}
+	} else {
+		recent.count++
+	}
+	recent.entries[recent.next] = entry
+	recent.index[entry.Trade.SeqID] = recent.next
+	recent.next = (recent.next + 1) % len(recent.entries)
+}
+
+// get a trade by sequence id
+func (recent *recentTrades) get(seqID uint64) (recentTrade, bool) {
+	position, ok := recent.index[seqID]
+	if !ok {
+		return recentTrade{}, false
+	}
+	return recent.entries[position], true
+}
+
+// update a trade in the window after it was corrected
+func (recent *recentTrades) update(entry recentTrade) {
+	if position, ok := recent.index[entry.Trade.SeqID]; ok {
+		recent.entries[position] = entry
+	}
+}
+
+// remove a busted trade from the window
+func (recent *recentTrades) remove(seqID uint64) {
+	delete(recent.index, seqID)
+}
+
+// list all trades in the window that were not busted from the oldest to the newest
+func (recent *recentTrades) list() []recentTrade {
+	list := make([]recentTrade, 0, len(recent.index))
+	start := (recent.next - recent.count + len(recent.entries)) % len(recent.entries)
+	for i := 0; i < recent.count; i++ {
+		position := (start + i) % len(recent.entries)
+		entry := recent.entries[position]
+		if current, ok := recent.index[entry.Trade.SeqID]; ok && current == position {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// first returns the sequence id of the oldest trade in the window, busted or not, or 0 if the window is empty
+func (recent *recentTrades) first() uint64 {
+	if recent.count == 0 {
+		return 0
+	}
+	start := (recent.next - recent.count + len(recent.entries)) % len(recent.entries)
+	return recent.entries[start].Trade.SeqID
+}
+
+// GetRecentTrades returns the trades of the window that were not busted, from the oldest to the newest, along with
+// the sequence id of the oldest trade of the window. Every trade generated since that one is either in the list or
+// was busted.
+func (book *orderBook) GetRecentTrades() ([]model.Trade, uint64) {
+	entries := book.RecentTrades.list()
+	trades := make([]model.Trade, len(entries))
+	for i, entry := range entries {
+		trades[i] = entry.Trade
+	}
+	return trades, book.RecentTrades.first()
+}
+
+// SetRecentTradesWindow changes the number of trades kept that can be busted or corrected
+func (book *orderBook) SetRecentTradesWindow(size int) {
+	previous := book.RecentTrades.list()
+	book.RecentTrades = newRecentTrades(size)
+	for _, entry := range previous {
+		book.RecentTrades.add(entry)
+	}
+}
+
+// SetTradeOperators changes the operators allowed to bust or correct trades
+func (book *orderBook) SetTradeOperators(operators []uint64) {
+	book.TradeOperators = make(map[uint64]bool, len(operators))
+	for _, operatorID := range operators {
+		book.TradeOperators[operatorID] = true
+	}
+}
+
+// reject the trade commands sent by an owner that is not an operator of the market
+// Returns true if the command was rejected
+func (book *orderBook) rejectUnauthorizedOperator(command model.Order, events *[]model.Event) bool {
+	if book.TradeOperators[command.OwnerID] {
+		return false
+	}
+	book.AppendErrorEvent(events, model.ErrorCode_UnauthorizedOperator, command)
+	return true
+}
+
+// BustTrade reverses a trade previously generated by the market
+func (book *orderBook) BustTrade(command model.Order, events *[]model.Event) {
+	if book.rejectUnknownMarket(command, events) || book.rejectUnauthorizedOperator(command, events) {
+		return
+	}
+	entry, ok := book.RecentTrades.get(command.TradeSeqID)
+	if !ok {
+		book.AppendErrorEvent(events, model.ErrorCode_UnknownTrade, command)
+		return
+	}
+	book.RecentTrades.remove(command.TradeSeqID)
+
+	var restored []model.Order
+	if command.RestoreOrders {
+		funds := utils.Multiply(entry.Trade.Amount, entry.Trade.Price, book.VolumePrecision, book.PricePrecision, book.PricePrecision)
+		restored = book.restoreTradeOrders(entry, entry.Trade.Amount, funds, 0)
+	}
+
+	book.LastEventSeqID++
+	*events = append(*events, model.NewTradeBustEvent(book.LastEventSeqID, book.MarketID, command.ID, command.OwnerID, entry.Trade, restoredOrderIDs(restored)))
+	for _, order := range restored {
+		book.appendOrderStatusEvent(events, order, model.LiquidityFlag_NotFilled)
+	}
+}
+
+// CorrectTrade changes the price or the amount of a trade previously generated by the market
+func (book *orderBook) CorrectTrade(command model.Order, events *[]model.Event) {
+	if book.rejectUnknownMarket(command, events) || book.rejectUnauthorizedOperator(command, events) {
+		return
+	}
+	entry, ok := book.RecentTrades.get(command.TradeSeqID)
+	if !ok {
+		book.AppendErrorEvent(events, model.ErrorCode_UnknownTrade, command)
+		return
+	}
+	original := entry.Trade
+	corrected := entry.Trade
+	if command.Price != 0 {
+		corrected.Price = command.Price
+	}
+	if command.Amount != 0 {
+		corrected.Amount = command.Amount
+	}
+	// a correction can only reduce the traded amount since the orders may no longer have anything left to fill
+	if corrected.Amount == 0 || corrected.Amount > original.Amount {
+		book.AppendErrorEvent(events, model.ErrorCode_InvalidCorrection, command)
+		return
+	}
+	corrected.AskRemaining += original.Amount - corrected.Amount
+	corrected.BidRemaining += original.Amount - corrected.Amount
+	entry.Trade = corrected
+	book.RecentTrades.update(entry)
+
+	var restored []model.Order
+	if command.RestoreOrders {
+		originalFunds := utils.Multiply(original.Amount, original.Price, book.VolumePrecision, book.PricePrecision, book.PricePrecision)
+		correctedFunds := utils.Multiply(corrected.Amount, corrected.Price, book.VolumePrecision, book.PricePrecision, book.PricePrecision)
+		restored = book.restoreTradeOrders(entry, original.Amount-corrected.Amount, originalFunds, correctedFunds)
+	}
+
+	book.LastEventSeqID++
+	*events = append(*events, model.NewTradeCorrectEvent(book.LastEventSeqID, book.MarketID, command.ID, command.OwnerID, original, corrected, restoredOrderIDs(restored)))
+	for _, order := range restored {
+		book.appendOrderStatusEvent(events, order, model.LiquidityFlag_NotFilled)
+	}
+}
+
+// restore the amount and funds on the orders of the trade that are still resting in the order book
+// Returns the restored orders
+func (book *orderBook) restoreTradeOrders(entry recentTrade, amount, funds, correctedFunds uint64) []model.Order {
+	restored := make([]model.Order, 0, 2)
+	if order, ok := book.restoreBookEntry(book.SellEntries, entry.Trade.AskID, entry.AskPrice, amount, funds, correctedFunds); ok {
+		book.Depth.add(model.MarketSide_Sell, order.Price, amount)
+		book.appendOrderFeedUpdate(model.OrderFeedAction_ModifyOrder, order, amount, order.GetUnfilledAmount(), 0)
+		restored = append(restored, order)
+	}
+	if order, ok := book.restoreBookEntry(book.BuyEntries, entry.Trade.BidID, entry.BidPrice, amount, funds, correctedFunds); ok {
+		book.Depth.add(model.MarketSide_Buy, order.Price, amount)
+		book.appendOrderFeedUpdate(model.OrderFeedAction_ModifyOrder, order, amount, order.GetUnfilledAmount(), 0)
+		restored = append(restored, order)
+	}
+	return restored
+}
+
+// restore the amount and funds on a single order found in the given side of the order book
+func (book *orderBook) restoreBookEntry(entries *SkipList, id, price, amount, funds, correctedFunds uint64) (model.Order, bool) {
+	if price == 0 {
+		return model.Order{}, false
+	}
+	iterator := entries.Seek(price)
+	if iterator == nil {
+		return model.Order{}, false
+	}
+	defer iterator.Close()
+	if iterator.Key() != price {
+		return model.Order{}, false
+	}
+	pricePoint := iterator.Value()
+	for i := 0; i < len(pricePoint.Entries); i++ {
+		order := &pricePoint.Entries[i]
+		if order.ID != id {
+			continue
+		}
+		if order.FilledAmount < amount || order.UsedFunds < funds {
+			return model.Order{}, false
+		}
+		order.FilledAmount -= amount
+		order.UsedFunds = order.UsedFunds - funds + correctedFunds
+		if order.FilledAmount == 0 {
+			order.Status = model.OrderStatus_Untouched
+		} else {
+			order.Status = model.OrderStatus_PartiallyFilled
+		}
+		return *order, true
+	}
+	return model.Order{}, false
+}
+
+func restoredOrderIDs(orders []model.Order) []uint64 {
+	ids := make([]uint64, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+	}
+	return ids
+}
@@ -0,0 +1,154 @@
+package engine
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newTradeCommand(id uint64, command model.CommandType, tradeSeqID uint64, restore bool) model.Order {
+	return model.Order{ID: id, OwnerID: 99, EventType: command, TradeSeqID: tradeSeqID, RestoreOrders: restore}
+}
+
+func TestOrderBookTradeBust(t *testing.T) {
+	Convey("Given an order book with a partially filled sell order", t, func() {
+		book := NewOrderBook("btcusd", 8, 8)
+		book.SetTradeOperators([]uint64{99})
+		events := make([]model.Event, 0, 5)
+		book.Process(model.NewOrder(1, uint64(100000000), uint64(1000000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		book.Process(model.NewOrder(2, uint64(100000000), uint64(400000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+		trade := *events[2].GetTrade()
+		lastTradeSeqID := book.GetLastTradeSeqID()
+		events = events[0:0]
+
+		Convey("Trade commands from an owner that is not an operator should be rejected", func() {
+			bust := newTradeCommand(100, model.CommandType_TradeBust, trade.SeqID, true)
+			bust.OwnerID = 20
+			book.Process(bust, &events)
+			correct := newTradeCommand(101, model.CommandType_TradeCorrect, trade.SeqID, true)
+			correct.OwnerID = 20
+			correct.Amount = 100000000
+			book.Process(correct, &events)
+			So(len(events), ShouldEqual, 2)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_UnauthorizedOperator)
+			So(events[1].GetError().Code, ShouldEqual, model.ErrorCode_UnauthorizedOperator)
+
+			Convey("and the trade should still be available to the operators", func() {
+				events = events[0:0]
+				book.Process(newTradeCommand(102, model.CommandType_TradeBust, trade.SeqID, false), &events)
+				So(events[0].Type, ShouldEqual, model.EventType_TradeBusted)
+			})
+		})
+
+		Convey("A bust without restoring orders should only report the busted trade", func() {
+			command := newTradeCommand(100, model.CommandType_TradeBust, trade.SeqID, false)
+			So(command.Valid(), ShouldBeTrue)
+			book.Process(command, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].Type, ShouldEqual, model.EventType_TradeBusted)
+			So(events[0].GetTradeBust().RequestID, ShouldEqual, 100)
+			So(events[0].GetTradeBust().OperatorID, ShouldEqual, 99)
+			So(events[0].GetTradeBust().Trade.SeqID, ShouldEqual, trade.SeqID)
+			So(events[0].GetTradeBust().Trade.Amount, ShouldEqual, 400000000)
+			So(events[0].GetTradeBust().RestoredOrderIDs, ShouldBeEmpty)
+			So(book.GetLastTradeSeqID(), ShouldEqual, lastTradeSeqID)
+
+			Convey("and the busted trade should no longer be listed in the recent trades", func() {
+				trades, fromSeqID := book.GetRecentTrades()
+				So(trades, ShouldBeEmpty)
+				So(fromSeqID, ShouldEqual, trade.SeqID)
+			})
+
+			Convey("and the same trade can not be busted again", func() {
+				events = events[0:0]
+				book.Process(newTradeCommand(101, model.CommandType_TradeBust, trade.SeqID, false), &events)
+				So(len(events), ShouldEqual, 1)
+				So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_UnknownTrade)
+			})
+		})
+
+		Convey("A bust with restoring orders should add the amount back to the resting order", func() {
+			book.Process(newTradeCommand(100, model.CommandType_TradeBust, trade.SeqID, true), &events)
+			So(len(events), ShouldEqual, 2)
+			So(events[0].GetTradeBust().RestoredOrderIDs, ShouldResemble, []uint64{1})
+			So(events[1].GetOrderStatus().ID, ShouldEqual, 1)
+			So(events[1].GetOrderStatus().FilledAmount, ShouldEqual, 0)
+			So(events[1].GetOrderStatus().UsedFunds, ShouldEqual, 0)
+			So(events[1].GetOrderStatus().Status, ShouldEqual, model.OrderStatus_Untouched)
+			So(events[1].SeqID, ShouldEqual, events[0].SeqID+1)
+
+			Convey("and the restored amount should be available for new orders", func() {
+				events = events[0:0]
+				book.Process(model.NewOrder(3, uint64(100000000), uint64(1000000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder), &events)
+				So(events[1].GetTrade().Amount, ShouldEqual, 1000000000)
+				So(book.GetLowestAsk(), ShouldEqual, 0)
+			})
+		})
+
+		Convey("A correction should keep the trade sequence id and restore the difference", func() {
+			command := newTradeCommand(100, model.CommandType_TradeCorrect, trade.SeqID, true)
+			command.Amount = 300000000
+			command.Price = 90000000
+			So(command.Valid(), ShouldBeTrue)
+			book.Process(command, &events)
+			So(len(events), ShouldEqual, 2)
+			correction := events[0].GetTradeCorrect()
+			So(events[0].Type, ShouldEqual, model.EventType_TradeCorrected)
+			So(correction.Original.Amount, ShouldEqual, 400000000)
+			So(correction.Original.Price, ShouldEqual, 100000000)
+			So(correction.Corrected.SeqID, ShouldEqual, trade.SeqID)
+			So(correction.Corrected.Amount, ShouldEqual, 300000000)
+			So(correction.Corrected.Price, ShouldEqual, 90000000)
+			So(correction.Corrected.AskRemaining, ShouldEqual, 700000000)
+			So(events[1].GetOrderStatus().FilledAmount, ShouldEqual, 300000000)
+			So(events[1].GetOrderStatus().UsedFunds, ShouldEqual, 270000000)
+			So(events[1].GetOrderStatus().Status, ShouldEqual, model.OrderStatus_PartiallyFilled)
+
+			Convey("and a later bust should reverse the corrected trade", func() {
+				events = events[0:0]
+				book.Process(newTradeCommand(101, model.CommandType_TradeBust, trade.SeqID, true), &events)
+				So(events[0].GetTradeBust().Trade.Amount, ShouldEqual, 300000000)
+				So(events[1].GetOrderStatus().FilledAmount, ShouldEqual, 0)
+				So(events[1].GetOrderStatus().UsedFunds, ShouldEqual, 0)
+			})
+		})
+
+		Convey("The recent trades should list the trade as last corrected", func() {
+			trades, fromSeqID := book.GetRecentTrades()
+			So(trades, ShouldResemble, []model.Trade{trade})
+			So(fromSeqID, ShouldEqual, trade.SeqID)
+			command := newTradeCommand(100, model.CommandType_TradeCorrect, trade.SeqID, false)
+			command.Amount = 300000000
+			book.Process(command, &events)
+			trades, _ = book.GetRecentTrades()
+			So(trades[0].Amount, ShouldEqual, 300000000)
+		})
+
+		Convey("A correction that increases the amount should be rejected", func() {
+			command := newTradeCommand(100, model.CommandType_TradeCorrect, trade.SeqID, false)
+			command.Amount = 500000000
+			book.Process(command, &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_InvalidCorrection)
+		})
+
+		Convey("Unknown trades should be rejected", func() {
+			book.Process(newTradeCommand(100, model.CommandType_TradeBust, 1000, false), &events)
+			So(len(events), ShouldEqual, 1)
+			So(events[0].GetError().Code, ShouldEqual, model.ErrorCode_UnknownTrade)
+		})
+
+		Convey("Trades should be busted after the market is loaded from a backup", func() {
+			restored := NewOrderBook("btcusd", 8, 8)
+			restored.SetTradeOperators([]uint64{99})
+			restored.Load(book.Backup())
+			restored.Process(newTradeCommand(100, model.CommandType_TradeBust, trade.SeqID, true), &events)
+			So(len(events), ShouldEqual, 2)
+			So(events[0].Type, ShouldEqual, model.EventType_TradeBusted)
+			So(restored.GetLastTradeSeqID(), ShouldEqual, lastTradeSeqID)
+			So(len(restored.Backup().RecentTrades), ShouldEqual, 0)
+		})
+	})
+}
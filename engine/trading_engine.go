@@ -55,6 +55,8 @@ func (ngin *tradingEngine) ProcessEvent(order model.Order, events *[]model.Event
 		ngin.Process(order, events)
 	case model.CommandType_CancelOrder:
 		ngin.CancelOrder(order, events)
+	case model.CommandType_TradeBust, model.CommandType_TradeCorrect, model.CommandType_CancelAll:
+		ngin.Process(order, events)
 	default:
 		return nil
 	}
@@ -0,0 +1,110 @@
+package fix
+
+import (
+	"bufio"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// logonTimeout is the time a new connection has to send its logon message
+const logonTimeout = 10 * time.Second
+
+// Acceptor listens for connections of FIX initiators and attaches them to the configured sessions
+type Acceptor struct {
+	address  string
+	listener net.Listener
+	lock     sync.Mutex
+	sessions map[SessionID]*Session
+}
+
+// NewAcceptor creates an acceptor that listens on the given address
+func NewAcceptor(address string) *Acceptor {
+	return &Acceptor{address: address, sessions: make(map[SessionID]*Session)}
+}
+
+// AddSession allows the counterparty of the session to log on
+func (acceptor *Acceptor) AddSession(session *Session) {
+	acceptor.lock.Lock()
+	defer acceptor.lock.Unlock()
+	acceptor.sessions[session.ID] = session
+}
+
+// Start listening for connections
+func (acceptor *Acceptor) Start() error {
+	listener, err := net.Listen("tcp", acceptor.address)
+	if err != nil {
+		return err
+	}
+	acceptor.listener = listener
+	go acceptor.acceptConnections()
+	return nil
+}
+
+// Addr returns the address on which the acceptor listens
+func (acceptor *Acceptor) Addr() net.Addr {
+	return acceptor.listener.Addr()
+}
+
+// Stop listening for connections and log out the connected sessions
+func (acceptor *Acceptor) Stop() error {
+	err := acceptor.listener.Close()
+	acceptor.lock.Lock()
+	defer acceptor.lock.Unlock()
+	for _, session := range acceptor.sessions {
+		if session.IsLoggedOn() {
+			session.Logout("Server shutting down")
+		}
+	}
+	return err
+}
+
+func (acceptor *Acceptor) acceptConnections() {
+	for {
+		conn, err := acceptor.listener.Accept()
+		if err != nil {
+			log.Debug().Err(err).Str("section", "fix").Str("action", "accept").Str("address", acceptor.address).Msg("Acceptor closed")
+			return
+		}
+		go acceptor.handleConnection(conn)
+	}
+}
+
+// handleConnection waits for the logon message of a new connection and attaches it to its session
+// - Connections with invalid credentials are closed without changing the state of the session
+func (acceptor *Acceptor) handleConnection(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(logonTimeout))
+	raw, err := ReadMessage(reader)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+	msg, err := ParseMessage(raw)
+	if err != nil || msg.Type() != MsgTypeLogon {
+		log.Warn().Str("section", "fix").Str("action", "accept").Str("remote", conn.RemoteAddr().String()).Msg("First message is not a valid logon")
+		conn.Close()
+		return
+	}
+	id := SessionID{SenderCompID: msg.Get(TagTargetCompID), TargetCompID: msg.Get(TagSenderCompID)}
+	acceptor.lock.Lock()
+	session, ok := acceptor.sessions[id]
+	acceptor.lock.Unlock()
+	if !ok {
+		log.Warn().Str("section", "fix").Str("action", "accept").Str("session", id.String()).Msg("Unknown session")
+		conn.Close()
+		return
+	}
+	if !session.authenticate(msg) {
+		log.Warn().Str("section", "fix").Str("action", "accept").Str("session", id.String()).Str("remote", conn.RemoteAddr().String()).Msg("Invalid logon credentials")
+		conn.Close()
+		return
+	}
+	if err := session.accept(conn, reader, msg); err != nil {
+		log.Warn().Err(err).Str("section", "fix").Str("action", "accept").Str("session", id.String()).Msg("Rejecting connection")
+		conn.Close()
+	}
+}
@@ -0,0 +1,299 @@
+package fix
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// BeginString is the only version of the protocol supported by the package
+const BeginString = "FIX.4.4"
+
+// soh is the delimiter of the fields of a message
+const soh = '\x01'
+
+// maxBodyLength limits the size of the messages accepted from a connection
+const maxBodyLength = 64 * 1024
+
+// Message types
+const (
+	MsgTypeHeartbeat                 = "0"
+	MsgTypeTestRequest               = "1"
+	MsgTypeResendRequest             = "2"
+	MsgTypeReject                    = "3"
+	MsgTypeSequenceReset             = "4"
+	MsgTypeLogout                    = "5"
+	MsgTypeExecutionReport           = "8"
+	MsgTypeOrderCancelReject         = "9"
+	MsgTypeLogon                     = "A"
+	MsgTypeNewOrderSingle            = "D"
+	MsgTypeOrderCancelRequest        = "F"
+	MsgTypeOrderCancelReplaceRequest = "G"
+	MsgTypeBusinessMessageReject     = "j"
+	MsgTypeOrderMassCancelRequest    = "q"
+	MsgTypeOrderMassCancelReport     = "r"
+)
+
+// Field tags
+const (
+	TagAccount               = 1
+	TagAvgPx                 = 6
+	TagBeginSeqNo            = 7
+	TagBeginString           = 8
+	TagBodyLength            = 9
+	TagCheckSum              = 10
+	TagClOrdID               = 11
+	TagCumQty                = 14
+	TagEndSeqNo              = 16
+	TagExecID                = 17
+	TagLastPx                = 31
+	TagLastQty               = 32
+	TagMsgSeqNum             = 34
+	TagMsgType               = 35
+	TagNewSeqNo              = 36
+	TagOrderID               = 37
+	TagOrderQty              = 38
+	TagOrdStatus             = 39
+	TagOrdType               = 40
+	TagOrigClOrdID           = 41
+	TagPossDupFlag           = 43
+	TagPrice                 = 44
+	TagRefSeqNum             = 45
+	TagSenderCompID          = 49
+	TagSendingTime           = 52
+	TagSide                  = 54
+	TagSymbol                = 55
+	TagTargetCompID          = 56
+	TagText                  = 58
+	TagTransactTime          = 60
+	TagEncryptMethod         = 98
+	TagStopPx                = 99
+	TagCxlRejReason          = 102
+	TagOrdRejReason          = 103
+	TagHeartBtInt            = 108
+	TagTestReqID             = 112
+	TagOrigSendingTime       = 122
+	TagGapFillFlag           = 123
+	TagResetSeqNumFlag       = 141
+	TagExecType              = 150
+	TagLeavesQty             = 151
+	TagCashOrderQty          = 152
+	TagRefMsgType            = 372
+	TagSessionRejectReason   = 373
+	TagBusinessRejectReason  = 380
+	TagCxlRejResponseTo      = 434
+	TagMassCancelRequestType = 530
+	TagMassCancelResponse    = 531
+	TagTotalAffectedOrders   = 533
+	TagUsername              = 553
+	TagPassword              = 554
+)
+
+// headerTags are written at the start of every message in this order
+var headerTags = []int{TagMsgType, TagSenderCompID, TagTargetCompID, TagMsgSeqNum, TagPossDupFlag, TagSendingTime, TagOrigSendingTime}
+
+// ErrInvalidMessage is returned when a message does not respect the format of the protocol
+var ErrInvalidMessage = errors.New("invalid FIX message")
+
+// Field is a tag and value pair of a message
+type Field struct {
+	Tag   int
+	Value string
+}
+
+// Message is a FIX message without the BeginString, BodyLength and CheckSum fields which are computed when the
+// message is encoded
+type Message struct {
+	Fields []Field
+}
+
+// NewMessage creates a new message of the given type
+func NewMessage(msgType string) *Message {
+	return &Message{Fields: []Field{{Tag: TagMsgType, Value: msgType}}}
+}
+
+// Type returns the type of the message
+func (msg *Message) Type() string {
+	return msg.Get(TagMsgType)
+}
+
+// Has checks if the message contains the given tag
+func (msg *Message) Has(tag int) bool {
+	for _, field := range msg.Fields {
+		if field.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the value of the tag or an empty string if the tag is not set
+func (msg *Message) Get(tag int) string {
+	for _, field := range msg.Fields {
+		if field.Tag == tag {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+// GetInt returns the value of the tag as an integer
+func (msg *Message) GetInt(tag int) (int, error) {
+	return strconv.Atoi(msg.Get(tag))
+}
+
+// Set the value of the tag replacing the existing value if any
+func (msg *Message) Set(tag int, value string) *Message {
+	for i := range msg.Fields {
+		if msg.Fields[i].Tag == tag {
+			msg.Fields[i].Value = value
+			return msg
+		}
+	}
+	msg.Fields = append(msg.Fields, Field{Tag: tag, Value: value})
+	return msg
+}
+
+// SetInt sets the value of the tag to the given integer
+func (msg *Message) SetInt(tag int, value int) *Message {
+	return msg.Set(tag, strconv.Itoa(value))
+}
+
+// Bytes encodes the message with the header fields first and computes the body length and the checksum
+func (msg *Message) Bytes() []byte {
+	var body bytes.Buffer
+	for _, tag := range headerTags {
+		if msg.Has(tag) {
+			writeField(&body, tag, msg.Get(tag))
+		}
+	}
+	for _, field := range msg.Fields {
+		if isHeaderTag(field.Tag) || field.Tag == TagBeginString || field.Tag == TagBodyLength || field.Tag == TagCheckSum {
+			continue
+		}
+		writeField(&body, field.Tag, field.Value)
+	}
+	var raw bytes.Buffer
+	writeField(&raw, TagBeginString, BeginString)
+	writeField(&raw, TagBodyLength, strconv.Itoa(body.Len()))
+	raw.Write(body.Bytes())
+	writeField(&raw, TagCheckSum, fmt.Sprintf("%03d", checksum(raw.Bytes())))
+	return raw.Bytes()
+}
+
+// String returns the encoded message with the delimiters replaced by "|" for logging
+func (msg *Message) String() string {
+	return string(bytes.ReplaceAll(msg.Bytes(), []byte{soh}, []byte{'|'}))
+}
+
+// ParseMessage decodes a message and validates its body length and checksum
+func ParseMessage(raw []byte) (*Message, error) {
+	if len(raw) == 0 || raw[len(raw)-1] != soh {
+		return nil, ErrInvalidMessage
+	}
+	msg := &Message{Fields: make([]Field, 0, 16)}
+	bodyStart, checksumStart := 0, 0
+	offset := 0
+	for offset < len(raw) {
+		end := bytes.IndexByte(raw[offset:], soh)
+		pair := raw[offset : offset+end]
+		separator := bytes.IndexByte(pair, '=')
+		if separator <= 0 {
+			return nil, ErrInvalidMessage
+		}
+		tag, err := strconv.Atoi(string(pair[:separator]))
+		if err != nil {
+			return nil, ErrInvalidMessage
+		}
+		value := string(pair[separator+1:])
+		position := len(msg.Fields)
+		switch {
+		case position == 0 && (tag != TagBeginString || value != BeginString):
+			return nil, ErrInvalidMessage
+		case position == 1 && tag != TagBodyLength:
+			return nil, ErrInvalidMessage
+		case position == 2 && tag != TagMsgType:
+			return nil, ErrInvalidMessage
+		}
+		if tag == TagCheckSum {
+			checksumStart = offset
+		}
+		offset += end + 1
+		if position == 1 {
+			bodyStart = offset
+		}
+		msg.Fields = append(msg.Fields, Field{Tag: tag, Value: value})
+		if tag == TagCheckSum {
+			break
+		}
+	}
+	if checksumStart == 0 || offset != len(raw) {
+		return nil, ErrInvalidMessage
+	}
+	if length, err := strconv.Atoi(msg.Fields[1].Value); err != nil || length != checksumStart-bodyStart {
+		return nil, ErrInvalidMessage
+	}
+	if fmt.Sprintf("%03d", checksum(raw[:checksumStart])) != msg.Get(TagCheckSum) {
+		return nil, ErrInvalidMessage
+	}
+	// keep only the fields that are not computed on encoding
+	msg.Fields = msg.Fields[2 : len(msg.Fields)-1]
+	return msg, nil
+}
+
+// ReadMessage reads the next message from the reader using the body length to find its end
+func ReadMessage(reader *bufio.Reader) ([]byte, error) {
+	begin, err := reader.ReadBytes(soh)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(begin, []byte("8="+BeginString+string(soh))) {
+		return nil, ErrInvalidMessage
+	}
+	length, err := reader.ReadBytes(soh)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(length, []byte("9=")) {
+		return nil, ErrInvalidMessage
+	}
+	bodyLength, err := strconv.Atoi(string(length[2 : len(length)-1]))
+	if err != nil || bodyLength <= 0 || bodyLength > maxBodyLength {
+		return nil, ErrInvalidMessage
+	}
+	// the body is followed by the checksum field which always has 3 digits: 10=NNN<SOH>
+	raw := make([]byte, len(begin)+len(length)+bodyLength+7)
+	copy(raw, begin)
+	copy(raw[len(begin):], length)
+	if _, err := io.ReadFull(reader, raw[len(begin)+len(length):]); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func isHeaderTag(tag int) bool {
+	for _, header := range headerTags {
+		if header == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func writeField(buffer *bytes.Buffer, tag int, value string) {
+	buffer.WriteString(strconv.Itoa(tag))
+	buffer.WriteByte('=')
+	buffer.WriteString(value)
+	buffer.WriteByte(soh)
+}
+
+func checksum(raw []byte) int {
+	sum := 0
+	for _, b := range raw {
+		sum += int(b)
+	}
+	return sum % 256
+}
@@ -0,0 +1,57 @@
+package fix_test
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/fix"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMessage(t *testing.T) {
+	Convey("Given a message", t, func() {
+		msg := fix.NewMessage(fix.MsgTypeHeartbeat).
+			Set(fix.TagSendingTime, "20200101-10:00:00.000").
+			Set(fix.TagTestReqID, "T1").
+			Set(fix.TagSenderCompID, "ENGINE").
+			Set(fix.TagTargetCompID, "CLIENT").
+			SetInt(fix.TagMsgSeqNum, 2)
+
+		Convey("It should be encoded with the header fields first and a valid body length and checksum", func() {
+			So(msg.String(), ShouldEqual, "8=FIX.4.4|9=62|35=0|49=ENGINE|56=CLIENT|34=2|52=20200101-10:00:00.000|112=T1|10=120|")
+		})
+
+		Convey("It should be decoded back", func() {
+			parsed, err := fix.ParseMessage(msg.Bytes())
+			So(err, ShouldBeNil)
+			So(parsed.Type(), ShouldEqual, fix.MsgTypeHeartbeat)
+			So(parsed.Get(fix.TagTestReqID), ShouldEqual, "T1")
+			seqNum, err := parsed.GetInt(fix.TagMsgSeqNum)
+			So(err, ShouldBeNil)
+			So(seqNum, ShouldEqual, 2)
+		})
+
+		Convey("A message with a wrong checksum or body length should be rejected", func() {
+			raw := msg.Bytes()
+			_, err := fix.ParseMessage(bytes.Replace(raw, []byte("10=120"), []byte("10=121"), 1))
+			So(err, ShouldEqual, fix.ErrInvalidMessage)
+			_, err = fix.ParseMessage(bytes.Replace(raw, []byte("9=62"), []byte("9=61"), 1))
+			So(err, ShouldEqual, fix.ErrInvalidMessage)
+		})
+
+		Convey("Messages should be read from a stream using the body length", func() {
+			stream := append(msg.Bytes(), msg.Bytes()...)
+			reader := bufio.NewReader(bytes.NewReader(stream))
+			first, err := fix.ReadMessage(reader)
+			So(err, ShouldBeNil)
+			So(first, ShouldResemble, msg.Bytes())
+			_, err = fix.ReadMessage(reader)
+			So(err, ShouldBeNil)
+			_, err = fix.ReadMessage(bufio.NewReader(strings.NewReader("8=FIX.4.2\x019=5\x01")))
+			So(err, ShouldEqual, fix.ErrInvalidMessage)
+		})
+	})
+}
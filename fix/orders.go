@@ -0,0 +1,322 @@
+package fix
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitlab.com/around25/products/matching-engine/conv"
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/utils"
+)
+
+// Values of the enumerated fields
+const (
+	SideBuy  = "1"
+	SideSell = "2"
+
+	OrdTypeMarket    = "1"
+	OrdTypeLimit     = "2"
+	OrdTypeStop      = "3"
+	OrdTypeStopLimit = "4"
+
+	ExecTypeNew       = "0"
+	ExecTypeCanceled  = "4"
+	ExecTypeReplaced  = "5"
+	ExecTypeRejected  = "8"
+	ExecTypeTrade     = "F"
+	ExecTypeTriggered = "L"
+
+	OrdStatusNew             = "0"
+	OrdStatusPartiallyFilled = "1"
+	OrdStatusFilled          = "2"
+	OrdStatusCanceled        = "4"
+	OrdStatusRejected        = "8"
+
+	CxlRejResponseToCancel  = "1"
+	CxlRejResponseToReplace = "2"
+
+	CxlRejReasonUnknownOrder = "1"
+	CxlRejReasonOther        = "99"
+
+	MassCancelRequestTypeSecurity = "1"
+	MassCancelRequestTypeAll      = "7"
+	MassCancelResponseRejected    = "0"
+
+	OrdRejReasonOther = "99"
+
+	// BusinessRejectReasonUnsupportedMsgType is used for application messages not handled by the engine
+	BusinessRejectReasonUnsupportedMsgType = "3"
+)
+
+// ErrMissingField is returned when a required field of an application message is missing
+var ErrMissingField = errors.New("required tag missing")
+
+// NewOrderFromMessage maps a NewOrderSingle or the replacement order of an OrderCancelReplaceRequest to a new order
+// command of the given owner
+// - Stop orders become stop entry orders when buying and stop loss orders when selling
+// - The id of the order must be set by the caller
+func NewOrderFromMessage(msg *Message, ownerID uint64, pricePrecision, volumePrecision int) (*model.Order, error) {
+	order := &model.Order{
+		EventType:     model.CommandType_NewOrder,
+		Market:        msg.Get(TagSymbol),
+		ClientOrderID: msg.Get(TagClOrdID),
+		OwnerID:       ownerID,
+	}
+	if order.Market == "" || order.ClientOrderID == "" {
+		return nil, ErrMissingField
+	}
+	switch msg.Get(TagSide) {
+	case SideBuy:
+		order.Side = model.MarketSide_Buy
+	case SideSell:
+		order.Side = model.MarketSide_Sell
+	default:
+		return nil, fmt.Errorf("unsupported side %q", msg.Get(TagSide))
+	}
+	ordType := msg.Get(TagOrdType)
+	switch ordType {
+	case OrdTypeMarket, OrdTypeStop:
+		order.Type = model.OrderType_Market
+	case OrdTypeLimit, OrdTypeStopLimit:
+		order.Type = model.OrderType_Limit
+	default:
+		return nil, fmt.Errorf("unsupported order type %q", ordType)
+	}
+	var err error
+	if order.Amount, err = parseUnits(msg, TagOrderQty, volumePrecision, true); err != nil {
+		return nil, err
+	}
+	if order.Price, err = parseUnits(msg, TagPrice, pricePrecision, order.Type == model.OrderType_Limit); err != nil {
+		return nil, err
+	}
+	if order.Funds, err = parseUnits(msg, TagCashOrderQty, pricePrecision, false); err != nil {
+		return nil, err
+	}
+	if ordType == OrdTypeStop || ordType == OrdTypeStopLimit {
+		if order.StopPrice, err = parseUnits(msg, TagStopPx, pricePrecision, true); err != nil {
+			return nil, err
+		}
+		order.Stop = model.StopLoss_Loss
+		if order.Side == model.MarketSide_Buy {
+			order.Stop = model.StopLoss_Entry
+		}
+	}
+	return order, nil
+}
+
+// CancelOrderFromMessage maps an OrderCancelRequest or an OrderCancelReplaceRequest to a cancel command that
+// identifies the order by the owner and the OrigClOrdID
+func CancelOrderFromMessage(msg *Message, ownerID uint64) (*model.Order, error) {
+	order := &model.Order{
+		EventType:     model.CommandType_CancelOrder,
+		Market:        msg.Get(TagSymbol),
+		ClientOrderID: msg.Get(TagOrigClOrdID),
+		OwnerID:       ownerID,
+	}
+	if order.Market == "" || order.ClientOrderID == "" || msg.Get(TagClOrdID) == "" {
+		return nil, ErrMissingField
+	}
+	return order, nil
+}
+
+// Report is an execution report generated for an order of a FIX owner
+type Report struct {
+	OwnerID uint64
+	OrderID uint64
+	ClOrdID string
+	// Closed is set when the order is no longer open after the report
+	Closed  bool
+	Message *Message
+}
+
+// ExecutionReports maps the events generated by a command to execution reports for the orders of the owners
+// accepted by the filter
+// - Fills are reported from the order status changes caused by the trades with the price and the amount of the trade
+// - Errors of cancel requests are not reported since they are answered with an OrderCancelReject
+func ExecutionReports(events []*model.Event, accept func(ownerID uint64) bool, pricePrecision, volumePrecision int) []Report {
+	reports := make([]Report, 0)
+	lastTrades := make(map[uint64]*model.Trade)
+	for _, event := range events {
+		switch event.Type {
+		case model.EventType_NewTrade:
+			trade := event.GetTrade()
+			lastTrades[trade.AskID] = trade
+			lastTrades[trade.BidID] = trade
+		case model.EventType_OrderStatusChange, model.EventType_OrderActivated:
+			status := event.GetOrderStatus()
+			if event.Type == model.EventType_OrderActivated {
+				status = event.GetOrderActivation()
+			}
+			if status == nil || !accept(status.OwnerID) {
+				continue
+			}
+			execType, ordStatus := statusExecType(event.Type, status)
+			msg := newExecutionReport(event, status.ID, status.ClientOrderID, status.Side, execType, ordStatus)
+			msg.Set(TagOrdType, ordType(status.Type)).
+				Set(TagOrderQty, conv.FromUnits(status.Amount, uint8(volumePrecision))).
+				Set(TagCumQty, conv.FromUnits(status.FilledAmount, uint8(volumePrecision))).
+				Set(TagAvgPx, conv.FromUnits(averagePrice(status, pricePrecision, volumePrecision), uint8(pricePrecision)))
+			if status.Type == model.OrderType_Limit {
+				msg.Set(TagPrice, conv.FromUnits(status.Price, uint8(pricePrecision)))
+			}
+			closed := status.Status == model.OrderStatus_Filled || status.Status == model.OrderStatus_Cancelled
+			leaves := uint64(0)
+			if !closed && status.Amount > status.FilledAmount {
+				leaves = status.Amount - status.FilledAmount
+			}
+			msg.Set(TagLeavesQty, conv.FromUnits(leaves, uint8(volumePrecision)))
+			if trade, ok := lastTrades[status.ID]; ok && execType == ExecTypeTrade {
+				msg.Set(TagLastQty, conv.FromUnits(trade.Amount, uint8(volumePrecision))).
+					Set(TagLastPx, conv.FromUnits(trade.Price, uint8(pricePrecision)))
+			}
+			if status.Status == model.OrderStatus_Cancelled && status.Reason != model.CancelReason_NoReason {
+				msg.Set(TagText, status.Reason.String())
+			}
+			reports = append(reports, Report{OwnerID: status.OwnerID, OrderID: status.ID, ClOrdID: status.ClientOrderID, Closed: closed, Message: msg})
+		case model.EventType_Error:
+			payload := event.GetError()
+			if !accept(payload.OwnerID) || payload.Code == model.ErrorCode_CancelFailed || payload.Code == model.ErrorCode_UnknownOrder {
+				continue
+			}
+			msg := newExecutionReport(event, payload.OrderID, payload.ClientOrderID, payload.Side, ExecTypeRejected, OrdStatusRejected).
+				Set(TagOrdType, ordType(payload.Type)).
+				Set(TagOrderQty, conv.FromUnits(payload.Amount, uint8(volumePrecision))).
+				Set(TagLeavesQty, "0").
+				Set(TagCumQty, "0").
+				Set(TagAvgPx, "0").
+				Set(TagOrdRejReason, OrdRejReasonOther).
+				Set(TagText, payload.Code.String())
+			reports = append(reports, Report{OwnerID: payload.OwnerID, OrderID: payload.OrderID, ClOrdID: payload.ClientOrderID, Closed: true, Message: msg})
+		}
+	}
+	return reports
+}
+
+// NewOrderCancelReject creates the response to a cancel or a cancel/replace request that could not be executed
+func NewOrderCancelReject(request *Message, responseTo, reason, text string) *Message {
+	msg := NewMessage(MsgTypeOrderCancelReject).
+		Set(TagOrderID, "NONE").
+		Set(TagClOrdID, request.Get(TagClOrdID)).
+		Set(TagOrigClOrdID, request.Get(TagOrigClOrdID)).
+		Set(TagOrdStatus, OrdStatusRejected).
+		Set(TagCxlRejResponseTo, responseTo).
+		Set(TagCxlRejReason, reason)
+	if text != "" {
+		msg.Set(TagText, text)
+	}
+	return msg
+}
+
+// NewBusinessMessageReject creates the response to an application message that cannot be processed
+func NewBusinessMessageReject(request *Message, reason, text string) *Message {
+	return NewMessage(MsgTypeBusinessMessageReject).
+		Set(TagRefSeqNum, request.Get(TagMsgSeqNum)).
+		Set(TagRefMsgType, request.Type()).
+		Set(TagBusinessRejectReason, reason).
+		Set(TagText, text)
+}
+
+// NewRejectedReport creates an execution report for a new order that could not be mapped to a command
+func NewRejectedReport(request *Message, text string) *Message {
+	return NewMessage(MsgTypeExecutionReport).
+		Set(TagOrderID, "NONE").
+		Set(TagClOrdID, request.Get(TagClOrdID)).
+		Set(TagExecID, "REJECT-"+request.Get(TagMsgSeqNum)).
+		Set(TagExecType, ExecTypeRejected).
+		Set(TagOrdStatus, OrdStatusRejected).
+		Set(TagSymbol, request.Get(TagSymbol)).
+		Set(TagSide, request.Get(TagSide)).
+		Set(TagLeavesQty, "0").
+		Set(TagCumQty, "0").
+		Set(TagAvgPx, "0").
+		Set(TagOrdRejReason, OrdRejReasonOther).
+		Set(TagText, text)
+}
+
+// newExecutionReport creates an execution report with the fields common to all reports
+func newExecutionReport(event *model.Event, orderID uint64, clOrdID string, side model.MarketSide, execType, ordStatus string) *Message {
+	msg := NewMessage(MsgTypeExecutionReport).
+		Set(TagOrderID, strconv.FormatUint(orderID, 10)).
+		Set(TagClOrdID, clOrdID).
+		Set(TagExecID, fmt.Sprintf("%s-%d-%d", event.Market, event.SeqID, orderID)).
+		Set(TagExecType, execType).
+		Set(TagOrdStatus, ordStatus).
+		Set(TagSymbol, event.Market).
+		Set(TagSide, SideBuy).
+		Set(TagTransactTime, time.Unix(0, event.CreatedAt).UTC().Format(sendingTimeFormat))
+	if side == model.MarketSide_Sell {
+		msg.Set(TagSide, SideSell)
+	}
+	return msg
+}
+
+// statusExecType returns the ExecType and the OrdStatus of the report for an order status change
+func statusExecType(eventType model.EventType, status *model.OrderStatusMsg) (string, string) {
+	ordStatus := OrdStatusNew
+	switch status.Status {
+	case model.OrderStatus_PartiallyFilled:
+		ordStatus = OrdStatusPartiallyFilled
+	case model.OrderStatus_Filled:
+		ordStatus = OrdStatusFilled
+	case model.OrderStatus_Cancelled:
+		ordStatus = OrdStatusCanceled
+	}
+	switch {
+	case eventType == model.EventType_OrderActivated:
+		return ExecTypeTriggered, ordStatus
+	case status.Status == model.OrderStatus_Cancelled:
+		return ExecTypeCanceled, ordStatus
+	case status.Liquidity != model.LiquidityFlag_NotFilled:
+		return ExecTypeTrade, ordStatus
+	}
+	return ExecTypeNew, ordStatus
+}
+
+// averagePrice computes the average price of the filled amount of the order
+func averagePrice(status *model.OrderStatusMsg, pricePrecision, volumePrecision int) uint64 {
+	if status.FilledAmount == 0 {
+		return 0
+	}
+	return utils.Divide(status.UsedFunds, status.FilledAmount, pricePrecision, volumePrecision, pricePrecision)
+}
+
+func ordType(orderType model.OrderType) string {
+	if orderType == model.OrderType_Market {
+		return OrdTypeMarket
+	}
+	return OrdTypeLimit
+}
+
+// parseUnits converts a decimal field to the units of the engine with the given precision
+func parseUnits(msg *Message, tag int, precision int, required bool) (uint64, error) {
+	value := msg.Get(tag)
+	if value == "" {
+		if required {
+			return 0, ErrMissingField
+		}
+		return 0, nil
+	}
+	parts := strings.SplitN(value, ".", 2)
+	valid := parts[0] != "" && isDigits(parts[0])
+	if len(parts) == 2 {
+		valid = valid && isDigits(parts[1]) && len(strings.TrimRight(parts[1], "0")) <= precision
+		parts[1] = strings.TrimRight(parts[1], "0")
+		value = parts[0] + "." + parts[1]
+	}
+	if !valid {
+		return 0, fmt.Errorf("invalid value %q for tag %d", msg.Get(tag), tag)
+	}
+	return conv.ToUnits(value, uint8(precision)), nil
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package fix_test
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/fix"
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderMapping(t *testing.T) {
+	Convey("Given a NewOrderSingle", t, func() {
+		msg := fix.NewMessage(fix.MsgTypeNewOrderSingle).
+			Set(fix.TagClOrdID, "A1").
+			Set(fix.TagSymbol, "btcusd").
+			Set(fix.TagSide, fix.SideSell).
+			Set(fix.TagOrdType, fix.OrdTypeLimit).
+			Set(fix.TagOrderQty, "1.5").
+			Set(fix.TagPrice, "100.25")
+
+		Convey("A limit order should be converted to the units of the market", func() {
+			order, err := fix.NewOrderFromMessage(msg, 7, 2, 8)
+			So(err, ShouldBeNil)
+			So(order.EventType, ShouldEqual, model.CommandType_NewOrder)
+			So(order.Market, ShouldEqual, "btcusd")
+			So(order.ClientOrderID, ShouldEqual, "A1")
+			So(order.OwnerID, ShouldEqual, 7)
+			So(order.Side, ShouldEqual, model.MarketSide_Sell)
+			So(order.Type, ShouldEqual, model.OrderType_Limit)
+			So(order.Amount, ShouldEqual, 150000000)
+			So(order.Price, ShouldEqual, 10025)
+		})
+
+		Convey("A stop limit sell order should become a stop loss order", func() {
+			order, err := fix.NewOrderFromMessage(msg.Set(fix.TagOrdType, fix.OrdTypeStopLimit).Set(fix.TagStopPx, "99"), 7, 2, 8)
+			So(err, ShouldBeNil)
+			So(order.Stop, ShouldEqual, model.StopLoss_Loss)
+			So(order.StopPrice, ShouldEqual, 9900)
+		})
+
+		Convey("Values with more decimals than the market precision should be rejected", func() {
+			_, err := fix.NewOrderFromMessage(msg.Set(fix.TagPrice, "100.251"), 7, 2, 8)
+			So(err, ShouldNotBeNil)
+			_, err = fix.NewOrderFromMessage(msg.Set(fix.TagPrice, "1e2"), 7, 2, 8)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Missing required fields should be rejected", func() {
+			_, err := fix.NewOrderFromMessage(msg.Set(fix.TagClOrdID, ""), 7, 2, 8)
+			So(err, ShouldEqual, fix.ErrMissingField)
+		})
+	})
+
+	Convey("Given an OrderCancelRequest", t, func() {
+		msg := fix.NewMessage(fix.MsgTypeOrderCancelRequest).
+			Set(fix.TagClOrdID, "C1").
+			Set(fix.TagOrigClOrdID, "A1").
+			Set(fix.TagSymbol, "btcusd")
+
+		Convey("It should cancel the order by the owner and the original client order id", func() {
+			order, err := fix.CancelOrderFromMessage(msg, 7)
+			So(err, ShouldBeNil)
+			So(order.CancelByClientOrderID(), ShouldBeTrue)
+			So(order.ClientOrderID, ShouldEqual, "A1")
+			So(order.OwnerID, ShouldEqual, 7)
+		})
+	})
+
+	Convey("Given the events generated by a matched order", t, func() {
+		events := []model.Event{
+			model.NewOrderStatusEvent(1, "btcusd", model.OrderType_Limit, model.MarketSide_Buy, 2, 7, "B1", 10000, 300000000, 0, model.OrderStatus_Untouched, 0, 0),
+			model.NewTradeEvent(2, "btcusd", 1, model.MarketSide_Buy, 1, 2, 8, 7, "S1", "B1", 100000000, 10000),
+			model.NewOrderStatusEvent(3, "btcusd", model.OrderType_Limit, model.MarketSide_Buy, 2, 7, "B1", 10000, 300000000, 0, model.OrderStatus_PartiallyFilled, 100000000, 10000),
+			model.NewOrderStatusEvent(4, "btcusd", model.OrderType_Limit, model.MarketSide_Sell, 1, 8, "S1", 10000, 100000000, 0, model.OrderStatus_Filled, 100000000, 10000),
+			model.NewErrorEvent(5, "btcusd", model.ErrorCode_CancelFailed, model.OrderType_Limit, model.MarketSide_Buy, 3, 7, "B2", 10000, 0, 0),
+		}
+		events[2].GetOrderStatus().Liquidity = model.LiquidityFlag_Taker
+		pointers := make([]*model.Event, len(events))
+		for i := range events {
+			pointers[i] = &events[i]
+		}
+		reports := fix.ExecutionReports(pointers, func(ownerID uint64) bool { return ownerID == 7 }, 2, 8)
+
+		Convey("Only the orders of the accepted owners should be reported", func() {
+			So(len(reports), ShouldEqual, 2)
+			So(reports[0].Message.Get(fix.TagExecType), ShouldEqual, fix.ExecTypeNew)
+			So(reports[0].Message.Get(fix.TagOrdStatus), ShouldEqual, fix.OrdStatusNew)
+			So(reports[0].Message.Get(fix.TagLeavesQty), ShouldEqual, "3.00000000")
+			So(reports[0].Closed, ShouldBeFalse)
+		})
+
+		Convey("Fills should be reported with the price and the amount of the trade", func() {
+			msg := reports[1].Message
+			So(msg.Get(fix.TagExecType), ShouldEqual, fix.ExecTypeTrade)
+			So(msg.Get(fix.TagOrdStatus), ShouldEqual, fix.OrdStatusPartiallyFilled)
+			So(msg.Get(fix.TagClOrdID), ShouldEqual, "B1")
+			So(msg.Get(fix.TagOrderID), ShouldEqual, "2")
+			So(msg.Get(fix.TagLastQty), ShouldEqual, "1.00000000")
+			So(msg.Get(fix.TagLastPx), ShouldEqual, "100.00")
+			So(msg.Get(fix.TagCumQty), ShouldEqual, "1.00000000")
+			So(msg.Get(fix.TagLeavesQty), ShouldEqual, "2.00000000")
+			So(msg.Get(fix.TagAvgPx), ShouldEqual, "100.00")
+			So(msg.Get(fix.TagExecID), ShouldEqual, "btcusd-3-2")
+		})
+	})
+}
@@ -0,0 +1,516 @@
+package fix
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultHeartBtInt is the heartbeat interval requested by the initiator when none is given
+const DefaultHeartBtInt = 30 * time.Second
+
+// sendingTimeFormat is the UTCTimestamp format used for the sending time of the messages
+const sendingTimeFormat = "20060102-15:04:05.000"
+
+// writeTimeout limits the time spent writing a message to a connection
+const writeTimeout = 5 * time.Second
+
+// logoutTimeout is the time waited for the response to a logout before closing the connection
+const logoutTimeout = 2 * time.Second
+
+// ErrAlreadyConnected is returned when a second connection tries to log on to a session that is already connected
+var ErrAlreadyConnected = errors.New("session is already connected")
+
+// SessionID identifies a session from the point of view of the local side
+type SessionID struct {
+	SenderCompID string
+	TargetCompID string
+}
+
+// String returns the session id as SENDER-TARGET
+func (id SessionID) String() string {
+	return id.SenderCompID + "-" + id.TargetCompID
+}
+
+// Application receives the events of a session
+// - The callbacks are called from the process reading the connection so they should not block
+type Application interface {
+	// OnLogon is called after the logon of the counterparty was accepted
+	OnLogon(session *Session)
+	// OnLogout is called after the connection of a logged on session was closed
+	OnLogout(session *Session)
+	// FromApp is called for every application message received in sequence
+	FromApp(session *Session, msg *Message)
+}
+
+// Session implements the FIX session layer on top of a connection
+// - The sequence numbers and the sent messages are kept in the store so they survive reconnects and restarts
+// - Application messages sent while the counterparty is not connected are stored and can be recovered with a
+// resend request after the next logon
+// - The credentials are sent in the logon of an initiator and required in the logon received by an acceptor
+type Session struct {
+	ID       SessionID
+	store    Store
+	app      Application
+	username string
+	password string
+
+	lock          sync.Mutex
+	conn          net.Conn
+	initiator     bool
+	loggedOn      bool
+	heartBtInt    time.Duration
+	lastSent      time.Time
+	lastReceived  time.Time
+	testRequestID string
+	logoutSent    time.Time
+	resendUpTo    int
+}
+
+// NewSession creates a new session that keeps its state in the given store
+func NewSession(id SessionID, store Store, app Application) *Session {
+	return &Session{ID: id, store: store, app: app}
+}
+
+// SetCredentials sets the username and password of the session
+// - Without credentials an acceptor accepts the logon of any connection using the comp ids of the session
+func (session *Session) SetCredentials(username, password string) {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	session.username = username
+	session.password = password
+}
+
+// authenticate checks the username and password sent in the logon of the counterparty
+func (session *Session) authenticate(logon *Message) bool {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	if session.username == "" && session.password == "" {
+		return true
+	}
+	username := subtle.ConstantTimeCompare([]byte(logon.Get(TagUsername)), []byte(session.username))
+	password := subtle.ConstantTimeCompare([]byte(logon.Get(TagPassword)), []byte(session.password))
+	return username&password == 1
+}
+
+// IsLoggedOn checks if the counterparty is connected and logged on
+func (session *Session) IsLoggedOn() bool {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	return session.loggedOn
+}
+
+// Send stamps the header of an application message with the next sequence number, stores it and sends it if the
+// session is connected
+func (session *Session) Send(msg *Message) error {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	return session.send(msg)
+}
+
+// Logout sends a logout message and closes the connection once the counterparty confirms it
+func (session *Session) Logout(text string) error {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	return session.sendLogout(text)
+}
+
+// Dial connects the session as an initiator to the acceptor at the given address and sends the logon message
+// - The logon resets the sequence numbers of both sides when resetSeqNum is set
+func (session *Session) Dial(address string, heartBtInt time.Duration, resetSeqNum bool) error {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return err
+	}
+	if heartBtInt <= 0 {
+		heartBtInt = DefaultHeartBtInt
+	}
+	session.lock.Lock()
+	if session.conn != nil {
+		session.lock.Unlock()
+		conn.Close()
+		return ErrAlreadyConnected
+	}
+	session.conn = conn
+	session.initiator = true
+	session.heartBtInt = heartBtInt
+	session.lastReceived = time.Now()
+	logon := NewMessage(MsgTypeLogon).
+		SetInt(TagEncryptMethod, 0).
+		SetInt(TagHeartBtInt, int(heartBtInt/time.Second))
+	if session.username != "" || session.password != "" {
+		logon.Set(TagUsername, session.username).Set(TagPassword, session.password)
+	}
+	if resetSeqNum {
+		if err := session.store.Reset(); err != nil {
+			session.lock.Unlock()
+			conn.Close()
+			return err
+		}
+		logon.Set(TagResetSeqNumFlag, "Y")
+	}
+	err = session.send(logon)
+	session.lock.Unlock()
+	if err != nil {
+		session.disconnect(conn)
+		return err
+	}
+	go session.serve(conn, bufio.NewReader(conn), nil)
+	return nil
+}
+
+// accept attaches a connection that sent a logon message to the session as an acceptor
+func (session *Session) accept(conn net.Conn, reader *bufio.Reader, logon *Message) error {
+	session.lock.Lock()
+	if session.conn != nil {
+		session.lock.Unlock()
+		return ErrAlreadyConnected
+	}
+	session.conn = conn
+	session.initiator = false
+	session.lastReceived = time.Now()
+	session.lock.Unlock()
+	go session.serve(conn, reader, logon)
+	return nil
+}
+
+// serve processes the messages received on the connection and sends heartbeats until the connection is closed
+func (session *Session) serve(conn net.Conn, reader *bufio.Reader, first *Message) {
+	defer session.disconnect(conn)
+	messages := make(chan []byte)
+	failed := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			raw, err := ReadMessage(reader)
+			if err != nil {
+				failed <- err
+				return
+			}
+			select {
+			case messages <- raw:
+			case <-done:
+				return
+			}
+		}
+	}()
+	if first != nil && !session.process(first) {
+		return
+	}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case raw := <-messages:
+			msg, err := ParseMessage(raw)
+			if err != nil {
+				// garbled messages are ignored and recovered by the sequence number checks
+				log.Warn().Err(err).Str("section", "fix").Str("action", "read").Str("session", session.ID.String()).Msg("Ignoring garbled message")
+				continue
+			}
+			if !session.process(msg) {
+				return
+			}
+		case err := <-failed:
+			log.Debug().Err(err).Str("section", "fix").Str("action", "read").Str("session", session.ID.String()).Msg("Connection closed")
+			return
+		case now := <-ticker.C:
+			if !session.checkHeartbeat(now) {
+				return
+			}
+		}
+	}
+}
+
+// process handles a message received from the counterparty and returns false if the connection should be closed
+func (session *Session) process(msg *Message) bool {
+	session.lock.Lock()
+	session.lastReceived = time.Now()
+	if msg.Get(TagSenderCompID) != session.ID.TargetCompID || msg.Get(TagTargetCompID) != session.ID.SenderCompID {
+		session.sendLogout("CompID problem")
+		session.lock.Unlock()
+		return false
+	}
+	seqNum, err := msg.GetInt(TagMsgSeqNum)
+	if err != nil {
+		session.sendLogout("MsgSeqNum missing")
+		session.lock.Unlock()
+		return false
+	}
+	msgType := msg.Type()
+	loggedOn := false
+	if !session.loggedOn {
+		if msgType != MsgTypeLogon || !session.logon(msg) {
+			session.lock.Unlock()
+			return false
+		}
+		loggedOn = true
+	}
+	ok, deliver := session.checkSeqNum(msg, msgType, seqNum)
+	session.lock.Unlock()
+
+	if loggedOn {
+		session.app.OnLogon(session)
+	}
+	if deliver {
+		session.app.FromApp(session, msg)
+	}
+	return ok
+}
+
+// logon validates the logon of the counterparty and replies to it when the session is the acceptor
+func (session *Session) logon(msg *Message) bool {
+	heartBtInt, err := msg.GetInt(TagHeartBtInt)
+	if err != nil || heartBtInt <= 0 {
+		session.sendLogout("HeartBtInt must be greater than zero")
+		return false
+	}
+	if msg.Get(TagResetSeqNumFlag) == "Y" {
+		if session.initiator {
+			// the sequence numbers were already reset before sending the logon
+			session.store.SetNextTargetSeqNum(1)
+		} else if err := session.store.Reset(); err != nil {
+			log.Error().Err(err).Str("section", "fix").Str("action", "logon").Str("session", session.ID.String()).Msg("Unable to reset the session store")
+			return false
+		}
+	}
+	if !session.initiator {
+		session.heartBtInt = time.Duration(heartBtInt) * time.Second
+		reply := NewMessage(MsgTypeLogon).
+			SetInt(TagEncryptMethod, 0).
+			SetInt(TagHeartBtInt, heartBtInt)
+		if msg.Get(TagResetSeqNumFlag) == "Y" {
+			reply.Set(TagResetSeqNumFlag, "Y")
+		}
+		if err := session.send(reply); err != nil {
+			return false
+		}
+	}
+	session.loggedOn = true
+	session.resendUpTo = 0
+	log.Info().Str("section", "fix").Str("action", "logon").Str("session", session.ID.String()).Msg("Session logged on")
+	return true
+}
+
+// checkSeqNum handles the session messages received in sequence and returns if the connection should be kept and if
+// the message should be delivered to the application
+func (session *Session) checkSeqNum(msg *Message, msgType string, seqNum int) (bool, bool) {
+	// a sequence reset without the gap fill flag ignores the sequence number of the message
+	if msgType == MsgTypeSequenceReset && msg.Get(TagGapFillFlag) != "Y" {
+		if newSeqNum, err := msg.GetInt(TagNewSeqNo); err == nil && newSeqNum > session.store.NextTargetSeqNum() {
+			session.store.SetNextTargetSeqNum(newSeqNum)
+		}
+		return true, false
+	}
+	expected := session.store.NextTargetSeqNum()
+	if seqNum > expected {
+		// ask for the missing messages once and drop the message since it will be resent
+		if session.resendUpTo < expected {
+			session.resendUpTo = seqNum
+			resend := NewMessage(MsgTypeResendRequest).
+				SetInt(TagBeginSeqNo, expected).
+				SetInt(TagEndSeqNo, 0)
+			session.send(resend)
+		}
+		if msgType == MsgTypeLogout {
+			return false, false
+		}
+		return true, false
+	}
+	if seqNum < expected {
+		if msg.Get(TagPossDupFlag) == "Y" {
+			return true, false
+		}
+		session.sendLogout("MsgSeqNum too low, expecting " + strconv.Itoa(expected) + " but received " + strconv.Itoa(seqNum))
+		return false, false
+	}
+	session.store.SetNextTargetSeqNum(expected + 1)
+
+	switch msgType {
+	case MsgTypeLogon, MsgTypeReject:
+	case MsgTypeHeartbeat:
+		if msg.Get(TagTestReqID) == session.testRequestID {
+			session.testRequestID = ""
+		}
+	case MsgTypeTestRequest:
+		session.send(NewMessage(MsgTypeHeartbeat).Set(TagTestReqID, msg.Get(TagTestReqID)))
+	case MsgTypeResendRequest:
+		begin, _ := msg.GetInt(TagBeginSeqNo)
+		end, _ := msg.GetInt(TagEndSeqNo)
+		session.resend(begin, end)
+	case MsgTypeSequenceReset:
+		if newSeqNum, err := msg.GetInt(TagNewSeqNo); err == nil && newSeqNum > expected+1 {
+			session.store.SetNextTargetSeqNum(newSeqNum)
+		}
+	case MsgTypeLogout:
+		if session.logoutSent.IsZero() {
+			session.sendLogout("")
+		}
+		return false, false
+	default:
+		return true, true
+	}
+	return true, false
+}
+
+// resend the stored application messages with the sequence numbers between begin and end and replace the session
+// messages with gap fills
+func (session *Session) resend(begin, end int) {
+	next := session.store.NextSenderSeqNum()
+	if begin < 1 {
+		begin = 1
+	}
+	if end == 0 || end >= next {
+		end = next - 1
+	}
+	messages, err := session.store.GetMessages(begin, end)
+	if err != nil {
+		log.Error().Err(err).Str("section", "fix").Str("action", "resend").Str("session", session.ID.String()).Msg("Unable to load the sent messages")
+		return
+	}
+	gapStart := 0
+	for seqNum := begin; seqNum <= end; seqNum++ {
+		var msg *Message
+		if raw, ok := messages[seqNum]; ok {
+			msg, _ = ParseMessage(raw)
+		}
+		if msg == nil || isSessionMessage(msg.Type()) {
+			if gapStart == 0 {
+				gapStart = seqNum
+			}
+			continue
+		}
+		if gapStart != 0 {
+			session.sendGapFill(gapStart, seqNum)
+			gapStart = 0
+		}
+		msg.Set(TagPossDupFlag, "Y")
+		msg.Set(TagOrigSendingTime, msg.Get(TagSendingTime))
+		msg.Set(TagSendingTime, time.Now().UTC().Format(sendingTimeFormat))
+		session.write(msg.Bytes())
+	}
+	if gapStart != 0 {
+		session.sendGapFill(gapStart, end+1)
+	}
+}
+
+// sendGapFill tells the counterparty to skip the messages between seqNum and newSeqNum
+func (session *Session) sendGapFill(seqNum, newSeqNum int) {
+	msg := NewMessage(MsgTypeSequenceReset).
+		Set(TagSenderCompID, session.ID.SenderCompID).
+		Set(TagTargetCompID, session.ID.TargetCompID).
+		SetInt(TagMsgSeqNum, seqNum).
+		Set(TagPossDupFlag, "Y").
+		Set(TagSendingTime, time.Now().UTC().Format(sendingTimeFormat)).
+		Set(TagGapFillFlag, "Y").
+		SetInt(TagNewSeqNo, newSeqNum)
+	session.write(msg.Bytes())
+}
+
+// checkHeartbeat sends heartbeats and test requests at the negotiated interval and returns false if the counterparty
+// stopped responding
+func (session *Session) checkHeartbeat(now time.Time) bool {
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	if !session.logoutSent.IsZero() && now.Sub(session.logoutSent) >= logoutTimeout {
+		return false
+	}
+	if !session.loggedOn {
+		// the counterparty must log on within one heartbeat interval of the connection
+		return session.heartBtInt == 0 || now.Sub(session.lastReceived) < session.heartBtInt
+	}
+	if now.Sub(session.lastSent) >= session.heartBtInt {
+		session.send(NewMessage(MsgTypeHeartbeat))
+	}
+	silence := now.Sub(session.lastReceived)
+	if silence >= 2*session.heartBtInt {
+		log.Warn().Str("section", "fix").Str("action", "heartbeat").Str("session", session.ID.String()).Msg("Heartbeat timeout")
+		return false
+	}
+	if silence >= session.heartBtInt+session.heartBtInt/5 && session.testRequestID == "" {
+		session.testRequestID = "TEST-" + now.UTC().Format(sendingTimeFormat)
+		session.send(NewMessage(MsgTypeTestRequest).Set(TagTestReqID, session.testRequestID))
+	}
+	return true
+}
+
+// send stamps the header of the message with the next sequence number, stores it and writes it to the connection
+// - Must be called while holding the session lock
+func (session *Session) send(msg *Message) error {
+	seqNum := session.store.NextSenderSeqNum()
+	msg.Set(TagSenderCompID, session.ID.SenderCompID)
+	msg.Set(TagTargetCompID, session.ID.TargetCompID)
+	msg.SetInt(TagMsgSeqNum, seqNum)
+	msg.Set(TagSendingTime, time.Now().UTC().Format(sendingTimeFormat))
+	raw := msg.Bytes()
+	if err := session.store.SaveMessage(seqNum, raw); err != nil {
+		return err
+	}
+	if err := session.store.SetNextSenderSeqNum(seqNum + 1); err != nil {
+		return err
+	}
+	session.write(raw)
+	return nil
+}
+
+// sendLogout sends a logout message with the given reason
+// - Must be called while holding the session lock
+func (session *Session) sendLogout(text string) error {
+	msg := NewMessage(MsgTypeLogout)
+	if text != "" {
+		msg.Set(TagText, text)
+	}
+	session.logoutSent = time.Now()
+	return session.send(msg)
+}
+
+// write the encoded message to the connection if the session is connected
+// - Must be called while holding the session lock
+func (session *Session) write(raw []byte) {
+	if session.conn == nil {
+		return
+	}
+	session.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := session.conn.Write(raw); err != nil {
+		log.Warn().Err(err).Str("section", "fix").Str("action", "write").Str("session", session.ID.String()).Msg("Unable to write message")
+		session.conn.Close()
+		return
+	}
+	session.lastSent = time.Now()
+}
+
+// disconnect closes the connection and notifies the application if the session was logged on
+func (session *Session) disconnect(conn net.Conn) {
+	conn.Close()
+	session.lock.Lock()
+	if session.conn != conn {
+		session.lock.Unlock()
+		return
+	}
+	wasLoggedOn := session.loggedOn
+	session.conn = nil
+	session.loggedOn = false
+	session.testRequestID = ""
+	session.logoutSent = time.Time{}
+	session.lock.Unlock()
+	if wasLoggedOn {
+		log.Info().Str("section", "fix").Str("action", "logout").Str("session", session.ID.String()).Msg("Session logged out")
+		session.app.OnLogout(session)
+	}
+}
+
+// isSessionMessage checks if the message type belongs to the session layer and should not be resent
+func isSessionMessage(msgType string) bool {
+	switch msgType {
+	case MsgTypeHeartbeat, MsgTypeTestRequest, MsgTypeResendRequest, MsgTypeReject, MsgTypeSequenceReset, MsgTypeLogout, MsgTypeLogon:
+		return true
+	}
+	return false
+}
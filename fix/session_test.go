@@ -0,0 +1,156 @@
+package fix_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"gitlab.com/around25/products/matching-engine/fix"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// recorder is an application that forwards the events of a session to channels
+type recorder struct {
+	logons   chan *fix.Session
+	logouts  chan *fix.Session
+	messages chan *fix.Message
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		logons:   make(chan *fix.Session, 10),
+		logouts:  make(chan *fix.Session, 10),
+		messages: make(chan *fix.Message, 10),
+	}
+}
+
+func (app *recorder) OnLogon(session *fix.Session)                   { app.logons <- session }
+func (app *recorder) OnLogout(session *fix.Session)                  { app.logouts <- session }
+func (app *recorder) FromApp(session *fix.Session, msg *fix.Message) { app.messages <- msg }
+
+func waitFor(events interface{}) bool {
+	switch ch := events.(type) {
+	case chan *fix.Session:
+		select {
+		case <-ch:
+			return true
+		case <-time.After(5 * time.Second):
+		}
+	case chan *fix.Message:
+		select {
+		case <-ch:
+			return true
+		case <-time.After(5 * time.Second):
+		}
+	}
+	return false
+}
+
+func TestSession(t *testing.T) {
+	Convey("Given an acceptor and an initiator", t, func() {
+		dir, err := ioutil.TempDir("", "fix")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		acceptorID := fix.SessionID{SenderCompID: "ENGINE", TargetCompID: "CLIENT"}
+		acceptorStore, err := fix.NewFileStore(dir, acceptorID.String(), 0)
+		So(err, ShouldBeNil)
+		acceptorApp := newRecorder()
+		acceptorSession := fix.NewSession(acceptorID, acceptorStore, acceptorApp)
+		acceptorSession.SetCredentials("client", "secret")
+		acceptor := fix.NewAcceptor("127.0.0.1:0")
+		acceptor.AddSession(acceptorSession)
+		So(acceptor.Start(), ShouldBeNil)
+		defer acceptor.Stop()
+		address := acceptor.Addr().String()
+
+		initiatorApp := newRecorder()
+		initiatorStore := fix.NewMemoryStore()
+		initiator := fix.NewSession(fix.SessionID{SenderCompID: "CLIENT", TargetCompID: "ENGINE"}, initiatorStore, initiatorApp)
+		initiator.SetCredentials("client", "secret")
+		So(initiator.Dial(address, time.Second, true), ShouldBeNil)
+		So(waitFor(initiatorApp.logons), ShouldBeTrue)
+		So(waitFor(acceptorApp.logons), ShouldBeTrue)
+
+		Convey("Application messages should be delivered in both directions", func() {
+			So(initiator.Send(fix.NewMessage(fix.MsgTypeNewOrderSingle).Set(fix.TagClOrdID, "1")), ShouldBeNil)
+			msg := <-acceptorApp.messages
+			So(msg.Get(fix.TagClOrdID), ShouldEqual, "1")
+			So(msg.Get(fix.TagMsgSeqNum), ShouldEqual, "2")
+			So(acceptorSession.Send(fix.NewMessage(fix.MsgTypeExecutionReport).Set(fix.TagClOrdID, "1")), ShouldBeNil)
+			msg = <-initiatorApp.messages
+			So(msg.Get(fix.TagClOrdID), ShouldEqual, "1")
+		})
+
+		Convey("Heartbeats should keep an idle session logged on", func() {
+			time.Sleep(2500 * time.Millisecond)
+			So(initiator.IsLoggedOn(), ShouldBeTrue)
+			So(acceptorSession.IsLoggedOn(), ShouldBeTrue)
+		})
+
+		Convey("A second connection to a logged on session should be refused", func() {
+			other := fix.NewSession(fix.SessionID{SenderCompID: "CLIENT", TargetCompID: "ENGINE"}, fix.NewMemoryStore(), newRecorder())
+			So(other.Dial(address, time.Second, true), ShouldBeNil)
+			time.Sleep(100 * time.Millisecond)
+			So(other.IsLoggedOn(), ShouldBeFalse)
+			So(acceptorSession.IsLoggedOn(), ShouldBeTrue)
+		})
+
+		Convey("A logon with invalid credentials should be refused", func() {
+			So(initiator.Logout(""), ShouldBeNil)
+			So(waitFor(initiatorApp.logouts), ShouldBeTrue)
+			So(waitFor(acceptorApp.logouts), ShouldBeTrue)
+
+			for _, password := range []string{"wrong", ""} {
+				other := fix.NewSession(fix.SessionID{SenderCompID: "CLIENT", TargetCompID: "ENGINE"}, fix.NewMemoryStore(), newRecorder())
+				other.SetCredentials("client", password)
+				So(other.Dial(address, time.Second, true), ShouldBeNil)
+				time.Sleep(100 * time.Millisecond)
+				So(other.IsLoggedOn(), ShouldBeFalse)
+				So(acceptorSession.IsLoggedOn(), ShouldBeFalse)
+			}
+
+			So(initiator.Dial(address, time.Second, false), ShouldBeNil)
+			So(waitFor(acceptorApp.logons), ShouldBeTrue)
+		})
+
+		Convey("Messages sent while the initiator was disconnected should be resent after the next logon", func() {
+			So(initiator.Logout(""), ShouldBeNil)
+			So(waitFor(initiatorApp.logouts), ShouldBeTrue)
+			So(waitFor(acceptorApp.logouts), ShouldBeTrue)
+			So(acceptorSession.Send(fix.NewMessage(fix.MsgTypeExecutionReport).Set(fix.TagClOrdID, "2")), ShouldBeNil)
+
+			So(initiator.Dial(address, time.Second, false), ShouldBeNil)
+			So(waitFor(initiatorApp.logons), ShouldBeTrue)
+			msg := <-initiatorApp.messages
+			So(msg.Get(fix.TagClOrdID), ShouldEqual, "2")
+			So(msg.Get(fix.TagPossDupFlag), ShouldEqual, "Y")
+			So(msg.Get(fix.TagOrigSendingTime), ShouldNotBeEmpty)
+
+			Convey("and the sequence numbers should continue after the resend", func() {
+				So(acceptorSession.Send(fix.NewMessage(fix.MsgTypeExecutionReport).Set(fix.TagClOrdID, "3")), ShouldBeNil)
+				msg := <-initiatorApp.messages
+				So(msg.Get(fix.TagClOrdID), ShouldEqual, "3")
+				So(msg.Get(fix.TagPossDupFlag), ShouldBeEmpty)
+			})
+		})
+
+		Convey("The sequence numbers of the acceptor should be persisted in the store", func() {
+			So(initiator.Send(fix.NewMessage(fix.MsgTypeNewOrderSingle).Set(fix.TagClOrdID, "1")), ShouldBeNil)
+			<-acceptorApp.messages
+			reloaded, err := fix.NewFileStore(dir, acceptorID.String(), 0)
+			So(err, ShouldBeNil)
+			So(reloaded.NextTargetSeqNum(), ShouldEqual, 3)
+			So(reloaded.NextSenderSeqNum(), ShouldEqual, acceptorStore.NextSenderSeqNum())
+			messages, err := reloaded.GetMessages(1, 0)
+			So(err, ShouldBeNil)
+			So(len(messages), ShouldEqual, acceptorStore.NextSenderSeqNum()-1)
+			reloaded.Close()
+		})
+
+		initiator.Logout("")
+		waitFor(acceptorApp.logouts)
+	})
+}
@@ -0,0 +1,294 @@
+package fix
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// DefaultStoreLimit is the number of sent messages kept by a store when no limit is given
+const DefaultStoreLimit = 10000
+
+// Store keeps the sequence numbers of a session and the messages sent on it so they can be resent
+type Store interface {
+	NextSenderSeqNum() int
+	NextTargetSeqNum() int
+	SetNextSenderSeqNum(int) error
+	SetNextTargetSeqNum(int) error
+	// SaveMessage keeps the encoded message sent with the given sequence number
+	SaveMessage(seqNum int, raw []byte) error
+	// GetMessages returns the saved messages with the sequence numbers between begin and end inclusive indexed by
+	// sequence number. An end of zero means all messages after begin. Messages that are no longer kept are missing
+	// from the result and are replaced by gap fills when resent.
+	GetMessages(begin, end int) (map[int][]byte, error)
+	// Reset the sequence numbers to 1 and remove the saved messages
+	Reset() error
+	Close() error
+}
+
+// memoryStore keeps the session state in memory
+// - Only the last limit sent messages are kept, the oldest ones are dropped as new ones are saved
+type memoryStore struct {
+	lock      sync.Mutex
+	senderSeq int
+	targetSeq int
+	limit     int
+	oldest    int
+	messages  map[int][]byte
+}
+
+// NewMemoryStore creates a store that does not survive restarts and keeps the last DefaultStoreLimit sent messages
+func NewMemoryStore() Store {
+	return newMemoryStore(DefaultStoreLimit)
+}
+
+func newMemoryStore(limit int) *memoryStore {
+	if limit <= 0 {
+		limit = DefaultStoreLimit
+	}
+	return &memoryStore{senderSeq: 1, targetSeq: 1, limit: limit, messages: make(map[int][]byte)}
+}
+
+func (store *memoryStore) NextSenderSeqNum() int {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	return store.senderSeq
+}
+
+func (store *memoryStore) NextTargetSeqNum() int {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	return store.targetSeq
+}
+
+func (store *memoryStore) SetNextSenderSeqNum(seqNum int) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.senderSeq = seqNum
+	return nil
+}
+
+func (store *memoryStore) SetNextTargetSeqNum(seqNum int) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.targetSeq = seqNum
+	return nil
+}
+
+func (store *memoryStore) SaveMessage(seqNum int, raw []byte) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.saveMessage(seqNum, raw)
+	return nil
+}
+
+// saveMessage keeps the message and drops the oldest messages over the limit
+func (store *memoryStore) saveMessage(seqNum int, raw []byte) {
+	if len(store.messages) == 0 || seqNum < store.oldest {
+		store.oldest = seqNum
+	}
+	store.messages[seqNum] = raw
+	for len(store.messages) > store.limit {
+		delete(store.messages, store.oldest)
+		store.oldest++
+	}
+}
+
+func (store *memoryStore) GetMessages(begin, end int) (map[int][]byte, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	messages := make(map[int][]byte)
+	for seqNum, raw := range store.messages {
+		if seqNum >= begin && (end == 0 || seqNum <= end) {
+			messages[seqNum] = raw
+		}
+	}
+	return messages, nil
+}
+
+func (store *memoryStore) Reset() error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.senderSeq = 1
+	store.targetSeq = 1
+	store.oldest = 0
+	store.messages = make(map[int][]byte)
+	return nil
+}
+
+func (store *memoryStore) Close() error {
+	return nil
+}
+
+// fileStore keeps the session state in memory and persists it in two files:
+// - {session}.seqnums contains the next sender and target sequence numbers
+// - {session}.messages is an append-only log of the sent messages, each preceded by its sequence number and length
+// - The log is rewritten with the kept messages once it holds twice the limit of messages
+type fileStore struct {
+	*memoryStore
+	seqNumsPath  string
+	messagesPath string
+	messages     *os.File
+	written      int
+}
+
+// NewFileStore creates a store that persists the state of the session with the given name in the directory and
+// keeps the last limit sent messages, or DefaultStoreLimit when the limit is not positive
+func NewFileStore(dir, name string, limit int) (Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	store := &fileStore{
+		memoryStore:  newMemoryStore(limit),
+		seqNumsPath:  filepath.Join(dir, name+".seqnums"),
+		messagesPath: filepath.Join(dir, name+".messages"),
+	}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	if err := store.openMessages(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// openMessages opens the messages log of the session for appending
+func (store *fileStore) openMessages() error {
+	messages, err := os.OpenFile(store.messagesPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	store.messages = messages
+	return nil
+}
+
+// load the sequence numbers and the sent messages from the files of the session
+func (store *fileStore) load() error {
+	seqNums, err := ioutil.ReadFile(store.seqNumsPath)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return err
+	default:
+		if _, err := fmt.Sscanf(string(seqNums), "%d:%d", &store.senderSeq, &store.targetSeq); err != nil {
+			return fmt.Errorf("invalid sequence numbers file %s: %w", store.seqNumsPath, err)
+		}
+	}
+	file, err := os.Open(store.messagesPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	for {
+		var seqNum, length int
+		if _, err := fmt.Fscanf(reader, "%d %d\n", &seqNum, &length); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("invalid messages file %s: %w", store.messagesPath, err)
+		}
+		raw := make([]byte, length)
+		if _, err := io.ReadFull(reader, raw); err != nil {
+			return fmt.Errorf("invalid messages file %s: %w", store.messagesPath, err)
+		}
+		store.memoryStore.saveMessage(seqNum, raw)
+		store.written++
+	}
+}
+
+// saveSeqNums replaces the sequence numbers file of the session
+func (store *fileStore) saveSeqNums() error {
+	tmp := store.seqNumsPath + ".tmp"
+	content := fmt.Sprintf("%d:%d", store.memoryStore.NextSenderSeqNum(), store.memoryStore.NextTargetSeqNum())
+	if err := ioutil.WriteFile(tmp, []byte(content), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, store.seqNumsPath)
+}
+
+func (store *fileStore) SetNextSenderSeqNum(seqNum int) error {
+	store.memoryStore.SetNextSenderSeqNum(seqNum)
+	return store.saveSeqNums()
+}
+
+func (store *fileStore) SetNextTargetSeqNum(seqNum int) error {
+	store.memoryStore.SetNextTargetSeqNum(seqNum)
+	return store.saveSeqNums()
+}
+
+func (store *fileStore) SaveMessage(seqNum int, raw []byte) error {
+	if err := writeStoredMessage(store.messages, seqNum, raw); err != nil {
+		return err
+	}
+	store.written++
+	store.memoryStore.SaveMessage(seqNum, raw)
+	if store.written >= 2*store.limit {
+		return store.compact()
+	}
+	return nil
+}
+
+// compact replaces the messages log with a log of the kept messages
+func (store *fileStore) compact() error {
+	tmp := store.messagesPath + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	messages, _ := store.memoryStore.GetMessages(0, 0)
+	seqNums := make([]int, 0, len(messages))
+	for seqNum := range messages {
+		seqNums = append(seqNums, seqNum)
+	}
+	sort.Ints(seqNums)
+	for _, seqNum := range seqNums {
+		if err := writeStoredMessage(writer, seqNum, messages[seqNum]); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, store.messagesPath); err != nil {
+		return err
+	}
+	store.messages.Close()
+	store.written = len(seqNums)
+	return store.openMessages()
+}
+
+// writeStoredMessage appends a message to a messages log
+func writeStoredMessage(writer io.Writer, seqNum int, raw []byte) error {
+	if _, err := fmt.Fprintf(writer, "%d %d\n", seqNum, len(raw)); err != nil {
+		return err
+	}
+	_, err := writer.Write(raw)
+	return err
+}
+
+func (store *fileStore) Reset() error {
+	store.memoryStore.Reset()
+	if err := store.messages.Truncate(0); err != nil {
+		return err
+	}
+	store.written = 0
+	return store.saveSeqNums()
+}
+
+func (store *fileStore) Close() error {
+	return store.messages.Close()
+}
@@ -0,0 +1,82 @@
+package fix_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/fix"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func saveMessages(store fix.Store, from, to int) {
+	for seqNum := from; seqNum <= to; seqNum++ {
+		So(store.SaveMessage(seqNum, []byte("message "+strconv.Itoa(seqNum))), ShouldBeNil)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	Convey("Given a file store that keeps the last 3 messages", t, func() {
+		dir, err := ioutil.TempDir("", "fix_store")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		store, err := fix.NewFileStore(dir, "ENGINE-CLIENT", 3)
+		So(err, ShouldBeNil)
+		defer store.Close()
+
+		Convey("Only the last messages should be returned", func() {
+			saveMessages(store, 1, 5)
+			messages, err := store.GetMessages(1, 0)
+			So(err, ShouldBeNil)
+			So(messages, ShouldResemble, map[int][]byte{
+				3: []byte("message 3"),
+				4: []byte("message 4"),
+				5: []byte("message 5"),
+			})
+		})
+
+		Convey("The messages file should be compacted to the kept messages", func() {
+			saveMessages(store, 1, 6)
+			info, err := os.Stat(filepath.Join(dir, "ENGINE-CLIENT.messages"))
+			So(err, ShouldBeNil)
+			So(info.Size(), ShouldEqual, 3*len("4 9\nmessage 4"))
+
+			Convey("and new messages should be appended after the compaction", func() {
+				saveMessages(store, 7, 7)
+				reloaded, err := fix.NewFileStore(dir, "ENGINE-CLIENT", 3)
+				So(err, ShouldBeNil)
+				defer reloaded.Close()
+				messages, err := reloaded.GetMessages(1, 0)
+				So(err, ShouldBeNil)
+				So(messages, ShouldResemble, map[int][]byte{
+					5: []byte("message 5"),
+					6: []byte("message 6"),
+					7: []byte("message 7"),
+				})
+			})
+		})
+
+		Convey("A reloaded store should keep the limit", func() {
+			saveMessages(store, 1, 5)
+			reloaded, err := fix.NewFileStore(dir, "ENGINE-CLIENT", 2)
+			So(err, ShouldBeNil)
+			defer reloaded.Close()
+			messages, err := reloaded.GetMessages(1, 0)
+			So(err, ShouldBeNil)
+			So(len(messages), ShouldEqual, 2)
+			So(string(messages[5]), ShouldEqual, "message 5")
+		})
+
+		Convey("A reset should remove the messages", func() {
+			saveMessages(store, 1, 2)
+			So(store.Reset(), ShouldBeNil)
+			saveMessages(store, 1, 1)
+			messages, err := store.GetMessages(1, 0)
+			So(err, ShouldBeNil)
+			So(messages, ShouldResemble, map[int][]byte{1: []byte("message 1")})
+		})
+	})
+}
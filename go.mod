@@ -6,21 +6,25 @@ require (
 	dmitri.shuralyov.com/service/change v0.0.0-20190203163610-217368fe4577 // indirect
 	git.apache.org/thrift.git v0.12.0 // indirect
 	github.com/Shopify/sarama v1.21.0
+	github.com/alicebob/miniredis/v2 v2.30.0
 	github.com/bsm/sarama-cluster v2.1.15+incompatible
 	github.com/coreos/go-etcd v2.0.0+incompatible // indirect
 	github.com/ericlagergren/decimal v0.0.0-20181231230500-73749d4874d5
 	github.com/fzipp/gocyclo v0.3.1 // indirect
 	github.com/gin-gonic/gin v1.4.0 // indirect
 	github.com/golang/lint v0.0.0-20181217174547-8f45f776aaf1 // indirect
-	github.com/golang/protobuf v1.4.1
-	github.com/gomodule/redigo v2.0.0+incompatible // indirect
+	github.com/golang/protobuf v1.4.2
+	github.com/gomodule/redigo v1.8.9
 	github.com/google/pprof v0.0.0-20190208070709-b421f19a5c07 // indirect
 	github.com/googleapis/gax-go v2.0.2+incompatible // indirect
 	github.com/gopherjs/gopherjs v0.0.0-20190430165422-3e4dfb77656c // indirect
 	github.com/gorilla/mux v1.7.0 // indirect
+	github.com/gorilla/websocket v1.4.2
 	github.com/gregjones/httpcache v0.0.0-20190212212710-3befbb6ad0cc // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/microcosm-cc/bluemonday v1.0.2 // indirect
+	github.com/nats-io/nats-server/v2 v2.2.6
+	github.com/nats-io/nats.go v1.11.0
 	github.com/openzipkin/zipkin-go v0.1.5 // indirect
 	github.com/prometheus/client_golang v1.0.0
 	github.com/rcrowley/go-metrics v0.0.0-20181016184325-3113b8401b8a
@@ -56,6 +60,7 @@ require (
 	golang.org/x/oauth2 v0.0.0-20190212230446-3e8b2be13635 // indirect
 	golang.org/x/perf v0.0.0-20190124201629-844a5f5b46f4 // indirect
 	golang.org/x/tools v0.1.0 // indirect
+	google.golang.org/grpc v1.36.0
 	google.golang.org/protobuf v1.25.0
 	sourcegraph.com/sqs/pbtypes v1.0.0 // indirect
 )
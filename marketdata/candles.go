@@ -0,0 +1,320 @@
+package marketdata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/utils"
+)
+
+// DefaultCandleIntervals are the intervals for which candles are generated when none are configured
+var DefaultCandleIntervals = []time.Duration{time.Minute, 5 * time.Minute, time.Hour, 24 * time.Hour}
+
+// ClosedCandlesKept is the number of closed candles kept for each interval so they can be amended by a trade
+// bust or correction
+const ClosedCandlesKept = 60
+
+// CandleAggregator builds OHLCV candles for a market from the generated trades
+//
+// Each interval has at most one open candle. A candle is closed when a trade is received after the end
+// of its interval or when the aggregator is closed at a time after the end of its interval.
+// Intervals without trades do not generate any candle.
+//
+// A busted or corrected trade is removed from or changed in the candles that include it. An open candle is simply
+// updated, or removed when it is left without trades, while one of the last closed candles of the interval is
+// published again with the Amended flag set.
+type CandleAggregator struct {
+	market          string
+	pricePrecision  int
+	volumePrecision int
+	intervals       []time.Duration
+	open            map[int64]*model.Candle
+	// the last closed candles of each interval from the oldest to the newest
+	closed map[int64][]*model.Candle
+}
+
+// TradeWindow holds the recent trades of the market that were not busted, from the oldest to the newest, along
+// with the sequence id of the oldest trade of the window. Every trade generated since that one is either in the
+// list or was busted, so a candle whose first trade is not older than it can be rebuilt from the window.
+type TradeWindow struct {
+	FromSeqID uint64
+	Trades    []model.Trade
+}
+
+// NewCandleAggregator creates a new aggregator for the given market and intervals
+func NewCandleAggregator(market string, pricePrecision, volumePrecision int, intervals []time.Duration) *CandleAggregator {
+	if len(intervals) == 0 {
+		intervals = DefaultCandleIntervals
+	}
+	return &CandleAggregator{
+		market:          market,
+		pricePrecision:  pricePrecision,
+		volumePrecision: volumePrecision,
+		intervals:       intervals,
+		open:            make(map[int64]*model.Candle, len(intervals)),
+		closed:          make(map[int64][]*model.Candle, len(intervals)),
+	}
+}
+
+// AddEvents updates the open candles with the trades found in the given events and returns the closed candles
+//
+// The trades are placed in the candles of the given time, which is the time of the input message that generated the
+// events and not the time when the events were created, so the same candles are built when the input is replayed.
+func (agg *CandleAggregator) AddEvents(events []model.Event, at time.Time) []*model.Candle {
+	var closed []*model.Candle
+	for i := range events {
+		if events[i].Type != model.EventType_NewTrade {
+			continue
+		}
+		closed = append(closed, agg.AddTrade(events[i].GetTrade(), at)...)
+	}
+	return closed
+}
+
+// AddTrade updates the open candles with a new trade and returns the candles closed by it
+func (agg *CandleAggregator) AddTrade(trade *model.Trade, at time.Time) []*model.Candle {
+	closed := agg.Close(at)
+	quoteVolume := utils.Multiply(trade.Amount, trade.Price, agg.volumePrecision, agg.pricePrecision, agg.pricePrecision)
+	for _, interval := range agg.intervals {
+		seconds := int64(interval / time.Second)
+		candle, ok := agg.open[seconds]
+		if !ok {
+			candle = newCandle(agg.market, seconds, trade, at)
+			agg.open[seconds] = candle
+		}
+		updateCandle(candle, trade, quoteVolume)
+	}
+	return closed
+}
+
+// create a new candle for the interval in which the trade was generated
+func newCandle(market string, seconds int64, trade *model.Trade, at time.Time) *model.Candle {
+	return &model.Candle{
+		Market:          market,
+		Interval:        seconds,
+		OpenTime:        at.Unix() - at.Unix()%seconds,
+		Open:            trade.Price,
+		High:            trade.Price,
+		Low:             trade.Price,
+		FirstTradeSeqID: trade.SeqID,
+	}
+}
+
+// update the prices and the volume of the candle with a new trade
+func updateCandle(candle *model.Candle, trade *model.Trade, quoteVolume uint64) {
+	if trade.Price > candle.High {
+		candle.High = trade.Price
+	}
+	if trade.Price < candle.Low {
+		candle.Low = trade.Price
+	}
+	candle.Close = trade.Price
+	candle.Volume += trade.Amount
+	candle.QuoteVolume += quoteVolume
+	candle.TradeCount++
+	candle.LastTradeSeqID = trade.SeqID
+}
+
+// Close removes and returns the open candles whose interval ended at the given time
+func (agg *CandleAggregator) Close(at time.Time) []*model.Candle {
+	var closed []*model.Candle
+	for _, interval := range agg.intervals {
+		seconds := int64(interval / time.Second)
+		candle, ok := agg.open[seconds]
+		if !ok || at.Unix() < candle.OpenTime+seconds {
+			continue
+		}
+		closed = append(closed, candle)
+		delete(agg.open, seconds)
+		agg.keepClosed(candle)
+	}
+	return closed
+}
+
+// keep a closed candle in the history of its interval
+func (agg *CandleAggregator) keepClosed(candle *model.Candle) {
+	history := append(agg.closed[candle.Interval], proto.Clone(candle).(*model.Candle))
+	if len(history) > ClosedCandlesKept {
+		history = history[len(history)-ClosedCandlesKept:]
+	}
+	agg.closed[candle.Interval] = history
+}
+
+// AmendTrades removes the busted trades from the candles and applies the corrected ones
+// Returns the amended closed candles that have to be published again
+func (agg *CandleAggregator) AmendTrades(events []model.Event, window TradeWindow) []*model.Candle {
+	var amended []*model.Candle
+	for i := range events {
+		original, corrected, ok := amendedTrade(&events[i])
+		if !ok {
+			continue
+		}
+		for _, interval := range agg.intervals {
+			seconds := int64(interval / time.Second)
+			if candle, ok := agg.open[seconds]; ok && includesTrade(candle, original.SeqID) {
+				amendCandle(candle, original, corrected, window, agg.pricePrecision, agg.volumePrecision)
+				if candle.TradeCount == 0 {
+					delete(agg.open, seconds)
+				}
+				continue
+			}
+			for _, candle := range agg.closed[seconds] {
+				if includesTrade(candle, original.SeqID) {
+					amendCandle(candle, original, corrected, window, agg.pricePrecision, agg.volumePrecision)
+					published := proto.Clone(candle).(*model.Candle)
+					published.Amended = true
+					amended = append(amended, published)
+					break
+				}
+			}
+		}
+	}
+	return amended
+}
+
+// AmendsTrades checks if any of the events busts or corrects a trade
+func AmendsTrades(events []model.Event) bool {
+	for i := range events {
+		if _, _, ok := amendedTrade(&events[i]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// amendedTrade returns the trade changed by a bust or correction event and its corrected value, nil for a bust
+func amendedTrade(event *model.Event) (*model.Trade, *model.Trade, bool) {
+	switch event.Type {
+	case model.EventType_TradeBusted:
+		return event.GetTradeBust().Trade, nil, true
+	case model.EventType_TradeCorrected:
+		return event.GetTradeCorrect().Original, event.GetTradeCorrect().Corrected, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// includesTrade checks if the trade with the given sequence id was placed in the candle
+func includesTrade(candle *model.Candle, seqID uint64) bool {
+	return candle.FirstTradeSeqID <= seqID && seqID <= candle.LastTradeSeqID
+}
+
+// amendCandle removes the original trade from the candle and adds the corrected one if any
+//
+// The candle is rebuilt from the trade window when the window still holds all of its trades. Otherwise only the
+// volume of the candle is updated, along with the prices that can be derived from the amended trade.
+func amendCandle(candle *model.Candle, original, corrected *model.Trade, window TradeWindow, pricePrecision, volumePrecision int) {
+	if window.FromSeqID != 0 && window.FromSeqID <= candle.FirstTradeSeqID {
+		rebuildCandle(candle, window.Trades, pricePrecision, volumePrecision)
+		return
+	}
+	candle.Volume -= original.Amount
+	candle.QuoteVolume -= utils.Multiply(original.Amount, original.Price, volumePrecision, pricePrecision, pricePrecision)
+	candle.TradeCount--
+	if corrected == nil {
+		return
+	}
+	candle.Volume += corrected.Amount
+	candle.QuoteVolume += utils.Multiply(corrected.Amount, corrected.Price, volumePrecision, pricePrecision, pricePrecision)
+	candle.TradeCount++
+	if corrected.Price > candle.High {
+		candle.High = corrected.Price
+	}
+	if corrected.Price < candle.Low {
+		candle.Low = corrected.Price
+	}
+	if candle.FirstTradeSeqID == corrected.SeqID {
+		candle.Open = corrected.Price
+	}
+	if candle.LastTradeSeqID == corrected.SeqID {
+		candle.Close = corrected.Price
+	}
+}
+
+// rebuildCandle computes the candle again from the trades of the window included in it
+// A candle left without trades keeps its sequence ids and has no prices or volume.
+func rebuildCandle(candle *model.Candle, trades []model.Trade, pricePrecision, volumePrecision int) {
+	first, last := candle.FirstTradeSeqID, candle.LastTradeSeqID
+	candle.Open, candle.High, candle.Low, candle.Close = 0, 0, 0, 0
+	candle.Volume, candle.QuoteVolume, candle.TradeCount = 0, 0, 0
+	for i := range trades {
+		trade := &trades[i]
+		if trade.SeqID < first || trade.SeqID > last {
+			continue
+		}
+		if candle.TradeCount == 0 {
+			candle.Open, candle.High, candle.Low = trade.Price, trade.Price, trade.Price
+			candle.FirstTradeSeqID = trade.SeqID
+		}
+		updateCandle(candle, trade, utils.Multiply(trade.Amount, trade.Price, volumePrecision, pricePrecision, pricePrecision))
+	}
+}
+
+// Backup returns the open candles and the last closed ones so they can be saved in the market backup
+func (agg *CandleAggregator) Backup() ([]*model.Candle, []*model.Candle) {
+	candles := make([]*model.Candle, 0, len(agg.open))
+	closed := make([]*model.Candle, 0)
+	for _, interval := range agg.intervals {
+		seconds := int64(interval / time.Second)
+		if candle, ok := agg.open[seconds]; ok {
+			candles = append(candles, proto.Clone(candle).(*model.Candle))
+		}
+		for _, candle := range agg.closed[seconds] {
+			closed = append(closed, proto.Clone(candle).(*model.Candle))
+		}
+	}
+	return candles, closed
+}
+
+// Load the open candles and the last closed ones from the market backup
+// Candles of intervals that are no longer configured are ignored
+func (agg *CandleAggregator) Load(candles, closed []*model.Candle) {
+	for _, candle := range candles {
+		if agg.hasInterval(candle.Interval) {
+			agg.open[candle.Interval] = proto.Clone(candle).(*model.Candle)
+		}
+	}
+	for _, candle := range closed {
+		if agg.hasInterval(candle.Interval) {
+			agg.keepClosed(candle)
+		}
+	}
+}
+
+// hasInterval checks if candles are generated for the given interval in seconds
+func (agg *CandleAggregator) hasInterval(seconds int64) bool {
+	for _, interval := range agg.intervals {
+		if seconds == int64(interval/time.Second) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCandleInterval parses an interval like 1m, 5m, 1h or 1d
+// Besides the units supported by time.ParseDuration a number of days can be given with the d suffix
+func ParseCandleInterval(value string) (time.Duration, error) {
+	var interval time.Duration
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid candle interval %q", value)
+		}
+		interval = time.Duration(days) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid candle interval %q", value)
+		}
+		interval = parsed
+	}
+	if interval < time.Second || interval%time.Second != 0 {
+		return 0, fmt.Errorf("candle interval %q must be a whole number of seconds", value)
+	}
+	return interval, nil
+}
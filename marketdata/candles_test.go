@@ -0,0 +1,155 @@
+package marketdata_test
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/around25/products/matching-engine/marketdata"
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func tradeEvent(seqID, price, amount uint64) model.Event {
+	return model.NewTradeEvent(seqID, "btcusd", seqID, model.MarketSide_Buy, 1, 2, 1, 2, "", "", amount, price)
+}
+
+func testTrade(seqID, price, amount uint64) model.Trade {
+	event := tradeEvent(seqID, price, amount)
+	return *event.GetTrade()
+}
+
+func TestCandleAggregator(t *testing.T) {
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	Convey("Given a candle aggregator with 1m and 1h intervals", t, func() {
+		agg := marketdata.NewCandleAggregator("btcusd", 8, 8, []time.Duration{time.Minute, time.Hour})
+		closed := agg.AddEvents([]model.Event{
+			tradeEvent(1, 100000000, 100000000),
+			model.NewOrderStatusEvent(2, "btcusd", model.OrderType_Limit, model.MarketSide_Buy, 1, 1, "", 100000000, 100000000, 0, model.OrderStatus_Filled, 0, 0),
+			tradeEvent(2, 120000000, 200000000),
+		}, start.Add(5*time.Second))
+		closed = append(closed, agg.AddEvents([]model.Event{tradeEvent(3, 90000000, 100000000)}, start.Add(20*time.Second))...)
+
+		Convey("Trades in the same interval should update the open candles", func() {
+			So(closed, ShouldBeEmpty)
+			candles, _ := agg.Backup()
+			So(len(candles), ShouldEqual, 2)
+			So(candles[0].Interval, ShouldEqual, 60)
+			So(candles[0].OpenTime, ShouldEqual, start.Unix())
+			So(candles[0].Open, ShouldEqual, 100000000)
+			So(candles[0].High, ShouldEqual, 120000000)
+			So(candles[0].Low, ShouldEqual, 90000000)
+			So(candles[0].Close, ShouldEqual, 90000000)
+			So(candles[0].Volume, ShouldEqual, 400000000)
+			So(candles[0].QuoteVolume, ShouldEqual, 430000000)
+			So(candles[0].TradeCount, ShouldEqual, 3)
+			So(candles[0].FirstTradeSeqID, ShouldEqual, 1)
+			So(candles[0].LastTradeSeqID, ShouldEqual, 3)
+			So(candles[1].Interval, ShouldEqual, 3600)
+		})
+
+		Convey("A trade in the next interval should close the candle", func() {
+			closed = agg.AddEvents([]model.Event{tradeEvent(4, 110000000, 100000000)}, start.Add(70*time.Second))
+			So(len(closed), ShouldEqual, 1)
+			So(closed[0].Interval, ShouldEqual, 60)
+			So(closed[0].Close, ShouldEqual, 90000000)
+			candles, _ := agg.Backup()
+			So(candles[0].OpenTime, ShouldEqual, start.Unix()+60)
+			So(candles[0].Open, ShouldEqual, 110000000)
+			So(candles[1].TradeCount, ShouldEqual, 4)
+		})
+
+		Convey("Trades should be placed by the time of the input message and not by the time of the event", func() {
+			event := tradeEvent(4, 110000000, 100000000)
+			event.CreatedAt = start.Add(2 * time.Hour).UnixNano()
+			closed = agg.AddEvents([]model.Event{event}, start.Add(30*time.Second))
+			So(closed, ShouldBeEmpty)
+			candles, _ := agg.Backup()
+			So(candles[0].OpenTime, ShouldEqual, start.Unix())
+			So(candles[0].TradeCount, ShouldEqual, 4)
+		})
+
+		Convey("Candles should be closed once their interval ended", func() {
+			So(agg.Close(start.Add(59*time.Second)), ShouldBeEmpty)
+			closed = agg.Close(start.Add(time.Hour))
+			So(len(closed), ShouldEqual, 2)
+			candles, closed := agg.Backup()
+			So(candles, ShouldBeEmpty)
+			So(len(closed), ShouldEqual, 2)
+		})
+
+		Convey("A busted trade should be removed from the open candles", func() {
+			window := marketdata.TradeWindow{FromSeqID: 1, Trades: []model.Trade{
+				testTrade(1, 100000000, 100000000),
+				testTrade(3, 90000000, 100000000),
+			}}
+			busted := testTrade(2, 120000000, 200000000)
+			amended := agg.AmendTrades([]model.Event{model.NewTradeBustEvent(5, "btcusd", 100, 99, busted, nil)}, window)
+			So(amended, ShouldBeEmpty)
+			candles, _ := agg.Backup()
+			So(candles[0].High, ShouldEqual, 100000000)
+			So(candles[0].Low, ShouldEqual, 90000000)
+			So(candles[0].Volume, ShouldEqual, 200000000)
+			So(candles[0].QuoteVolume, ShouldEqual, 190000000)
+			So(candles[0].TradeCount, ShouldEqual, 2)
+			So(candles[1].High, ShouldEqual, 100000000)
+		})
+
+		Convey("A corrected trade of a closed candle should publish the amended candle", func() {
+			So(agg.Close(start.Add(time.Minute)), ShouldHaveLength, 1)
+			original := testTrade(3, 90000000, 100000000)
+			corrected := original
+			corrected.Price = 130000000
+			window := marketdata.TradeWindow{FromSeqID: 1, Trades: []model.Trade{
+				testTrade(1, 100000000, 100000000),
+				testTrade(2, 120000000, 200000000),
+				corrected,
+			}}
+			amended := agg.AmendTrades([]model.Event{model.NewTradeCorrectEvent(5, "btcusd", 100, 99, original, corrected, nil)}, window)
+			So(len(amended), ShouldEqual, 1)
+			So(amended[0].Amended, ShouldBeTrue)
+			So(amended[0].Interval, ShouldEqual, 60)
+			So(amended[0].OpenTime, ShouldEqual, start.Unix())
+			So(amended[0].High, ShouldEqual, 130000000)
+			So(amended[0].Low, ShouldEqual, 100000000)
+			So(amended[0].Close, ShouldEqual, 130000000)
+			So(amended[0].QuoteVolume, ShouldEqual, 470000000)
+			candles, _ := agg.Backup()
+			So(candles[0].Interval, ShouldEqual, 3600)
+			So(candles[0].Close, ShouldEqual, 130000000)
+		})
+
+		Convey("Trades older than the window should only be removed from the volume", func() {
+			busted := testTrade(1, 100000000, 100000000)
+			window := marketdata.TradeWindow{FromSeqID: 2}
+			agg.AmendTrades([]model.Event{model.NewTradeBustEvent(5, "btcusd", 100, 99, busted, nil)}, window)
+			candles, _ := agg.Backup()
+			So(candles[0].Volume, ShouldEqual, 300000000)
+			So(candles[0].QuoteVolume, ShouldEqual, 330000000)
+			So(candles[0].TradeCount, ShouldEqual, 2)
+			So(candles[0].Open, ShouldEqual, 100000000)
+		})
+
+		Convey("Open candles should be restored from the backup", func() {
+			restored := marketdata.NewCandleAggregator("btcusd", 8, 8, []time.Duration{time.Minute})
+			restored.Load(agg.Backup())
+			candles, _ := restored.Backup()
+			So(len(candles), ShouldEqual, 1)
+			So(candles[0].TradeCount, ShouldEqual, 3)
+		})
+	})
+
+	Convey("Candle intervals should be parsed from the configuration", t, func() {
+		interval, err := marketdata.ParseCandleInterval("5m")
+		So(err, ShouldBeNil)
+		So(interval, ShouldEqual, 5*time.Minute)
+		interval, err = marketdata.ParseCandleInterval("1d")
+		So(err, ShouldBeNil)
+		So(interval, ShouldEqual, 24*time.Hour)
+		_, err = marketdata.ParseCandleInterval("1x")
+		So(err, ShouldNotBeNil)
+		_, err = marketdata.ParseCandleInterval("500ms")
+		So(err, ShouldNotBeNil)
+	})
+}
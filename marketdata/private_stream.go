@@ -0,0 +1,131 @@
+package marketdata
+
+import (
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+// PrivateStream assigns the events of a market to the private streams of the owners they are relevant to
+// and numbers the events of each owner so gaps can be detected by the clients
+type PrivateStream struct {
+	// accounts that receive the executions of all the owners
+	dropCopy []uint64
+	// sequence of the last event of each owner
+	seqIDs map[uint64]uint64
+}
+
+// NewPrivateStream creates the private streams of a market with the given drop copy accounts
+func NewPrivateStream(dropCopy []uint64) *PrivateStream {
+	return &PrivateStream{
+		dropCopy: dropCopy,
+		seqIDs:   make(map[uint64]uint64),
+	}
+}
+
+// Route returns the events of the private streams in the order in which they were generated
+// - Trades are sent to both the ask and the bid owner, events that are not related to an owner are skipped
+func (stream *PrivateStream) Route(events []model.Event) []*model.OwnerEvent {
+	routed := make([]*model.OwnerEvent, 0, len(events))
+	for i := range events {
+		ev := &events[i]
+		owners := eventOwners(ev)
+		for _, ownerID := range owners {
+			routed = append(routed, stream.next(ownerID, ev, false))
+		}
+		if !isExecution(ev) {
+			continue
+		}
+		for _, accountID := range stream.dropCopy {
+			if !containsOwner(owners, accountID) {
+				routed = append(routed, stream.next(accountID, ev, true))
+			}
+		}
+	}
+	return routed
+}
+
+// SeqID returns the sequence of the last event of an owner
+func (stream *PrivateStream) SeqID(ownerID uint64) uint64 {
+	return stream.seqIDs[ownerID]
+}
+
+// Backup returns the sequence of the last event of each owner so it can be saved in the market backup
+func (stream *PrivateStream) Backup() map[uint64]uint64 {
+	seqIDs := make(map[uint64]uint64, len(stream.seqIDs))
+	for ownerID, seqID := range stream.seqIDs {
+		seqIDs[ownerID] = seqID
+	}
+	return seqIDs
+}
+
+// Load the sequence of the last event of each owner from the market backup
+func (stream *PrivateStream) Load(seqIDs map[uint64]uint64) {
+	stream.seqIDs = make(map[uint64]uint64, len(seqIDs))
+	for ownerID, seqID := range seqIDs {
+		stream.seqIDs[ownerID] = seqID
+	}
+}
+
+func (stream *PrivateStream) next(ownerID uint64, ev *model.Event, dropCopy bool) *model.OwnerEvent {
+	stream.seqIDs[ownerID]++
+	return &model.OwnerEvent{
+		OwnerID:    ownerID,
+		OwnerSeqID: stream.seqIDs[ownerID],
+		DropCopy:   dropCopy,
+		Event:      ev,
+	}
+}
+
+// eventOwners returns the owners the event is relevant to
+func eventOwners(ev *model.Event) []uint64 {
+	switch ev.Type {
+	case model.EventType_OrderStatusChange:
+		return ownerList(ev.GetOrderStatus().GetOwnerID())
+	case model.EventType_OrderActivated:
+		return ownerList(ev.GetOrderActivation().GetOwnerID())
+	case model.EventType_Error:
+		return ownerList(ev.GetError().GetOwnerID())
+	case model.EventType_NewTrade:
+		return tradeOwners(ev.GetTrade())
+	case model.EventType_TradeBusted:
+		return tradeOwners(ev.GetTradeBust().GetTrade())
+	case model.EventType_TradeCorrected:
+		return tradeOwners(ev.GetTradeCorrect().GetCorrected())
+	}
+	return nil
+}
+
+// isExecution checks if the event is a trade or a change of a trade sent to the drop copy accounts
+func isExecution(ev *model.Event) bool {
+	switch ev.Type {
+	case model.EventType_NewTrade, model.EventType_TradeBusted, model.EventType_TradeCorrected:
+		return true
+	}
+	return false
+}
+
+func tradeOwners(trade *model.Trade) []uint64 {
+	if trade == nil {
+		return nil
+	}
+	owners := ownerList(trade.AskOwnerID)
+	if trade.BidOwnerID != trade.AskOwnerID {
+		owners = append(owners, ownerList(trade.BidOwnerID)...)
+	}
+	return owners
+}
+
+func ownerList(ownerID uint64) []uint64 {
+	if ownerID == 0 {
+		return nil
+	}
+	return []uint64{ownerID}
+}
+
+func containsOwner(owners []uint64, ownerID uint64) bool {
+	for _, owner := range owners {
+		if owner == ownerID {
+			return true
+		}
+	}
+	return false
+}
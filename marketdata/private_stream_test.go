@@ -0,0 +1,71 @@
+package marketdata_test
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/marketdata"
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPrivateStream(t *testing.T) {
+	events := []model.Event{
+		model.NewOrderStatusEvent(1, "btcusd", model.OrderType_Limit, model.MarketSide_Buy, 2, 20, "", 100, 3, 0, model.OrderStatus_Pending, 0, 0),
+		model.NewTradeEvent(2, "btcusd", 1, model.MarketSide_Buy, 1, 2, 10, 20, "", "", 3, 100),
+		model.NewBBOEvent(3, "btcusd", 0, 0, 100, 2),
+		model.NewOrderStatusEvent(4, "btcusd", model.OrderType_Limit, model.MarketSide_Sell, 1, 10, "", 100, 5, 0, model.OrderStatus_PartiallyFilled, 3, 0),
+		model.NewErrorEvent(5, "btcusd", model.ErrorCode_InvalidOrder, model.OrderType_Limit, model.MarketSide_Buy, 3, 20, "", 0, 0, 0),
+	}
+
+	Convey("Given the private streams of a market with a drop copy account", t, func() {
+		stream := marketdata.NewPrivateStream([]uint64{99})
+		routed := stream.Route(events)
+
+		Convey("Every event of an owner should be sent on its stream in order", func() {
+			owners := make([]uint64, len(routed))
+			for i, ev := range routed {
+				owners[i] = ev.OwnerID
+			}
+			So(owners, ShouldResemble, []uint64{20, 10, 20, 99, 10, 20})
+			So(routed[0].Event.SeqID, ShouldEqual, 1)
+			So(routed[1].Event.Type, ShouldEqual, model.EventType_NewTrade)
+			So(routed[2].Event.Type, ShouldEqual, model.EventType_NewTrade)
+			So(routed[5].Event.Type, ShouldEqual, model.EventType_Error)
+		})
+
+		Convey("The events of each owner should have consecutive sequence numbers", func() {
+			So(routed[0].OwnerSeqID, ShouldEqual, 1)
+			So(routed[2].OwnerSeqID, ShouldEqual, 2)
+			So(routed[5].OwnerSeqID, ShouldEqual, 3)
+			So(routed[1].OwnerSeqID, ShouldEqual, 1)
+			So(routed[4].OwnerSeqID, ShouldEqual, 2)
+			So(stream.SeqID(20), ShouldEqual, 3)
+		})
+
+		Convey("The drop copy account should receive a copy of the trades", func() {
+			So(routed[3].OwnerID, ShouldEqual, 99)
+			So(routed[3].DropCopy, ShouldBeTrue)
+			So(routed[3].OwnerSeqID, ShouldEqual, 1)
+			So(routed[3].Event.Type, ShouldEqual, model.EventType_NewTrade)
+			So(routed[0].DropCopy, ShouldBeFalse)
+		})
+
+		Convey("The sequence numbers should continue after loading the backup", func() {
+			restored := marketdata.NewPrivateStream([]uint64{99})
+			restored.Load(stream.Backup())
+			next := restored.Route(events[:1])
+			So(next, ShouldHaveLength, 1)
+			So(next[0].OwnerSeqID, ShouldEqual, 4)
+		})
+	})
+
+	Convey("A trade between orders of the same owner should be sent once", t, func() {
+		stream := marketdata.NewPrivateStream(nil)
+		routed := stream.Route([]model.Event{
+			model.NewTradeEvent(1, "btcusd", 1, model.MarketSide_Buy, 1, 2, 10, 10, "", "", 3, 100),
+		})
+		So(routed, ShouldHaveLength, 1)
+		So(routed[0].OwnerID, ShouldEqual, 10)
+	})
+}
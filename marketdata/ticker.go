@@ -0,0 +1,129 @@
+package marketdata
+
+import (
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/utils"
+)
+
+// TickerWindow is the length of the rolling window of the ticker statistics
+const TickerWindow = 24 * time.Hour
+
+// the trades are grouped in one minute buckets so the window moves with a one minute granularity
+const tickerBucket = int64(60)
+
+// Ticker keeps the statistics of the trades of a market in a rolling 24 hour window
+type Ticker struct {
+	market          string
+	pricePrecision  int
+	volumePrecision int
+	// one minute buckets sorted from the oldest one
+	buckets []*model.Candle
+}
+
+// NewTicker creates a new empty ticker for the given market
+func NewTicker(market string, pricePrecision, volumePrecision int) *Ticker {
+	return &Ticker{
+		market:          market,
+		pricePrecision:  pricePrecision,
+		volumePrecision: volumePrecision,
+		buckets:         make([]*model.Candle, 0),
+	}
+}
+
+// AddEvents updates the ticker with the trades found in the given events
+// The trades are placed in the bucket of the time of the input message that generated the events, like the candles.
+func (ticker *Ticker) AddEvents(events []model.Event, at time.Time) {
+	for i := range events {
+		if events[i].Type != model.EventType_NewTrade {
+			continue
+		}
+		ticker.AddTrade(events[i].GetTrade(), at)
+	}
+}
+
+// AddTrade updates the ticker with a new trade
+func (ticker *Ticker) AddTrade(trade *model.Trade, at time.Time) {
+	quoteVolume := utils.Multiply(trade.Amount, trade.Price, ticker.volumePrecision, ticker.pricePrecision, ticker.pricePrecision)
+	openTime := at.Unix() - at.Unix()%tickerBucket
+	last := len(ticker.buckets) - 1
+	if last < 0 || ticker.buckets[last].OpenTime < openTime {
+		ticker.buckets = append(ticker.buckets, newCandle(ticker.market, tickerBucket, trade, at))
+		last++
+	}
+	updateCandle(ticker.buckets[last], trade, quoteVolume)
+}
+
+// AmendTrades removes the busted trades from the buckets of the window and applies the corrected ones
+func (ticker *Ticker) AmendTrades(events []model.Event, window TradeWindow) {
+	for i := range events {
+		original, corrected, ok := amendedTrade(&events[i])
+		if !ok {
+			continue
+		}
+		for j, bucket := range ticker.buckets {
+			if !includesTrade(bucket, original.SeqID) {
+				continue
+			}
+			amendCandle(bucket, original, corrected, window, ticker.pricePrecision, ticker.volumePrecision)
+			if bucket.TradeCount == 0 {
+				ticker.buckets = append(ticker.buckets[:j], ticker.buckets[j+1:]...)
+			}
+			break
+		}
+	}
+}
+
+// Stats removes the buckets that are no longer in the window and returns the statistics of the remaining trades
+func (ticker *Ticker) Stats(now time.Time) *model.Ticker {
+	closeTime := now.Unix()
+	openTime := now.Add(-TickerWindow).Unix()
+	expired := 0
+	for expired < len(ticker.buckets) && ticker.buckets[expired].OpenTime+tickerBucket <= openTime {
+		expired++
+	}
+	ticker.buckets = ticker.buckets[expired:]
+
+	stats := &model.Ticker{Market: ticker.market, OpenTime: openTime, CloseTime: closeTime}
+	for i, bucket := range ticker.buckets {
+		if i == 0 {
+			stats.Open = bucket.Open
+			stats.Low = bucket.Low
+		}
+		if bucket.High > stats.High {
+			stats.High = bucket.High
+		}
+		if bucket.Low < stats.Low {
+			stats.Low = bucket.Low
+		}
+		stats.Last = bucket.Close
+		stats.Volume += bucket.Volume
+		stats.QuoteVolume += bucket.QuoteVolume
+		stats.TradeCount += bucket.TradeCount
+		stats.LastTradeSeqID = bucket.LastTradeSeqID
+	}
+	if stats.Volume != 0 {
+		stats.VWAP = utils.Divide(stats.QuoteVolume, stats.Volume, ticker.pricePrecision, ticker.volumePrecision, ticker.pricePrecision)
+	}
+	return stats
+}
+
+// Backup returns the buckets of the window so they can be saved in the market backup
+func (ticker *Ticker) Backup() []*model.Candle {
+	buckets := make([]*model.Candle, len(ticker.buckets))
+	for i, bucket := range ticker.buckets {
+		buckets[i] = proto.Clone(bucket).(*model.Candle)
+	}
+	return buckets
+}
+
+// Load the buckets of the window from the market backup
+func (ticker *Ticker) Load(buckets []*model.Candle) {
+	ticker.buckets = make([]*model.Candle, len(buckets))
+	for i, bucket := range buckets {
+		ticker.buckets[i] = proto.Clone(bucket).(*model.Candle)
+	}
+}
@@ -0,0 +1,91 @@
+package marketdata_test
+
+import (
+	"testing"
+	"time"
+
+	"gitlab.com/around25/products/matching-engine/marketdata"
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTicker(t *testing.T) {
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	Convey("Given a ticker with trades in different minutes", t, func() {
+		ticker := marketdata.NewTicker("btcusd", 8, 8)
+		ticker.AddEvents([]model.Event{
+			tradeEvent(1, 100000000, 100000000),
+			tradeEvent(2, 120000000, 200000000),
+		}, start.Add(5*time.Second))
+		ticker.AddEvents([]model.Event{tradeEvent(3, 90000000, 100000000)}, start.Add(2*time.Hour))
+
+		Convey("The statistics should include all trades in the window", func() {
+			stats := ticker.Stats(start.Add(3 * time.Hour))
+			So(stats.Market, ShouldEqual, "btcusd")
+			So(stats.CloseTime, ShouldEqual, start.Add(3*time.Hour).Unix())
+			So(stats.OpenTime, ShouldEqual, start.Add(3*time.Hour-marketdata.TickerWindow).Unix())
+			So(stats.Open, ShouldEqual, 100000000)
+			So(stats.High, ShouldEqual, 120000000)
+			So(stats.Low, ShouldEqual, 90000000)
+			So(stats.Last, ShouldEqual, 90000000)
+			So(stats.Volume, ShouldEqual, 400000000)
+			So(stats.QuoteVolume, ShouldEqual, 430000000)
+			So(stats.VWAP, ShouldEqual, 107500000)
+			So(stats.TradeCount, ShouldEqual, 3)
+			So(stats.LastTradeSeqID, ShouldEqual, 3)
+		})
+
+		Convey("Trades older than the window should be removed", func() {
+			stats := ticker.Stats(start.Add(marketdata.TickerWindow + time.Hour))
+			So(stats.Open, ShouldEqual, 90000000)
+			So(stats.High, ShouldEqual, 90000000)
+			So(stats.Volume, ShouldEqual, 100000000)
+			So(stats.TradeCount, ShouldEqual, 1)
+			So(len(ticker.Backup()), ShouldEqual, 1)
+		})
+
+		Convey("An empty window should have no statistics", func() {
+			stats := ticker.Stats(start.Add(2 * marketdata.TickerWindow))
+			So(stats.TradeCount, ShouldEqual, 0)
+			So(stats.VWAP, ShouldEqual, 0)
+			So(ticker.Backup(), ShouldBeEmpty)
+		})
+
+		Convey("A busted trade should be removed from the statistics", func() {
+			window := marketdata.TradeWindow{FromSeqID: 1, Trades: []model.Trade{testTrade(1, 100000000, 100000000), testTrade(3, 90000000, 100000000)}}
+			ticker.AmendTrades([]model.Event{model.NewTradeBustEvent(5, "btcusd", 100, 99, testTrade(2, 120000000, 200000000), nil)}, window)
+			stats := ticker.Stats(start.Add(3 * time.Hour))
+			So(stats.High, ShouldEqual, 100000000)
+			So(stats.Volume, ShouldEqual, 200000000)
+			So(stats.QuoteVolume, ShouldEqual, 190000000)
+			So(stats.VWAP, ShouldEqual, 95000000)
+			So(stats.TradeCount, ShouldEqual, 2)
+
+			Convey("and a bucket left without trades should be removed", func() {
+				window.Trades = window.Trades[:1]
+				ticker.AmendTrades([]model.Event{model.NewTradeBustEvent(6, "btcusd", 101, 99, testTrade(3, 90000000, 100000000), nil)}, window)
+				stats := ticker.Stats(start.Add(3 * time.Hour))
+				So(stats.Last, ShouldEqual, 100000000)
+				So(stats.Low, ShouldEqual, 100000000)
+				So(stats.TradeCount, ShouldEqual, 1)
+				So(len(ticker.Backup()), ShouldEqual, 1)
+			})
+		})
+
+		Convey("The window should be restored from the backup", func() {
+			restored := marketdata.NewTicker("btcusd", 8, 8)
+			restored.Load(ticker.Backup())
+			So(restored.Stats(start.Add(3*time.Hour)), ShouldResemble, ticker.Stats(start.Add(3*time.Hour)))
+		})
+	})
+	Convey("The VWAP should be computed when the price and the volume precisions differ", t, func() {
+		ticker := marketdata.NewTicker("btcusd", 2, 8)
+		ticker.AddEvents([]model.Event{tradeEvent(1, 10000, 100000000), tradeEvent(2, 12000, 300000000)}, start)
+		stats := ticker.Stats(start.Add(time.Minute))
+		So(stats.QuoteVolume, ShouldEqual, 46000)
+		So(stats.Volume, ShouldEqual, 400000000)
+		So(stats.VWAP, ShouldEqual, 11500)
+	})
+}
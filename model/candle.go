@@ -0,0 +1,15 @@
+package model
+
+import (
+	proto "github.com/golang/protobuf/proto"
+)
+
+// FromBinary loads a candle from a byte array
+func (candle *Candle) FromBinary(msg []byte) error {
+	return proto.Unmarshal(msg, candle)
+}
+
+// ToBinary converts a candle to a byte string
+func (candle *Candle) ToBinary() ([]byte, error) {
+	return proto.Marshal(candle)
+}
@@ -0,0 +1,274 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.14.0
+// source: candle.proto
+
+package model
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// Candle contains the open, high, low, close prices and the volume of the trades of a market in a time interval
+type Candle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Market string `protobuf:"bytes,1,opt,name=Market,proto3" json:"Market,omitempty"`
+	// The length of the interval in seconds
+	Interval int64 `protobuf:"varint,2,opt,name=Interval,proto3" json:"Interval,omitempty"`
+	// The start of the interval as a unix timestamp in seconds
+	OpenTime int64  `protobuf:"varint,3,opt,name=OpenTime,proto3" json:"OpenTime,omitempty"`
+	Open     uint64 `protobuf:"varint,4,opt,name=Open,proto3" json:"Open,omitempty"`
+	High     uint64 `protobuf:"varint,5,opt,name=High,proto3" json:"High,omitempty"`
+	Low      uint64 `protobuf:"varint,6,opt,name=Low,proto3" json:"Low,omitempty"`
+	Close    uint64 `protobuf:"varint,7,opt,name=Close,proto3" json:"Close,omitempty"`
+	// The traded amount in the base currency
+	Volume uint64 `protobuf:"varint,8,opt,name=Volume,proto3" json:"Volume,omitempty"`
+	// The traded amount in the quote currency
+	QuoteVolume uint64 `protobuf:"varint,9,opt,name=QuoteVolume,proto3" json:"QuoteVolume,omitempty"`
+	TradeCount  uint64 `protobuf:"varint,10,opt,name=TradeCount,proto3" json:"TradeCount,omitempty"`
+	// The sequence ids of the first and the last trade included in the candle
+	FirstTradeSeqID uint64 `protobuf:"varint,11,opt,name=FirstTradeSeqID,proto3" json:"FirstTradeSeqID,omitempty"`
+	LastTradeSeqID  uint64 `protobuf:"varint,12,opt,name=LastTradeSeqID,proto3" json:"LastTradeSeqID,omitempty"`
+	// Set when the candle replaces a closed candle already published for the same interval and open time because
+	// one of its trades was busted or corrected
+	Amended bool `protobuf:"varint,13,opt,name=Amended,proto3" json:"Amended,omitempty"`
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_candle_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_candle_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_candle_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Candle) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *Candle) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+func (x *Candle) GetOpenTime() int64 {
+	if x != nil {
+		return x.OpenTime
+	}
+	return 0
+}
+
+func (x *Candle) GetOpen() uint64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Candle) GetHigh() uint64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Candle) GetLow() uint64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Candle) GetClose() uint64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *Candle) GetVolume() uint64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *Candle) GetQuoteVolume() uint64 {
+	if x != nil {
+		return x.QuoteVolume
+	}
+	return 0
+}
+
+func (x *Candle) GetTradeCount() uint64 {
+	if x != nil {
+		return x.TradeCount
+	}
+	return 0
+}
+
+func (x *Candle) GetFirstTradeSeqID() uint64 {
+	if x != nil {
+		return x.FirstTradeSeqID
+	}
+	return 0
+}
+
+func (x *Candle) GetLastTradeSeqID() uint64 {
+	if x != nil {
+		return x.LastTradeSeqID
+	}
+	return 0
+}
+
+func (x *Candle) GetAmended() bool {
+	if x != nil {
+		return x.Amended
+	}
+	return false
+}
+
+var File_candle_proto protoreflect.FileDescriptor
+
+var file_candle_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x63, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x22, 0xee, 0x02, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x6e, 0x54, 0x69, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x4f, 0x70, 0x65, 0x6e, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x4f, 0x70, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04,
+	0x4f, 0x70, 0x65, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x48, 0x69, 0x67, 0x68, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x04, 0x48, 0x69, 0x67, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x4c, 0x6f, 0x77, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x4c, 0x6f, 0x77, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6c,
+	0x6f, 0x73, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x43, 0x6c, 0x6f, 0x73, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x51, 0x75, 0x6f, 0x74,
+	0x65, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x51,
+	0x75, 0x6f, 0x74, 0x65, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x54, 0x72,
+	0x61, 0x64, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a,
+	0x54, 0x72, 0x61, 0x64, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x0f, 0x46, 0x69,
+	0x72, 0x73, 0x74, 0x54, 0x72, 0x61, 0x64, 0x65, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0f, 0x46, 0x69, 0x72, 0x73, 0x74, 0x54, 0x72, 0x61, 0x64, 0x65, 0x53,
+	0x65, 0x71, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x4c, 0x61, 0x73, 0x74, 0x54, 0x72, 0x61, 0x64,
+	0x65, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x4c, 0x61,
+	0x73, 0x74, 0x54, 0x72, 0x61, 0x64, 0x65, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07,
+	0x41, 0x6d, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x41,
+	0x6d, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x35, 0x2f, 0x70, 0x72,
+	0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x2d,
+	0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_candle_proto_rawDescOnce sync.Once
+	file_candle_proto_rawDescData = file_candle_proto_rawDesc
+)
+
+func file_candle_proto_rawDescGZIP() []byte {
+	file_candle_proto_rawDescOnce.Do(func() {
+		file_candle_proto_rawDescData = protoimpl.X.CompressGZIP(file_candle_proto_rawDescData)
+	})
+	return file_candle_proto_rawDescData
+}
+
+var file_candle_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_candle_proto_goTypes = []interface{}{
+	(*Candle)(nil), // 0: model.Candle
+}
+var file_candle_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_candle_proto_init() }
+func file_candle_proto_init() {
+	if File_candle_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_candle_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Candle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_candle_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_candle_proto_goTypes,
+		DependencyIndexes: file_candle_proto_depIdxs,
+		MessageInfos:      file_candle_proto_msgTypes,
+	}.Build()
+	File_candle_proto = out.File
+	file_candle_proto_rawDesc = nil
+	file_candle_proto_goTypes = nil
+	file_candle_proto_depIdxs = nil
+}
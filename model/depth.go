@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+// NewDepthSnapshotMessage returns a new depth message with a full snapshot of the price levels
+func NewDepthSnapshotMessage(market string, snapshot DepthSnapshot) DepthMessage {
+	return DepthMessage{
+		Market:    market,
+		Payload:   &DepthMessage_Snapshot{Snapshot: &snapshot},
+		CreatedAt: time.Now().UTC().UnixNano(),
+	}
+}
+
+// NewDepthUpdateMessage returns a new depth message with the price levels changed by a command
+func NewDepthUpdateMessage(market string, update DepthUpdate) DepthMessage {
+	return DepthMessage{
+		Market:    market,
+		Payload:   &DepthMessage_Update{Update: &update},
+		CreatedAt: time.Now().UTC().UnixNano(),
+	}
+}
+
+// FromBinary loads a depth message from a byte array
+func (msg *DepthMessage) FromBinary(raw []byte) error {
+	return proto.Unmarshal(raw, msg)
+}
+
+// ToBinary converts a depth message to a byte string
+func (msg *DepthMessage) ToBinary() ([]byte, error) {
+	return proto.Marshal(msg)
+}
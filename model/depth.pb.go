@@ -0,0 +1,499 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.14.0
+// source: depth.proto
+
+package model
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// DepthLevel is the total amount of the open orders at a price in the order book
+type DepthLevel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Price uint64 `protobuf:"varint,1,opt,name=Price,proto3" json:"Price,omitempty"`
+	// The total amount left to be filled for all orders at the price. Zero means the level was removed.
+	Amount uint64 `protobuf:"varint,2,opt,name=Amount,proto3" json:"Amount,omitempty"`
+}
+
+func (x *DepthLevel) Reset() {
+	*x = DepthLevel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depth_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DepthLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepthLevel) ProtoMessage() {}
+
+func (x *DepthLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_depth_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepthLevel.ProtoReflect.Descriptor instead.
+func (*DepthLevel) Descriptor() ([]byte, []int) {
+	return file_depth_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DepthLevel) GetPrice() uint64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *DepthLevel) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+// DepthSnapshot contains the aggregated price levels of the order book
+type DepthSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The sequence id of the last event included in the snapshot
+	SeqID uint64 `protobuf:"varint,1,opt,name=SeqID,proto3" json:"SeqID,omitempty"`
+	// Bids sorted from the highest price
+	Bids []*DepthLevel `protobuf:"bytes,2,rep,name=Bids,proto3" json:"Bids,omitempty"`
+	// Asks sorted from the lowest price
+	Asks []*DepthLevel `protobuf:"bytes,3,rep,name=Asks,proto3" json:"Asks,omitempty"`
+	// CRC32 checksum of the top price levels of the order book
+	Checksum uint32 `protobuf:"varint,4,opt,name=Checksum,proto3" json:"Checksum,omitempty"`
+}
+
+func (x *DepthSnapshot) Reset() {
+	*x = DepthSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depth_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DepthSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepthSnapshot) ProtoMessage() {}
+
+func (x *DepthSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_depth_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepthSnapshot.ProtoReflect.Descriptor instead.
+func (*DepthSnapshot) Descriptor() ([]byte, []int) {
+	return file_depth_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DepthSnapshot) GetSeqID() uint64 {
+	if x != nil {
+		return x.SeqID
+	}
+	return 0
+}
+
+func (x *DepthSnapshot) GetBids() []*DepthLevel {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *DepthSnapshot) GetAsks() []*DepthLevel {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *DepthSnapshot) GetChecksum() uint32 {
+	if x != nil {
+		return x.Checksum
+	}
+	return 0
+}
+
+// DepthUpdate contains the price levels changed by a single command with their new amount
+type DepthUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The sequence id of the last event generated by the command that changed the levels
+	SeqID uint64 `protobuf:"varint,1,opt,name=SeqID,proto3" json:"SeqID,omitempty"`
+	// The sequence id of the previous depth update used to detect gaps
+	PrevSeqID uint64 `protobuf:"varint,2,opt,name=PrevSeqID,proto3" json:"PrevSeqID,omitempty"`
+	// Changed bids sorted from the highest price
+	Bids []*DepthLevel `protobuf:"bytes,3,rep,name=Bids,proto3" json:"Bids,omitempty"`
+	// Changed asks sorted from the lowest price
+	Asks []*DepthLevel `protobuf:"bytes,4,rep,name=Asks,proto3" json:"Asks,omitempty"`
+	// CRC32 checksum of the top price levels of the order book after the update
+	Checksum uint32 `protobuf:"varint,5,opt,name=Checksum,proto3" json:"Checksum,omitempty"`
+}
+
+func (x *DepthUpdate) Reset() {
+	*x = DepthUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depth_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DepthUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepthUpdate) ProtoMessage() {}
+
+func (x *DepthUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_depth_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepthUpdate.ProtoReflect.Descriptor instead.
+func (*DepthUpdate) Descriptor() ([]byte, []int) {
+	return file_depth_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DepthUpdate) GetSeqID() uint64 {
+	if x != nil {
+		return x.SeqID
+	}
+	return 0
+}
+
+func (x *DepthUpdate) GetPrevSeqID() uint64 {
+	if x != nil {
+		return x.PrevSeqID
+	}
+	return 0
+}
+
+func (x *DepthUpdate) GetBids() []*DepthLevel {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *DepthUpdate) GetAsks() []*DepthLevel {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *DepthUpdate) GetChecksum() uint32 {
+	if x != nil {
+		return x.Checksum
+	}
+	return 0
+}
+
+// DepthMessage is published on the depth topic of the market
+type DepthMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Market    string `protobuf:"bytes,1,opt,name=Market,proto3" json:"Market,omitempty"`
+	CreatedAt int64  `protobuf:"varint,2,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	// Types that are assignable to Payload:
+	//	*DepthMessage_Snapshot
+	//	*DepthMessage_Update
+	Payload isDepthMessage_Payload `protobuf_oneof:"Payload"`
+}
+
+func (x *DepthMessage) Reset() {
+	*x = DepthMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_depth_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DepthMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepthMessage) ProtoMessage() {}
+
+func (x *DepthMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_depth_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepthMessage.ProtoReflect.Descriptor instead.
+func (*DepthMessage) Descriptor() ([]byte, []int) {
+	return file_depth_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DepthMessage) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *DepthMessage) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (m *DepthMessage) GetPayload() isDepthMessage_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (x *DepthMessage) GetSnapshot() *DepthSnapshot {
+	if x, ok := x.GetPayload().(*DepthMessage_Snapshot); ok {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *DepthMessage) GetUpdate() *DepthUpdate {
+	if x, ok := x.GetPayload().(*DepthMessage_Update); ok {
+		return x.Update
+	}
+	return nil
+}
+
+type isDepthMessage_Payload interface {
+	isDepthMessage_Payload()
+}
+
+type DepthMessage_Snapshot struct {
+	Snapshot *DepthSnapshot `protobuf:"bytes,3,opt,name=Snapshot,proto3,oneof"`
+}
+
+type DepthMessage_Update struct {
+	Update *DepthUpdate `protobuf:"bytes,4,opt,name=Update,proto3,oneof"`
+}
+
+func (*DepthMessage_Snapshot) isDepthMessage_Payload() {}
+
+func (*DepthMessage_Update) isDepthMessage_Payload() {}
+
+var File_depth_proto protoreflect.FileDescriptor
+
+var file_depth_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x64, 0x65, 0x70, 0x74, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x22, 0x3a, 0x0a, 0x0a, 0x44, 0x65, 0x70, 0x74, 0x68, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x22, 0x8f, 0x01, 0x0a, 0x0d, 0x44, 0x65, 0x70, 0x74, 0x68, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x25, 0x0a, 0x04, 0x42, 0x69, 0x64, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x44,
+	0x65, 0x70, 0x74, 0x68, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x04, 0x42, 0x69, 0x64, 0x73, 0x12,
+	0x25, 0x0a, 0x04, 0x41, 0x73, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x44, 0x65, 0x70, 0x74, 0x68, 0x4c, 0x65, 0x76, 0x65, 0x6c,
+	0x52, 0x04, 0x41, 0x73, 0x6b, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73,
+	0x75, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73,
+	0x75, 0x6d, 0x22, 0xab, 0x01, 0x0a, 0x0b, 0x44, 0x65, 0x70, 0x74, 0x68, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x50, 0x72, 0x65, 0x76,
+	0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x50, 0x72, 0x65,
+	0x76, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x25, 0x0a, 0x04, 0x42, 0x69, 0x64, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x44, 0x65, 0x70,
+	0x74, 0x68, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x04, 0x42, 0x69, 0x64, 0x73, 0x12, 0x25, 0x0a,
+	0x04, 0x41, 0x73, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x2e, 0x44, 0x65, 0x70, 0x74, 0x68, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x04,
+	0x41, 0x73, 0x6b, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d,
+	0x22, 0xb1, 0x01, 0x0a, 0x0c, 0x44, 0x65, 0x70, 0x74, 0x68, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x32, 0x0a, 0x08, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x2e, 0x44, 0x65, 0x70, 0x74, 0x68, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x48,
+	0x00, 0x52, 0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x2c, 0x0a, 0x06, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x2e, 0x44, 0x65, 0x70, 0x74, 0x68, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x48,
+	0x00, 0x52, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x50, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x35, 0x2f, 0x70, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x2d, 0x65, 0x6e,
+	0x67, 0x69, 0x6e, 0x65, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_depth_proto_rawDescOnce sync.Once
+	file_depth_proto_rawDescData = file_depth_proto_rawDesc
+)
+
+func file_depth_proto_rawDescGZIP() []byte {
+	file_depth_proto_rawDescOnce.Do(func() {
+		file_depth_proto_rawDescData = protoimpl.X.CompressGZIP(file_depth_proto_rawDescData)
+	})
+	return file_depth_proto_rawDescData
+}
+
+var file_depth_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_depth_proto_goTypes = []interface{}{
+	(*DepthLevel)(nil),    // 0: model.DepthLevel
+	(*DepthSnapshot)(nil), // 1: model.DepthSnapshot
+	(*DepthUpdate)(nil),   // 2: model.DepthUpdate
+	(*DepthMessage)(nil),  // 3: model.DepthMessage
+}
+var file_depth_proto_depIdxs = []int32{
+	0, // 0: model.DepthSnapshot.Bids:type_name -> model.DepthLevel
+	0, // 1: model.DepthSnapshot.Asks:type_name -> model.DepthLevel
+	0, // 2: model.DepthUpdate.Bids:type_name -> model.DepthLevel
+	0, // 3: model.DepthUpdate.Asks:type_name -> model.DepthLevel
+	1, // 4: model.DepthMessage.Snapshot:type_name -> model.DepthSnapshot
+	2, // 5: model.DepthMessage.Update:type_name -> model.DepthUpdate
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_depth_proto_init() }
+func file_depth_proto_init() {
+	if File_depth_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_depth_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DepthLevel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depth_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DepthSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depth_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DepthUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_depth_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DepthMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_depth_proto_msgTypes[3].OneofWrappers = []interface{}{
+		(*DepthMessage_Snapshot)(nil),
+		(*DepthMessage_Update)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_depth_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_depth_proto_goTypes,
+		DependencyIndexes: file_depth_proto_depIdxs,
+		MessageInfos:      file_depth_proto_msgTypes,
+	}.Build()
+	File_depth_proto = out.File
+	file_depth_proto_rawDesc = nil
+	file_depth_proto_goTypes = nil
+	file_depth_proto_depIdxs = nil
+}
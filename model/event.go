@@ -6,46 +6,59 @@ import (
 	proto "github.com/golang/protobuf/proto"
 )
 
+// EventSchemaVersion is the version of the event messages published by the engine
+// - Increased when a change of the messages can't be read by the existing consumers
+const EventSchemaVersion = 1
+
 // NewOrderStatusEvent returns a new event set with the order status details
-func NewOrderStatusEvent(seqID uint64, market string, orderType OrderType, side MarketSide, id, ownerID uint64, price, amount, funds uint64, status OrderStatus, filledAmount uint64, usedFunds uint64) Event {
+func NewOrderStatusEvent(seqID uint64, market string, orderType OrderType, side MarketSide, id, ownerID uint64, clientOrderID string, price, amount, funds uint64, status OrderStatus, filledAmount uint64, usedFunds uint64) Event {
 	return Event{
 		SeqID:  seqID,
 		Type:   EventType_OrderStatusChange,
 		Market: market,
 		Payload: &Event_OrderStatus{
 			OrderStatus: &OrderStatusMsg{
-				ID:           id,
-				Type:         orderType,
-				Side:         side,
-				OwnerID:      ownerID,
-				Price:        price,
-				Amount:       amount,
-				Funds:        funds,
-				Status:       status,
-				FilledAmount: filledAmount,
-				UsedFunds:    usedFunds,
+				ID:            id,
+				Type:          orderType,
+				Side:          side,
+				OwnerID:       ownerID,
+				ClientOrderID: clientOrderID,
+				Price:         price,
+				Amount:        amount,
+				Funds:         funds,
+				Status:        status,
+				FilledAmount:  filledAmount,
+				UsedFunds:     usedFunds,
 			},
 		},
 		CreatedAt: time.Now().UTC().UnixNano(),
 	}
 }
 
+// NewOrderCancelledEvent returns a new event with the details of a cancelled order and the reason it was cancelled
+func NewOrderCancelledEvent(seqID uint64, market string, orderType OrderType, side MarketSide, id, ownerID uint64, clientOrderID string, price, amount, funds uint64, filledAmount uint64, usedFunds uint64, reason CancelReason) Event {
+	event := NewOrderStatusEvent(seqID, market, orderType, side, id, ownerID, clientOrderID, price, amount, funds, OrderStatus_Cancelled, filledAmount, usedFunds)
+	event.GetOrderStatus().Reason = reason
+	return event
+}
+
 // NewOrderActivatedEvent returns a new event set with the order status details
-func NewOrderActivatedEvent(seqID uint64, market string, orderType OrderType, side MarketSide, id, ownerID uint64, price, amount, funds uint64, status OrderStatus) Event {
+func NewOrderActivatedEvent(seqID uint64, market string, orderType OrderType, side MarketSide, id, ownerID uint64, clientOrderID string, price, amount, funds uint64, status OrderStatus) Event {
 	return Event{
 		SeqID:  seqID,
 		Type:   EventType_OrderActivated,
 		Market: market,
 		Payload: &Event_OrderActivation{
 			OrderActivation: &OrderStatusMsg{
-				ID:      id,
-				Type:    orderType,
-				Side:    side,
-				OwnerID: ownerID,
-				Amount:  amount,
-				Funds:   funds,
-				Price:   price,
-				Status:  status,
+				ID:            id,
+				Type:          orderType,
+				Side:          side,
+				OwnerID:       ownerID,
+				ClientOrderID: clientOrderID,
+				Amount:        amount,
+				Funds:         funds,
+				Price:         price,
+				Status:        status,
 			},
 		},
 		CreatedAt: time.Now().UTC().UnixNano(),
@@ -53,21 +66,78 @@ func NewOrderActivatedEvent(seqID uint64, market string, orderType OrderType, si
 }
 
 // NewTradeEvent returns a new event set with the trade details
-func NewTradeEvent(seqID uint64, market string, tradeSeqID uint64, takerSide MarketSide, askID, bidID, askOwnerID, bidOwnerID, amount, price uint64) Event {
+func NewTradeEvent(seqID uint64, market string, tradeSeqID uint64, takerSide MarketSide, askID, bidID, askOwnerID, bidOwnerID uint64, askClientOrderID, bidClientOrderID string, amount, price uint64) Event {
 	return Event{
 		SeqID:  seqID,
 		Type:   EventType_NewTrade,
 		Market: market,
 		Payload: &Event_Trade{
 			Trade: &Trade{
-				TakerSide:  takerSide,
-				AskID:      askID,
-				AskOwnerID: askOwnerID,
-				BidID:      bidID,
-				BidOwnerID: bidOwnerID,
-				Amount:     amount,
-				Price:      price,
-				SeqID:      tradeSeqID,
+				TakerSide:        takerSide,
+				AskID:            askID,
+				AskOwnerID:       askOwnerID,
+				AskClientOrderID: askClientOrderID,
+				BidID:            bidID,
+				BidOwnerID:       bidOwnerID,
+				BidClientOrderID: bidClientOrderID,
+				Amount:           amount,
+				Price:            price,
+				SeqID:            tradeSeqID,
+			},
+		},
+		CreatedAt: time.Now().UTC().UnixNano(),
+	}
+}
+
+// NewTradeBustEvent returns a new event with the details of a busted trade
+func NewTradeBustEvent(seqID uint64, market string, requestID, operatorID uint64, trade Trade, restoredOrderIDs []uint64) Event {
+	return Event{
+		SeqID:  seqID,
+		Type:   EventType_TradeBusted,
+		Market: market,
+		Payload: &Event_TradeBust{
+			TradeBust: &TradeBustMsg{
+				RequestID:        requestID,
+				OperatorID:       operatorID,
+				Trade:            &trade,
+				RestoredOrderIDs: restoredOrderIDs,
+			},
+		},
+		CreatedAt: time.Now().UTC().UnixNano(),
+	}
+}
+
+// NewTradeCorrectEvent returns a new event with the original and the corrected trade
+func NewTradeCorrectEvent(seqID uint64, market string, requestID, operatorID uint64, original, corrected Trade, restoredOrderIDs []uint64) Event {
+	return Event{
+		SeqID:  seqID,
+		Type:   EventType_TradeCorrected,
+		Market: market,
+		Payload: &Event_TradeCorrect{
+			TradeCorrect: &TradeCorrectMsg{
+				RequestID:        requestID,
+				OperatorID:       operatorID,
+				Original:         &original,
+				Corrected:        &corrected,
+				RestoredOrderIDs: restoredOrderIDs,
+			},
+		},
+		CreatedAt: time.Now().UTC().UnixNano(),
+	}
+}
+
+// NewBBOEvent returns a new event with the best bid and the best ask of the order book
+func NewBBOEvent(seqID uint64, market string, bidPrice, bidAmount, askPrice, askAmount uint64) Event {
+	return Event{
+		SeqID:  seqID,
+		Type:   EventType_BestBidOffer,
+		Market: market,
+		Payload: &Event_BBO{
+			BBO: &BBOMsg{
+				BidPrice:  bidPrice,
+				BidAmount: bidAmount,
+				AskPrice:  askPrice,
+				AskAmount: askAmount,
 			},
 		},
 		CreatedAt: time.Now().UTC().UnixNano(),
@@ -75,27 +145,61 @@ func NewTradeEvent(seqID uint64, market string, tradeSeqID uint64, takerSide Mar
 }
 
 // NewErrorEvent returns a new error event
-func NewErrorEvent(seqID uint64, market string, code ErrorCode, orderType OrderType, side MarketSide, id, ownerID, price, amount, funds uint64) Event {
+func NewErrorEvent(seqID uint64, market string, code ErrorCode, orderType OrderType, side MarketSide, id, ownerID uint64, clientOrderID string, price, amount, funds uint64) Event {
 	return Event{
 		SeqID:  seqID,
 		Type:   EventType_Error,
 		Market: market,
 		Payload: &Event_Error{
 			Error: &ErrorMsg{
-				Code:    code,
-				OrderID: id,
-				Type:    orderType,
-				Side:    side,
-				OwnerID: ownerID,
-				Amount:  amount,
-				Funds:   funds,
-				Price:   price,
+				Code:          code,
+				OrderID:       id,
+				Type:          orderType,
+				Side:          side,
+				OwnerID:       ownerID,
+				ClientOrderID: clientOrderID,
+				Amount:        amount,
+				Funds:         funds,
+				Price:         price,
 			},
 		},
 		CreatedAt: time.Now().UTC().UnixNano(),
 	}
 }
 
+// NewEvents returns the batch of events generated by the command read from the given position of the input topic
+func NewEvents(market, topic string, partition int32, offset int64, events []*Event) *Events {
+	batch := &Events{
+		Events:    events,
+		Market:    market,
+		Topic:     topic,
+		Partition: partition,
+		Offset:    offset,
+		CreatedAt: time.Now().UTC().UnixNano(),
+	}
+	// replayed events keep the sequence id of the original event and are not part of the sequence of the market
+	for _, event := range events {
+		if event.Replay {
+			continue
+		}
+		if batch.FirstSeqID == 0 {
+			batch.FirstSeqID = event.SeqID
+		}
+		batch.LastSeqID = event.SeqID
+	}
+	return batch
+}
+
+// FromBinary loads a batch of events from a byte array
+func (events *Events) FromBinary(msg []byte) error {
+	return proto.Unmarshal(msg, events)
+}
+
+// ToBinary converts a batch of events to a byte string
+func (events *Events) ToBinary() ([]byte, error) {
+	return proto.Marshal(events)
+}
+
 // FromBinary loads an event from a byte array
 func (event *Event) FromBinary(msg []byte) error {
 	return proto.Unmarshal(msg, event)
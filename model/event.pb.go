@@ -39,6 +39,12 @@ const (
 	EventType_OrderActivated EventType = 3
 	// Error in processing
 	EventType_Error EventType = 4
+	// A trade was reversed by an operator
+	EventType_TradeBusted EventType = 5
+	// The price or amount of a trade was corrected by an operator
+	EventType_TradeCorrected EventType = 6
+	// The price or the amount of the best bid or the best ask changed
+	EventType_BestBidOffer EventType = 7
 )
 
 // Enum value maps for EventType.
@@ -49,6 +55,9 @@ var (
 		2: "NewTrade",
 		3: "OrderActivated",
 		4: "Error",
+		5: "TradeBusted",
+		6: "TradeCorrected",
+		7: "BestBidOffer",
 	}
 	EventType_value = map[string]int32{
 		"Unspecified":       0,
@@ -56,6 +65,9 @@ var (
 		"NewTrade":          2,
 		"OrderActivated":    3,
 		"Error":             4,
+		"TradeBusted":       5,
+		"TradeCorrected":    6,
+		"BestBidOffer":      7,
 	}
 )
 
@@ -86,25 +98,189 @@ func (EventType) EnumDescriptor() ([]byte, []int) {
 	return file_event_proto_rawDescGZIP(), []int{0}
 }
 
+type LiquidityFlag int32
+
+const (
+	// The status change was not caused by a trade
+	LiquidityFlag_NotFilled LiquidityFlag = 0
+	// The order was resting in the order book and provided the liquidity
+	LiquidityFlag_Maker LiquidityFlag = 1
+	// The order matched with an order resting in the order book and removed liquidity
+	LiquidityFlag_Taker LiquidityFlag = 2
+)
+
+// Enum value maps for LiquidityFlag.
+var (
+	LiquidityFlag_name = map[int32]string{
+		0: "NotFilled",
+		1: "Maker",
+		2: "Taker",
+	}
+	LiquidityFlag_value = map[string]int32{
+		"NotFilled": 0,
+		"Maker":     1,
+		"Taker":     2,
+	}
+)
+
+func (x LiquidityFlag) Enum() *LiquidityFlag {
+	p := new(LiquidityFlag)
+	*p = x
+	return p
+}
+
+func (x LiquidityFlag) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LiquidityFlag) Descriptor() protoreflect.EnumDescriptor {
+	return file_event_proto_enumTypes[1].Descriptor()
+}
+
+func (LiquidityFlag) Type() protoreflect.EnumType {
+	return &file_event_proto_enumTypes[1]
+}
+
+func (x LiquidityFlag) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LiquidityFlag.Descriptor instead.
+func (LiquidityFlag) EnumDescriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{1}
+}
+
+type CancelReason int32
+
+const (
+	// The order was not cancelled
+	CancelReason_NoReason CancelReason = 0
+	// The order was cancelled at the request of the user
+	CancelReason_UserRequest CancelReason = 1
+	// There was not enough liquidity in the order book to fill a market order
+	CancelReason_NoLiquidity CancelReason = 2
+	// The order expired based on the time in force set on it
+	CancelReason_Expired CancelReason = 3
+	// The order would have matched with another order of the same owner
+	CancelReason_SelfTradePrevention CancelReason = 4
+	// The order was cancelled by a risk check
+	CancelReason_Risk CancelReason = 5
+	// The market was halted
+	CancelReason_Halt CancelReason = 6
+	// The order was cancelled together with other orders by a mass cancel request
+	CancelReason_MassCancel CancelReason = 7
+)
+
+// Enum value maps for CancelReason.
+var (
+	CancelReason_name = map[int32]string{
+		0: "NoReason",
+		1: "UserRequest",
+		2: "NoLiquidity",
+		3: "Expired",
+		4: "SelfTradePrevention",
+		5: "Risk",
+		6: "Halt",
+		7: "MassCancel",
+	}
+	CancelReason_value = map[string]int32{
+		"NoReason":            0,
+		"UserRequest":         1,
+		"NoLiquidity":         2,
+		"Expired":             3,
+		"SelfTradePrevention": 4,
+		"Risk":                5,
+		"Halt":                6,
+		"MassCancel":          7,
+	}
+)
+
+func (x CancelReason) Enum() *CancelReason {
+	p := new(CancelReason)
+	*p = x
+	return p
+}
+
+func (x CancelReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CancelReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_event_proto_enumTypes[2].Descriptor()
+}
+
+func (CancelReason) Type() protoreflect.EnumType {
+	return &file_event_proto_enumTypes[2]
+}
+
+func (x CancelReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CancelReason.Descriptor instead.
+func (CancelReason) EnumDescriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{2}
+}
+
 type ErrorCode int32
 
 const (
-	ErrorCode_Undefined    ErrorCode = 0
+	ErrorCode_Undefined ErrorCode = 0
+	// The order failed the validation of the required fields
 	ErrorCode_InvalidOrder ErrorCode = 1
+	// The order received by the market could not be cancelled since it was not found at the given price,
+	// for example because it was already filled or cancelled
 	ErrorCode_CancelFailed ErrorCode = 2
+	// Another open order of the same owner already uses the given ClientOrderID
+	ErrorCode_DuplicateClientOrderID ErrorCode = 3
+	// An order with the same ID but a different payload was already received by the market
+	ErrorCode_DuplicateOrder ErrorCode = 4
+	// The order was sent to a market that is not handled by the engine
+	ErrorCode_UnknownMarket ErrorCode = 5
+	// The price or amount of the order is not a multiple of the increments configured for the market
+	ErrorCode_WrongPrecision ErrorCode = 6
+	// The order to cancel was not received by the market: its id is not among the orders last received by the market
+	// or no open order of the owner uses the given ClientOrderID
+	ErrorCode_UnknownOrder ErrorCode = 7
+	// The market is halted and does not accept new orders
+	ErrorCode_MarketHalted ErrorCode = 8
+	// The trade could not be found in the recent trades of the market or it was already busted
+	ErrorCode_UnknownTrade ErrorCode = 9
+	// The corrected trade amount is zero or greater than the original amount
+	ErrorCode_InvalidCorrection ErrorCode = 10
+	// The operator that sent the trade bust or correction is not allowed to change the trades of the market
+	ErrorCode_UnauthorizedOperator ErrorCode = 11
 )
 
 // Enum value maps for ErrorCode.
 var (
 	ErrorCode_name = map[int32]string{
-		0: "Undefined",
-		1: "InvalidOrder",
-		2: "CancelFailed",
+		0:  "Undefined",
+		1:  "InvalidOrder",
+		2:  "CancelFailed",
+		3:  "DuplicateClientOrderID",
+		4:  "DuplicateOrder",
+		5:  "UnknownMarket",
+		6:  "WrongPrecision",
+		7:  "UnknownOrder",
+		8:  "MarketHalted",
+		9:  "UnknownTrade",
+		10: "InvalidCorrection",
+		11: "UnauthorizedOperator",
 	}
 	ErrorCode_value = map[string]int32{
-		"Undefined":    0,
-		"InvalidOrder": 1,
-		"CancelFailed": 2,
+		"Undefined":              0,
+		"InvalidOrder":           1,
+		"CancelFailed":           2,
+		"DuplicateClientOrderID": 3,
+		"DuplicateOrder":         4,
+		"UnknownMarket":          5,
+		"WrongPrecision":         6,
+		"UnknownOrder":           7,
+		"MarketHalted":           8,
+		"UnknownTrade":           9,
+		"InvalidCorrection":      10,
+		"UnauthorizedOperator":   11,
 	}
 )
 
@@ -119,11 +295,11 @@ func (x ErrorCode) String() string {
 }
 
 func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
-	return file_event_proto_enumTypes[1].Descriptor()
+	return file_event_proto_enumTypes[3].Descriptor()
 }
 
 func (ErrorCode) Type() protoreflect.EnumType {
-	return &file_event_proto_enumTypes[1]
+	return &file_event_proto_enumTypes[3]
 }
 
 func (x ErrorCode) Number() protoreflect.EnumNumber {
@@ -132,7 +308,7 @@ func (x ErrorCode) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ErrorCode.Descriptor instead.
 func (ErrorCode) EnumDescriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{1}
+	return file_event_proto_rawDescGZIP(), []int{3}
 }
 
 type OrderStatusMsg struct {
@@ -153,6 +329,12 @@ type OrderStatusMsg struct {
 	Status       OrderStatus `protobuf:"varint,8,opt,name=Status,proto3,enum=model.OrderStatus" json:"Status,omitempty"`
 	FilledAmount uint64      `protobuf:"varint,9,opt,name=FilledAmount,proto3" json:"FilledAmount,omitempty"`
 	UsedFunds    uint64      `protobuf:"varint,10,opt,name=UsedFunds,proto3" json:"UsedFunds,omitempty"`
+	// The id defined by the user when the order was added
+	ClientOrderID string `protobuf:"bytes,11,opt,name=ClientOrderID,proto3" json:"ClientOrderID,omitempty"`
+	// The reason for which the order was cancelled. Only set for cancelled orders.
+	Reason CancelReason `protobuf:"varint,12,opt,name=Reason,proto3,enum=model.CancelReason" json:"Reason,omitempty"`
+	// Signals if the order was filled as a maker or a taker. Only set for status changes caused by a trade.
+	Liquidity LiquidityFlag `protobuf:"varint,13,opt,name=Liquidity,proto3,enum=model.LiquidityFlag" json:"Liquidity,omitempty"`
 }
 
 func (x *OrderStatusMsg) Reset() {
@@ -257,6 +439,27 @@ func (x *OrderStatusMsg) GetUsedFunds() uint64 {
 	return 0
 }
 
+func (x *OrderStatusMsg) GetClientOrderID() string {
+	if x != nil {
+		return x.ClientOrderID
+	}
+	return ""
+}
+
+func (x *OrderStatusMsg) GetReason() CancelReason {
+	if x != nil {
+		return x.Reason
+	}
+	return CancelReason_NoReason
+}
+
+func (x *OrderStatusMsg) GetLiquidity() LiquidityFlag {
+	if x != nil {
+		return x.Liquidity
+	}
+	return LiquidityFlag_NotFilled
+}
+
 type ErrorMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -273,6 +476,8 @@ type ErrorMsg struct {
 	Funds   uint64     `protobuf:"varint,7,opt,name=Funds,proto3" json:"Funds,omitempty"`
 	// The unique identifier the account that added the order
 	OwnerID uint64 `protobuf:"varint,8,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
+	// The id defined by the user when the order was added
+	ClientOrderID string `protobuf:"bytes,9,opt,name=ClientOrderID,proto3" json:"ClientOrderID,omitempty"`
 }
 
 func (x *ErrorMsg) Reset() {
@@ -363,6 +568,246 @@ func (x *ErrorMsg) GetOwnerID() uint64 {
 	return 0
 }
 
+func (x *ErrorMsg) GetClientOrderID() string {
+	if x != nil {
+		return x.ClientOrderID
+	}
+	return ""
+}
+
+type TradeBustMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The id of the bust request
+	RequestID uint64 `protobuf:"varint,1,opt,name=RequestID,proto3" json:"RequestID,omitempty"`
+	// The operator that sent the bust request
+	OperatorID uint64 `protobuf:"varint,2,opt,name=OperatorID,proto3" json:"OperatorID,omitempty"`
+	// The trade that was reversed as it was last reported
+	Trade *Trade `protobuf:"bytes,3,opt,name=Trade,proto3" json:"Trade,omitempty"`
+	// The ids of the orders for which the busted amount was restored in the order book
+	RestoredOrderIDs []uint64 `protobuf:"varint,4,rep,packed,name=RestoredOrderIDs,proto3" json:"RestoredOrderIDs,omitempty"`
+}
+
+func (x *TradeBustMsg) Reset() {
+	*x = TradeBustMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_event_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TradeBustMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradeBustMsg) ProtoMessage() {}
+
+func (x *TradeBustMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradeBustMsg.ProtoReflect.Descriptor instead.
+func (*TradeBustMsg) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TradeBustMsg) GetRequestID() uint64 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *TradeBustMsg) GetOperatorID() uint64 {
+	if x != nil {
+		return x.OperatorID
+	}
+	return 0
+}
+
+func (x *TradeBustMsg) GetTrade() *Trade {
+	if x != nil {
+		return x.Trade
+	}
+	return nil
+}
+
+func (x *TradeBustMsg) GetRestoredOrderIDs() []uint64 {
+	if x != nil {
+		return x.RestoredOrderIDs
+	}
+	return nil
+}
+
+type TradeCorrectMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The id of the correction request
+	RequestID uint64 `protobuf:"varint,1,opt,name=RequestID,proto3" json:"RequestID,omitempty"`
+	// The operator that sent the correction request
+	OperatorID uint64 `protobuf:"varint,2,opt,name=OperatorID,proto3" json:"OperatorID,omitempty"`
+	// The trade as it was reported before the correction
+	Original *Trade `protobuf:"bytes,3,opt,name=Original,proto3" json:"Original,omitempty"`
+	// The trade after the correction. Keeps the sequence id of the original trade.
+	Corrected *Trade `protobuf:"bytes,4,opt,name=Corrected,proto3" json:"Corrected,omitempty"`
+	// The ids of the orders for which the difference in amount was restored in the order book
+	RestoredOrderIDs []uint64 `protobuf:"varint,5,rep,packed,name=RestoredOrderIDs,proto3" json:"RestoredOrderIDs,omitempty"`
+}
+
+func (x *TradeCorrectMsg) Reset() {
+	*x = TradeCorrectMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_event_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TradeCorrectMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradeCorrectMsg) ProtoMessage() {}
+
+func (x *TradeCorrectMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradeCorrectMsg.ProtoReflect.Descriptor instead.
+func (*TradeCorrectMsg) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TradeCorrectMsg) GetRequestID() uint64 {
+	if x != nil {
+		return x.RequestID
+	}
+	return 0
+}
+
+func (x *TradeCorrectMsg) GetOperatorID() uint64 {
+	if x != nil {
+		return x.OperatorID
+	}
+	return 0
+}
+
+func (x *TradeCorrectMsg) GetOriginal() *Trade {
+	if x != nil {
+		return x.Original
+	}
+	return nil
+}
+
+func (x *TradeCorrectMsg) GetCorrected() *Trade {
+	if x != nil {
+		return x.Corrected
+	}
+	return nil
+}
+
+func (x *TradeCorrectMsg) GetRestoredOrderIDs() []uint64 {
+	if x != nil {
+		return x.RestoredOrderIDs
+	}
+	return nil
+}
+
+// BBOMsg contains the top of the order book
+type BBOMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The highest bid price and the total amount of the orders at that price, zero if there are no bids
+	BidPrice  uint64 `protobuf:"varint,1,opt,name=BidPrice,proto3" json:"BidPrice,omitempty"`
+	BidAmount uint64 `protobuf:"varint,2,opt,name=BidAmount,proto3" json:"BidAmount,omitempty"`
+	// The lowest ask price and the total amount of the orders at that price, zero if there are no asks
+	AskPrice  uint64 `protobuf:"varint,3,opt,name=AskPrice,proto3" json:"AskPrice,omitempty"`
+	AskAmount uint64 `protobuf:"varint,4,opt,name=AskAmount,proto3" json:"AskAmount,omitempty"`
+}
+
+func (x *BBOMsg) Reset() {
+	*x = BBOMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_event_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BBOMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BBOMsg) ProtoMessage() {}
+
+func (x *BBOMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_event_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BBOMsg.ProtoReflect.Descriptor instead.
+func (*BBOMsg) Descriptor() ([]byte, []int) {
+	return file_event_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BBOMsg) GetBidPrice() uint64 {
+	if x != nil {
+		return x.BidPrice
+	}
+	return 0
+}
+
+func (x *BBOMsg) GetBidAmount() uint64 {
+	if x != nil {
+		return x.BidAmount
+	}
+	return 0
+}
+
+func (x *BBOMsg) GetAskPrice() uint64 {
+	if x != nil {
+		return x.AskPrice
+	}
+	return 0
+}
+
+func (x *BBOMsg) GetAskAmount() uint64 {
+	if x != nil {
+		return x.AskAmount
+	}
+	return 0
+}
+
 type Event struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -376,14 +821,20 @@ type Event struct {
 	//	*Event_Trade
 	//	*Event_OrderActivation
 	//	*Event_Error
+	//	*Event_TradeBust
+	//	*Event_TradeCorrect
+	//	*Event_BBO
 	Payload isEvent_Payload `protobuf_oneof:"Payload"`
 	SeqID   uint64          `protobuf:"varint,7,opt,name=SeqID,proto3" json:"SeqID,omitempty"`
+	// Set on the acknowledgement replayed for a duplicate order. The event keeps the sequence id of the original
+	// acknowledgement and is not part of the sequence of events of the market.
+	Replay bool `protobuf:"varint,12,opt,name=Replay,proto3" json:"Replay,omitempty"`
 }
 
 func (x *Event) Reset() {
 	*x = Event{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_event_proto_msgTypes[2]
+		mi := &file_event_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -396,7 +847,7 @@ func (x *Event) String() string {
 func (*Event) ProtoMessage() {}
 
 func (x *Event) ProtoReflect() protoreflect.Message {
-	mi := &file_event_proto_msgTypes[2]
+	mi := &file_event_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -409,7 +860,7 @@ func (x *Event) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event.ProtoReflect.Descriptor instead.
 func (*Event) Descriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{2}
+	return file_event_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Event) GetType() EventType {
@@ -468,6 +919,27 @@ func (x *Event) GetError() *ErrorMsg {
 	return nil
 }
 
+func (x *Event) GetTradeBust() *TradeBustMsg {
+	if x, ok := x.GetPayload().(*Event_TradeBust); ok {
+		return x.TradeBust
+	}
+	return nil
+}
+
+func (x *Event) GetTradeCorrect() *TradeCorrectMsg {
+	if x, ok := x.GetPayload().(*Event_TradeCorrect); ok {
+		return x.TradeCorrect
+	}
+	return nil
+}
+
+func (x *Event) GetBBO() *BBOMsg {
+	if x, ok := x.GetPayload().(*Event_BBO); ok {
+		return x.BBO
+	}
+	return nil
+}
+
 func (x *Event) GetSeqID() uint64 {
 	if x != nil {
 		return x.SeqID
@@ -475,6 +947,13 @@ func (x *Event) GetSeqID() uint64 {
 	return 0
 }
 
+func (x *Event) GetReplay() bool {
+	if x != nil {
+		return x.Replay
+	}
+	return false
+}
+
 type isEvent_Payload interface {
 	isEvent_Payload()
 }
@@ -495,6 +974,18 @@ type Event_Error struct {
 	Error *ErrorMsg `protobuf:"bytes,8,opt,name=Error,proto3,oneof"`
 }
 
+type Event_TradeBust struct {
+	TradeBust *TradeBustMsg `protobuf:"bytes,9,opt,name=TradeBust,proto3,oneof"`
+}
+
+type Event_TradeCorrect struct {
+	TradeCorrect *TradeCorrectMsg `protobuf:"bytes,10,opt,name=TradeCorrect,proto3,oneof"`
+}
+
+type Event_BBO struct {
+	BBO *BBOMsg `protobuf:"bytes,11,opt,name=BBO,proto3,oneof"`
+}
+
 func (*Event_OrderStatus) isEvent_Payload() {}
 
 func (*Event_Trade) isEvent_Payload() {}
@@ -503,18 +994,34 @@ func (*Event_OrderActivation) isEvent_Payload() {}
 
 func (*Event_Error) isEvent_Payload() {}
 
+func (*Event_TradeBust) isEvent_Payload() {}
+
+func (*Event_TradeCorrect) isEvent_Payload() {}
+
+func (*Event_BBO) isEvent_Payload() {}
+
+// Events generated by the engine for a single command, published as one message when the market uses the batch output
 type Events struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Events []*Event `protobuf:"bytes,1,rep,name=Events,proto3" json:"Events,omitempty"`
+	Market string   `protobuf:"bytes,2,opt,name=Market,proto3" json:"Market,omitempty"`
+	// Position of the command in the input topic
+	Topic     string `protobuf:"bytes,3,opt,name=Topic,proto3" json:"Topic,omitempty"`
+	Partition int32  `protobuf:"varint,4,opt,name=Partition,proto3" json:"Partition,omitempty"`
+	Offset    int64  `protobuf:"varint,5,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	// Sequence IDs of the first and the last event of the batch, replayed events are not counted
+	FirstSeqID uint64 `protobuf:"varint,6,opt,name=FirstSeqID,proto3" json:"FirstSeqID,omitempty"`
+	LastSeqID  uint64 `protobuf:"varint,7,opt,name=LastSeqID,proto3" json:"LastSeqID,omitempty"`
+	CreatedAt  int64  `protobuf:"varint,8,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
 }
 
 func (x *Events) Reset() {
 	*x = Events{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_event_proto_msgTypes[3]
+		mi := &file_event_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -527,7 +1034,7 @@ func (x *Events) String() string {
 func (*Events) ProtoMessage() {}
 
 func (x *Events) ProtoReflect() protoreflect.Message {
-	mi := &file_event_proto_msgTypes[3]
+	mi := &file_event_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -540,7 +1047,7 @@ func (x *Events) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Events.ProtoReflect.Descriptor instead.
 func (*Events) Descriptor() ([]byte, []int) {
-	return file_event_proto_rawDescGZIP(), []int{3}
+	return file_event_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Events) GetEvents() []*Event {
@@ -550,13 +1057,62 @@ func (x *Events) GetEvents() []*Event {
 	return nil
 }
 
+func (x *Events) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *Events) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *Events) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *Events) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *Events) GetFirstSeqID() uint64 {
+	if x != nil {
+		return x.FirstSeqID
+	}
+	return 0
+}
+
+func (x *Events) GetLastSeqID() uint64 {
+	if x != nil {
+		return x.LastSeqID
+	}
+	return 0
+}
+
+func (x *Events) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
 var File_event_proto protoreflect.FileDescriptor
 
 var file_event_proto_rawDesc = []byte{
 	0x0a, 0x0b, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6d,
 	0x6f, 0x64, 0x65, 0x6c, 0x1a, 0x0b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x1a, 0x0b, 0x74, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb9,
-	0x02, 0x0a, 0x0e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4d, 0x73,
+	0x6f, 0x1a, 0x0b, 0x74, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc0,
+	0x03, 0x0a, 0x0e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4d, 0x73,
 	0x67, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49,
 	0x44, 0x12, 0x24, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
 	0x10, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70,
@@ -575,61 +1131,153 @@ var file_event_proto_rawDesc = []byte{
 	0x65, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
 	0x46, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09,
 	0x55, 0x73, 0x65, 0x64, 0x46, 0x75, 0x6e, 0x64, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x09, 0x55, 0x73, 0x65, 0x64, 0x46, 0x75, 0x6e, 0x64, 0x73, 0x22, 0xf5, 0x01, 0x0a, 0x08, 0x45,
-	0x72, 0x72, 0x6f, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x24, 0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x45, 0x72,
-	0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x24, 0x0a,
-	0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x6d, 0x6f,
-	0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x53, 0x69, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x11, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74,
-	0x53, 0x69, 0x64, 0x65, 0x52, 0x04, 0x53, 0x69, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x4f, 0x72,
-	0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x4f, 0x72, 0x64,
-	0x65, 0x72, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x05, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x6d,
-	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x41, 0x6d, 0x6f, 0x75,
-	0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x46, 0x75, 0x6e, 0x64, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x05, 0x46, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x4f, 0x77, 0x6e, 0x65,
-	0x72, 0x49, 0x44, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x4f, 0x77, 0x6e, 0x65, 0x72,
-	0x49, 0x44, 0x22, 0xd1, 0x02, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x04,
-	0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x6d, 0x6f, 0x64,
-	0x65, 0x6c, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79,
-	0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0b, 0x4f, 0x72, 0x64, 0x65,
-	0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
-	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x0b, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x24, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x09, 0x55, 0x73, 0x65, 0x64, 0x46, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x43, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44,
+	0x12, 0x2b, 0x0a, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x13, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x32, 0x0a,
+	0x09, 0x4c, 0x69, 0x71, 0x75, 0x69, 0x64, 0x69, 0x74, 0x79, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x14, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4c, 0x69, 0x71, 0x75, 0x69, 0x64, 0x69,
+	0x74, 0x79, 0x46, 0x6c, 0x61, 0x67, 0x52, 0x09, 0x4c, 0x69, 0x71, 0x75, 0x69, 0x64, 0x69, 0x74,
+	0x79, 0x22, 0x9b, 0x02, 0x0a, 0x08, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x73, 0x67, 0x12, 0x24,
+	0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x24, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x10, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x53, 0x69,
+	0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x2e, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x53, 0x69, 0x64, 0x65, 0x52, 0x04, 0x53, 0x69, 0x64,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x07, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x50,
+	0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x50, 0x72, 0x69, 0x63,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x46, 0x75, 0x6e,
+	0x64, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x46, 0x75, 0x6e, 0x64, 0x73, 0x12,
+	0x18, 0x0a, 0x07, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44, 0x12, 0x24, 0x0a, 0x0d, 0x43, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x22,
+	0x9c, 0x01, 0x0a, 0x0c, 0x54, 0x72, 0x61, 0x64, 0x65, 0x42, 0x75, 0x73, 0x74, 0x4d, 0x73, 0x67,
+	0x12, 0x1c, 0x0a, 0x09, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x09, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x12, 0x1e,
+	0x0a, 0x0a, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0a, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x12, 0x22,
+	0x0a, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x05, 0x54, 0x72, 0x61,
+	0x64, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x49, 0x44, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x04, 0x52, 0x10, 0x52, 0x65,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x73, 0x22, 0xd1,
+	0x01, 0x0a, 0x0f, 0x54, 0x72, 0x61, 0x64, 0x65, 0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x4d,
+	0x73, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x44,
+	0x12, 0x1e, 0x0a, 0x0a, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44,
+	0x12, 0x28, 0x0a, 0x08, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65,
-	0x48, 0x00, 0x52, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x41, 0x0a, 0x0f, 0x4f, 0x72, 0x64,
-	0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x0f, 0x4f, 0x72, 0x64,
-	0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x05,
-	0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6d, 0x6f,
-	0x64, 0x65, 0x6c, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x05,
-	0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x42, 0x09, 0x0a, 0x07, 0x50,
-	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x2e, 0x0a, 0x06, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
-	0x12, 0x24, 0x0a, 0x06, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2a, 0x60, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x6e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x66, 0x69,
-	0x65, 0x64, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x4e,
-	0x65, 0x77, 0x54, 0x72, 0x61, 0x64, 0x65, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x4f, 0x72, 0x64,
-	0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x10, 0x03, 0x12, 0x09, 0x0a,
-	0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x04, 0x2a, 0x3e, 0x0a, 0x09, 0x45, 0x72, 0x72, 0x6f,
-	0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x55, 0x6e, 0x64, 0x65, 0x66, 0x69, 0x6e,
-	0x65, 0x64, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x49, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x4f,
-	0x72, 0x64, 0x65, 0x72, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c,
-	0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x10, 0x02, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x6c,
-	0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x35, 0x2f,
-	0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e,
-	0x67, 0x2d, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x52, 0x08, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x12, 0x2a, 0x0a, 0x09, 0x43, 0x6f,
+	0x72, 0x72, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x09, 0x43, 0x6f, 0x72,
+	0x72, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x04,
+	0x52, 0x10, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49,
+	0x44, 0x73, 0x22, 0x7c, 0x0a, 0x06, 0x42, 0x42, 0x4f, 0x4d, 0x73, 0x67, 0x12, 0x1a, 0x0a, 0x08,
+	0x42, 0x69, 0x64, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08,
+	0x42, 0x69, 0x64, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x42, 0x69, 0x64, 0x41,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x42, 0x69, 0x64,
+	0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x41, 0x73, 0x6b, 0x50, 0x72, 0x69,
+	0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x41, 0x73, 0x6b, 0x50, 0x72, 0x69,
+	0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x41, 0x73, 0x6b, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x41, 0x73, 0x6b, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x22, 0xff, 0x03, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x04, 0x54, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0b, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4d,
+	0x73, 0x67, 0x48, 0x00, 0x52, 0x0b, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x24, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x48, 0x00,
+	0x52, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x41, 0x0a, 0x0f, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x0f, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x05, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x05, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x12, 0x33, 0x0a, 0x09, 0x54, 0x72, 0x61, 0x64, 0x65, 0x42, 0x75, 0x73, 0x74,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x54,
+	0x72, 0x61, 0x64, 0x65, 0x42, 0x75, 0x73, 0x74, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x09, 0x54,
+	0x72, 0x61, 0x64, 0x65, 0x42, 0x75, 0x73, 0x74, 0x12, 0x3c, 0x0a, 0x0c, 0x54, 0x72, 0x61, 0x64,
+	0x65, 0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x43, 0x6f, 0x72, 0x72,
+	0x65, 0x63, 0x74, 0x4d, 0x73, 0x67, 0x48, 0x00, 0x52, 0x0c, 0x54, 0x72, 0x61, 0x64, 0x65, 0x43,
+	0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x12, 0x21, 0x0a, 0x03, 0x42, 0x42, 0x4f, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x42, 0x42, 0x4f, 0x4d,
+	0x73, 0x67, 0x48, 0x00, 0x52, 0x03, 0x42, 0x42, 0x4f, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x65, 0x71,
+	0x49, 0x44, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12,
+	0x16, 0x0a, 0x06, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x42, 0x09, 0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x22, 0xee, 0x01, 0x0a, 0x06, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x24, 0x0a,
+	0x06, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x54,
+	0x6f, 0x70, 0x69, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x6f, 0x70, 0x69,
+	0x63, 0x12, 0x1c, 0x0a, 0x09, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x46, 0x69, 0x72, 0x73, 0x74,
+	0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x46, 0x69, 0x72,
+	0x73, 0x74, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x4c, 0x61, 0x73, 0x74, 0x53,
+	0x65, 0x71, 0x49, 0x44, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x4c, 0x61, 0x73, 0x74,
+	0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x41, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x2a, 0x97, 0x01, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x6e, 0x73, 0x70, 0x65, 0x63, 0x69, 0x66, 0x69, 0x65, 0x64,
+	0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x65, 0x77,
+	0x54, 0x72, 0x61, 0x64, 0x65, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x41, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x65, 0x64, 0x10, 0x03, 0x12, 0x09, 0x0a, 0x05, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x10, 0x04, 0x12, 0x0f, 0x0a, 0x0b, 0x54, 0x72, 0x61, 0x64, 0x65, 0x42,
+	0x75, 0x73, 0x74, 0x65, 0x64, 0x10, 0x05, 0x12, 0x12, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x64, 0x65,
+	0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x65, 0x64, 0x10, 0x06, 0x12, 0x10, 0x0a, 0x0c, 0x42,
+	0x65, 0x73, 0x74, 0x42, 0x69, 0x64, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x10, 0x07, 0x2a, 0x34, 0x0a,
+	0x0d, 0x4c, 0x69, 0x71, 0x75, 0x69, 0x64, 0x69, 0x74, 0x79, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x0d,
+	0x0a, 0x09, 0x4e, 0x6f, 0x74, 0x46, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x00, 0x12, 0x09, 0x0a,
+	0x05, 0x4d, 0x61, 0x6b, 0x65, 0x72, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x61, 0x6b, 0x65,
+	0x72, 0x10, 0x02, 0x2a, 0x88, 0x01, 0x0a, 0x0c, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x6f, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x4e, 0x6f, 0x4c, 0x69, 0x71, 0x75, 0x69, 0x64, 0x69,
+	0x74, 0x79, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x10,
+	0x03, 0x12, 0x17, 0x0a, 0x13, 0x53, 0x65, 0x6c, 0x66, 0x54, 0x72, 0x61, 0x64, 0x65, 0x50, 0x72,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x04, 0x12, 0x08, 0x0a, 0x04, 0x52, 0x69,
+	0x73, 0x6b, 0x10, 0x05, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x61, 0x6c, 0x74, 0x10, 0x06, 0x12, 0x0e,
+	0x0a, 0x0a, 0x4d, 0x61, 0x73, 0x73, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x10, 0x07, 0x2a, 0xfc,
+	0x01, 0x0a, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0d, 0x0a, 0x09,
+	0x55, 0x6e, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x64, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x49,
+	0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x01, 0x12, 0x10, 0x0a,
+	0x0c, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x10, 0x02, 0x12,
+	0x1a, 0x0a, 0x16, 0x44, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x44,
+	0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x04, 0x12,
+	0x11, 0x0a, 0x0d, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74,
+	0x10, 0x05, 0x12, 0x12, 0x0a, 0x0e, 0x57, 0x72, 0x6f, 0x6e, 0x67, 0x50, 0x72, 0x65, 0x63, 0x69,
+	0x73, 0x69, 0x6f, 0x6e, 0x10, 0x06, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77,
+	0x6e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x07, 0x12, 0x10, 0x0a, 0x0c, 0x4d, 0x61, 0x72, 0x6b,
+	0x65, 0x74, 0x48, 0x61, 0x6c, 0x74, 0x65, 0x64, 0x10, 0x08, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x6e,
+	0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x10, 0x09, 0x12, 0x15, 0x0a, 0x11,
+	0x49, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x10, 0x0a, 0x12, 0x18, 0x0a, 0x14, 0x55, 0x6e, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69,
+	0x7a, 0x65, 0x64, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x10, 0x0b, 0x42, 0x34, 0x5a,
+	0x32, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x6f, 0x75,
+	0x6e, 0x64, 0x32, 0x35, 0x2f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x2d, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x2f, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -644,38 +1292,51 @@ func file_event_proto_rawDescGZIP() []byte {
 	return file_event_proto_rawDescData
 }
 
-var file_event_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_event_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_event_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_event_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_event_proto_goTypes = []interface{}{
-	(EventType)(0),         // 0: model.EventType
-	(ErrorCode)(0),         // 1: model.ErrorCode
-	(*OrderStatusMsg)(nil), // 2: model.OrderStatusMsg
-	(*ErrorMsg)(nil),       // 3: model.ErrorMsg
-	(*Event)(nil),          // 4: model.Event
-	(*Events)(nil),         // 5: model.Events
-	(OrderType)(0),         // 6: model.OrderType
-	(MarketSide)(0),        // 7: model.MarketSide
-	(OrderStatus)(0),       // 8: model.OrderStatus
-	(*Trade)(nil),          // 9: model.Trade
+	(EventType)(0),          // 0: model.EventType
+	(LiquidityFlag)(0),      // 1: model.LiquidityFlag
+	(CancelReason)(0),       // 2: model.CancelReason
+	(ErrorCode)(0),          // 3: model.ErrorCode
+	(*OrderStatusMsg)(nil),  // 4: model.OrderStatusMsg
+	(*ErrorMsg)(nil),        // 5: model.ErrorMsg
+	(*TradeBustMsg)(nil),    // 6: model.TradeBustMsg
+	(*TradeCorrectMsg)(nil), // 7: model.TradeCorrectMsg
+	(*BBOMsg)(nil),          // 8: model.BBOMsg
+	(*Event)(nil),           // 9: model.Event
+	(*Events)(nil),          // 10: model.Events
+	(OrderType)(0),          // 11: model.OrderType
+	(MarketSide)(0),         // 12: model.MarketSide
+	(OrderStatus)(0),        // 13: model.OrderStatus
+	(*Trade)(nil),           // 14: model.Trade
 }
 var file_event_proto_depIdxs = []int32{
-	6,  // 0: model.OrderStatusMsg.Type:type_name -> model.OrderType
-	7,  // 1: model.OrderStatusMsg.Side:type_name -> model.MarketSide
-	8,  // 2: model.OrderStatusMsg.Status:type_name -> model.OrderStatus
-	1,  // 3: model.ErrorMsg.Code:type_name -> model.ErrorCode
-	6,  // 4: model.ErrorMsg.Type:type_name -> model.OrderType
-	7,  // 5: model.ErrorMsg.Side:type_name -> model.MarketSide
-	0,  // 6: model.Event.Type:type_name -> model.EventType
-	2,  // 7: model.Event.OrderStatus:type_name -> model.OrderStatusMsg
-	9,  // 8: model.Event.Trade:type_name -> model.Trade
-	2,  // 9: model.Event.OrderActivation:type_name -> model.OrderStatusMsg
-	3,  // 10: model.Event.Error:type_name -> model.ErrorMsg
-	4,  // 11: model.Events.Events:type_name -> model.Event
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	11, // 0: model.OrderStatusMsg.Type:type_name -> model.OrderType
+	12, // 1: model.OrderStatusMsg.Side:type_name -> model.MarketSide
+	13, // 2: model.OrderStatusMsg.Status:type_name -> model.OrderStatus
+	2,  // 3: model.OrderStatusMsg.Reason:type_name -> model.CancelReason
+	1,  // 4: model.OrderStatusMsg.Liquidity:type_name -> model.LiquidityFlag
+	3,  // 5: model.ErrorMsg.Code:type_name -> model.ErrorCode
+	11, // 6: model.ErrorMsg.Type:type_name -> model.OrderType
+	12, // 7: model.ErrorMsg.Side:type_name -> model.MarketSide
+	14, // 8: model.TradeBustMsg.Trade:type_name -> model.Trade
+	14, // 9: model.TradeCorrectMsg.Original:type_name -> model.Trade
+	14, // 10: model.TradeCorrectMsg.Corrected:type_name -> model.Trade
+	0,  // 11: model.Event.Type:type_name -> model.EventType
+	4,  // 12: model.Event.OrderStatus:type_name -> model.OrderStatusMsg
+	14, // 13: model.Event.Trade:type_name -> model.Trade
+	4,  // 14: model.Event.OrderActivation:type_name -> model.OrderStatusMsg
+	5,  // 15: model.Event.Error:type_name -> model.ErrorMsg
+	6,  // 16: model.Event.TradeBust:type_name -> model.TradeBustMsg
+	7,  // 17: model.Event.TradeCorrect:type_name -> model.TradeCorrectMsg
+	8,  // 18: model.Event.BBO:type_name -> model.BBOMsg
+	9,  // 19: model.Events.Events:type_name -> model.Event
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_event_proto_init() }
@@ -711,7 +1372,7 @@ func file_event_proto_init() {
 			}
 		}
 		file_event_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Event); i {
+			switch v := v.(*TradeBustMsg); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -723,6 +1384,42 @@ func file_event_proto_init() {
 			}
 		}
 		file_event_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TradeCorrectMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_event_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BBOMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_event_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_event_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Events); i {
 			case 0:
 				return &v.state
@@ -735,19 +1432,22 @@ func file_event_proto_init() {
 			}
 		}
 	}
-	file_event_proto_msgTypes[2].OneofWrappers = []interface{}{
+	file_event_proto_msgTypes[5].OneofWrappers = []interface{}{
 		(*Event_OrderStatus)(nil),
 		(*Event_Trade)(nil),
 		(*Event_OrderActivation)(nil),
 		(*Event_Error)(nil),
+		(*Event_TradeBust)(nil),
+		(*Event_TradeCorrect)(nil),
+		(*Event_BBO)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_event_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   4,
+			NumEnums:      4,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
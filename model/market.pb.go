@@ -48,6 +48,28 @@ type MarketBackup struct {
 	StopLossOrders    []*Order `protobuf:"bytes,16,rep,name=StopLossOrders,proto3" json:"StopLossOrders,omitempty"`
 	EventSeqID        uint64   `protobuf:"varint,17,opt,name=EventSeqID,proto3" json:"EventSeqID,omitempty"`
 	TradeSeqID        uint64   `protobuf:"varint,18,opt,name=TradeSeqID,proto3" json:"TradeSeqID,omitempty"`
+	// Window of the orders last received by the market used to detect duplicate submissions
+	RecentOrders []*RecentOrder `protobuf:"bytes,19,rep,name=RecentOrders,proto3" json:"RecentOrders,omitempty"`
+	// Window of the trades last generated by the market that can still be busted or corrected
+	RecentTrades []*RecentTrade `protobuf:"bytes,20,rep,name=RecentTrades,proto3" json:"RecentTrades,omitempty"`
+	// The sequence number of the last update generated for the order feed
+	OrderFeedSeqID uint64 `protobuf:"varint,21,opt,name=OrderFeedSeqID,proto3" json:"OrderFeedSeqID,omitempty"`
+	// The candles of the market that were not closed yet
+	Candles []*Candle `protobuf:"bytes,22,rep,name=Candles,proto3" json:"Candles,omitempty"`
+	// One minute buckets with the trades of the last 24 hours used for the ticker statistics
+	TickerBuckets []*Candle `protobuf:"bytes,23,rep,name=TickerBuckets,proto3" json:"TickerBuckets,omitempty"`
+	// The sequence of the last event published on the private stream of each owner
+	OwnerSeqIDs map[uint64]uint64 `protobuf:"bytes,24,rep,name=OwnerSeqIDs,proto3" json:"OwnerSeqIDs,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// The number of commands of the direct journal processed by the market, the next ones are replayed on restore
+	DirectOffset int64 `protobuf:"varint,25,opt,name=DirectOffset,proto3" json:"DirectOffset,omitempty"`
+	// The sequence id of the last event published by the market on all its topics, updated when the market is stopped
+	// because its output failed. The events up to it are not published again when they are generated on restore.
+	LastPublishedSeqID uint64 `protobuf:"varint,26,opt,name=LastPublishedSeqID,proto3" json:"LastPublishedSeqID,omitempty"`
+	// The last candles closed for each interval, which are published again when one of their trades is busted or corrected
+	ClosedCandles []*Candle `protobuf:"bytes,27,rep,name=ClosedCandles,proto3" json:"ClosedCandles,omitempty"`
+	// The sequence id of the last event published on each event topic, ahead of LastPublishedSeqID on the topics that
+	// were written before the output of another topic failed
+	PublishedSeqIDs map[string]uint64 `protobuf:"bytes,28,rep,name=PublishedSeqIDs,proto3" json:"PublishedSeqIDs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
 }
 
 func (x *MarketBackup) Reset() {
@@ -208,61 +230,312 @@ func (x *MarketBackup) GetTradeSeqID() uint64 {
 	return 0
 }
 
+func (x *MarketBackup) GetRecentOrders() []*RecentOrder {
+	if x != nil {
+		return x.RecentOrders
+	}
+	return nil
+}
+
+func (x *MarketBackup) GetRecentTrades() []*RecentTrade {
+	if x != nil {
+		return x.RecentTrades
+	}
+	return nil
+}
+
+func (x *MarketBackup) GetOrderFeedSeqID() uint64 {
+	if x != nil {
+		return x.OrderFeedSeqID
+	}
+	return 0
+}
+
+func (x *MarketBackup) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+func (x *MarketBackup) GetTickerBuckets() []*Candle {
+	if x != nil {
+		return x.TickerBuckets
+	}
+	return nil
+}
+
+func (x *MarketBackup) GetOwnerSeqIDs() map[uint64]uint64 {
+	if x != nil {
+		return x.OwnerSeqIDs
+	}
+	return nil
+}
+
+func (x *MarketBackup) GetDirectOffset() int64 {
+	if x != nil {
+		return x.DirectOffset
+	}
+	return 0
+}
+
+func (x *MarketBackup) GetLastPublishedSeqID() uint64 {
+	if x != nil {
+		return x.LastPublishedSeqID
+	}
+	return 0
+}
+
+func (x *MarketBackup) GetClosedCandles() []*Candle {
+	if x != nil {
+		return x.ClosedCandles
+	}
+	return nil
+}
+
+func (x *MarketBackup) GetPublishedSeqIDs() map[string]uint64 {
+	if x != nil {
+		return x.PublishedSeqIDs
+	}
+	return nil
+}
+
+// RecentOrder keeps an order recently received by the market along with the generated acknowledgement
+type RecentOrder struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The order as it was received by the engine
+	Order *Order `protobuf:"bytes,1,opt,name=Order,proto3" json:"Order,omitempty"`
+	// The acknowledgement event generated by the engine for the order
+	Ack *Event `protobuf:"bytes,2,opt,name=Ack,proto3" json:"Ack,omitempty"`
+}
+
+func (x *RecentOrder) Reset() {
+	*x = RecentOrder{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_market_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecentOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecentOrder) ProtoMessage() {}
+
+func (x *RecentOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecentOrder.ProtoReflect.Descriptor instead.
+func (*RecentOrder) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RecentOrder) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *RecentOrder) GetAck() *Event {
+	if x != nil {
+		return x.Ack
+	}
+	return nil
+}
+
+// RecentTrade keeps a trade recently generated by the market along with the prices of the matched orders
+type RecentTrade struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The trade as it was last reported by the engine
+	Trade *Trade `protobuf:"bytes,1,opt,name=Trade,proto3" json:"Trade,omitempty"`
+	// The limit prices of the ask and bid orders used to find them in the order book
+	AskPrice uint64 `protobuf:"varint,2,opt,name=AskPrice,proto3" json:"AskPrice,omitempty"`
+	BidPrice uint64 `protobuf:"varint,3,opt,name=BidPrice,proto3" json:"BidPrice,omitempty"`
+}
+
+func (x *RecentTrade) Reset() {
+	*x = RecentTrade{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_market_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecentTrade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecentTrade) ProtoMessage() {}
+
+func (x *RecentTrade) ProtoReflect() protoreflect.Message {
+	mi := &file_market_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecentTrade.ProtoReflect.Descriptor instead.
+func (*RecentTrade) Descriptor() ([]byte, []int) {
+	return file_market_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RecentTrade) GetTrade() *Trade {
+	if x != nil {
+		return x.Trade
+	}
+	return nil
+}
+
+func (x *RecentTrade) GetAskPrice() uint64 {
+	if x != nil {
+		return x.AskPrice
+	}
+	return 0
+}
+
+func (x *RecentTrade) GetBidPrice() uint64 {
+	if x != nil {
+		return x.BidPrice
+	}
+	return 0
+}
+
 var File_market_proto protoreflect.FileDescriptor
 
 var file_market_proto_rawDesc = []byte{
 	0x0a, 0x0c, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05,
 	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x1a, 0x0b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x22, 0xdc, 0x05, 0x0a, 0x0c, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x42, 0x61, 0x63,
-	0x6b, 0x75, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x1c, 0x0a, 0x09, 0x50, 0x61, 0x72,
-	0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x50, 0x61,
-	0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x4f, 0x66, 0x66, 0x73, 0x65,
-	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12,
-	0x1a, 0x0a, 0x08, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x50,
-	0x72, 0x69, 0x63, 0x65, 0x50, 0x72, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x0e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x50, 0x72, 0x65, 0x63, 0x69, 0x73,
-	0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x0f, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x50, 0x72, 0x65,
-	0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x56, 0x6f,
-	0x6c, 0x75, 0x6d, 0x65, 0x50, 0x72, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a,
-	0x09, 0x4c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x41, 0x73, 0x6b, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x09, 0x4c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x41, 0x73, 0x6b, 0x12, 0x1e, 0x0a, 0x0a, 0x48,
-	0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x0a, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x69, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x4c,
-	0x6f, 0x77, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18,
-	0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x4c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x48, 0x69, 0x67, 0x68, 0x65,
-	0x73, 0x74, 0x4c, 0x6f, 0x73, 0x73, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x10, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x4c, 0x6f, 0x73, 0x73, 0x50, 0x72,
-	0x69, 0x63, 0x65, 0x12, 0x2a, 0x0a, 0x09, 0x42, 0x75, 0x79, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73,
-	0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f,
-	0x72, 0x64, 0x65, 0x72, 0x52, 0x09, 0x42, 0x75, 0x79, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x12,
-	0x2c, 0x0a, 0x0a, 0x53, 0x65, 0x6c, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x18, 0x0c, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65,
-	0x72, 0x52, 0x0a, 0x53, 0x65, 0x6c, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x12, 0x38, 0x0a,
-	0x10, 0x42, 0x75, 0x79, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65,
-	0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e,
-	0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x10, 0x42, 0x75, 0x79, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74,
-	0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x3a, 0x0a, 0x11, 0x53, 0x65, 0x6c, 0x6c, 0x4d,
-	0x61, 0x72, 0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72,
-	0x52, 0x11, 0x53, 0x65, 0x6c, 0x6c, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72,
-	0x69, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x0f, 0x53, 0x74, 0x6f, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d,
-	0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x0f, 0x53, 0x74, 0x6f, 0x70,
-	0x45, 0x6e, 0x74, 0x72, 0x79, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x12, 0x34, 0x0a, 0x0e, 0x53,
-	0x74, 0x6f, 0x70, 0x4c, 0x6f, 0x73, 0x73, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x18, 0x10, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65,
-	0x72, 0x52, 0x0e, 0x53, 0x74, 0x6f, 0x70, 0x4c, 0x6f, 0x73, 0x73, 0x4f, 0x72, 0x64, 0x65, 0x72,
-	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18,
-	0x11, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x71, 0x49,
-	0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x54, 0x72, 0x61, 0x64, 0x65, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18,
-	0x12, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x54, 0x72, 0x61, 0x64, 0x65, 0x53, 0x65, 0x71, 0x49,
-	0x44, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x61, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x35, 0x2f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
-	0x73, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x2d, 0x65, 0x6e, 0x67, 0x69, 0x6e,
-	0x65, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x6f, 0x1a, 0x0b, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x0b, 0x74, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0c, 0x63, 0x61,
+	0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xfb, 0x0a, 0x0a, 0x0c, 0x4d,
+	0x61, 0x72, 0x6b, 0x65, 0x74, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x54,
+	0x6f, 0x70, 0x69, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x6f, 0x70, 0x69,
+	0x63, 0x12, 0x1c, 0x0a, 0x09, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x4d, 0x61, 0x72, 0x6b, 0x65,
+	0x74, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x4d, 0x61, 0x72, 0x6b, 0x65,
+	0x74, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x69, 0x63, 0x65, 0x50, 0x72, 0x65, 0x63,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x50, 0x72, 0x69,
+	0x63, 0x65, 0x50, 0x72, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x0f, 0x56,
+	0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x50, 0x72, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x50, 0x72, 0x65, 0x63,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x4c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x41,
+	0x73, 0x6b, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x4c, 0x6f, 0x77, 0x65, 0x73, 0x74,
+	0x41, 0x73, 0x6b, 0x12, 0x1e, 0x0a, 0x0a, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x69,
+	0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74,
+	0x42, 0x69, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x4c, 0x6f, 0x77, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x4c,
+	0x6f, 0x77, 0x65, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12,
+	0x2a, 0x0a, 0x10, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x4c, 0x6f, 0x73, 0x73, 0x50, 0x72,
+	0x69, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x48, 0x69, 0x67, 0x68, 0x65,
+	0x73, 0x74, 0x4c, 0x6f, 0x73, 0x73, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x2a, 0x0a, 0x09, 0x42,
+	0x75, 0x79, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x09, 0x42, 0x75,
+	0x79, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x12, 0x2c, 0x0a, 0x0a, 0x53, 0x65, 0x6c, 0x6c, 0x4f,
+	0x72, 0x64, 0x65, 0x72, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x0a, 0x53, 0x65, 0x6c, 0x6c, 0x4f,
+	0x72, 0x64, 0x65, 0x72, 0x73, 0x12, 0x38, 0x0a, 0x10, 0x42, 0x75, 0x79, 0x4d, 0x61, 0x72, 0x6b,
+	0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x10, 0x42,
+	0x75, 0x79, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12,
+	0x3a, 0x0a, 0x11, 0x53, 0x65, 0x6c, 0x6c, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64,
+	0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x11, 0x53, 0x65, 0x6c, 0x6c, 0x4d, 0x61,
+	0x72, 0x6b, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x0f, 0x53,
+	0x74, 0x6f, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x18, 0x0f,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x52, 0x0f, 0x53, 0x74, 0x6f, 0x70, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x73, 0x12, 0x34, 0x0a, 0x0e, 0x53, 0x74, 0x6f, 0x70, 0x4c, 0x6f, 0x73, 0x73, 0x4f,
+	0x72, 0x64, 0x65, 0x72, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x0e, 0x53, 0x74, 0x6f, 0x70, 0x4c,
+	0x6f, 0x73, 0x73, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x11, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x54, 0x72, 0x61,
+	0x64, 0x65, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x12, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x54,
+	0x72, 0x61, 0x64, 0x65, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x36, 0x0a, 0x0c, 0x52, 0x65, 0x63,
+	0x65, 0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x18, 0x13, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x52, 0x0c, 0x52, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x73, 0x12, 0x36, 0x0a, 0x0c, 0x52, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x54, 0x72, 0x61, 0x64, 0x65,
+	0x73, 0x18, 0x14, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e,
+	0x52, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x0c, 0x52, 0x65, 0x63,
+	0x65, 0x6e, 0x74, 0x54, 0x72, 0x61, 0x64, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x15, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x53, 0x65, 0x71, 0x49,
+	0x44, 0x12, 0x27, 0x0a, 0x07, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x18, 0x16, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x43, 0x61, 0x6e, 0x64, 0x6c,
+	0x65, 0x52, 0x07, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x0d, 0x54, 0x69,
+	0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x17, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65,
+	0x52, 0x0d, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12,
+	0x46, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x71, 0x49, 0x44, 0x73, 0x18, 0x18,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4d, 0x61, 0x72,
+	0x6b, 0x65, 0x74, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x2e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53,
+	0x65, 0x71, 0x49, 0x44, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65,
+	0x72, 0x53, 0x65, 0x71, 0x49, 0x44, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x19, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x44,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x2e, 0x0a, 0x12, 0x4c,
+	0x61, 0x73, 0x74, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x53, 0x65, 0x71, 0x49,
+	0x44, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x4c, 0x61, 0x73, 0x74, 0x50, 0x75, 0x62,
+	0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x33, 0x0a, 0x0d, 0x43,
+	0x6c, 0x6f, 0x73, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x18, 0x1b, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x43, 0x61, 0x6e, 0x64, 0x6c,
+	0x65, 0x52, 0x0d, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73,
+	0x12, 0x52, 0x0a, 0x0f, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x53, 0x65, 0x71,
+	0x49, 0x44, 0x73, 0x18, 0x1c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x2e, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x2e, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x53, 0x65, 0x71, 0x49, 0x44, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x0f, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x64, 0x53, 0x65,
+	0x71, 0x49, 0x44, 0x73, 0x1a, 0x3e, 0x0a, 0x10, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x71,
+	0x49, 0x44, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x1a, 0x42, 0x0a, 0x14, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65,
+	0x64, 0x53, 0x65, 0x71, 0x49, 0x44, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x51, 0x0a, 0x0b, 0x52, 0x65, 0x63, 0x65,
+	0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x05, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f,
+	0x72, 0x64, 0x65, 0x72, 0x52, 0x05, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x03, 0x41,
+	0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x03, 0x41, 0x63, 0x6b, 0x22, 0x69, 0x0a, 0x0b, 0x52,
+	0x65, 0x63, 0x65, 0x6e, 0x74, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x54, 0x72,
+	0x61, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x2e, 0x54, 0x72, 0x61, 0x64, 0x65, 0x52, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x41, 0x73, 0x6b, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x08, 0x41, 0x73, 0x6b, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x69,
+	0x64, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x42, 0x69,
+	0x64, 0x50, 0x72, 0x69, 0x63, 0x65, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x35, 0x2f, 0x70, 0x72,
+	0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x2d,
+	0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -277,23 +550,40 @@ func file_market_proto_rawDescGZIP() []byte {
 	return file_market_proto_rawDescData
 }
 
-var file_market_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_market_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_market_proto_goTypes = []interface{}{
 	(*MarketBackup)(nil), // 0: model.MarketBackup
-	(*Order)(nil),        // 1: model.Order
+	(*RecentOrder)(nil),  // 1: model.RecentOrder
+	(*RecentTrade)(nil),  // 2: model.RecentTrade
+	nil,                  // 3: model.MarketBackup.OwnerSeqIDsEntry
+	nil,                  // 4: model.MarketBackup.PublishedSeqIDsEntry
+	(*Order)(nil),        // 5: model.Order
+	(*Candle)(nil),       // 6: model.Candle
+	(*Event)(nil),        // 7: model.Event
+	(*Trade)(nil),        // 8: model.Trade
 }
 var file_market_proto_depIdxs = []int32{
-	1, // 0: model.MarketBackup.BuyOrders:type_name -> model.Order
-	1, // 1: model.MarketBackup.SellOrders:type_name -> model.Order
-	1, // 2: model.MarketBackup.BuyMarketEntries:type_name -> model.Order
-	1, // 3: model.MarketBackup.SellMarketEntries:type_name -> model.Order
-	1, // 4: model.MarketBackup.StopEntryOrders:type_name -> model.Order
-	1, // 5: model.MarketBackup.StopLossOrders:type_name -> model.Order
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	5,  // 0: model.MarketBackup.BuyOrders:type_name -> model.Order
+	5,  // 1: model.MarketBackup.SellOrders:type_name -> model.Order
+	5,  // 2: model.MarketBackup.BuyMarketEntries:type_name -> model.Order
+	5,  // 3: model.MarketBackup.SellMarketEntries:type_name -> model.Order
+	5,  // 4: model.MarketBackup.StopEntryOrders:type_name -> model.Order
+	5,  // 5: model.MarketBackup.StopLossOrders:type_name -> model.Order
+	1,  // 6: model.MarketBackup.RecentOrders:type_name -> model.RecentOrder
+	2,  // 7: model.MarketBackup.RecentTrades:type_name -> model.RecentTrade
+	6,  // 8: model.MarketBackup.Candles:type_name -> model.Candle
+	6,  // 9: model.MarketBackup.TickerBuckets:type_name -> model.Candle
+	3,  // 10: model.MarketBackup.OwnerSeqIDs:type_name -> model.MarketBackup.OwnerSeqIDsEntry
+	6,  // 11: model.MarketBackup.ClosedCandles:type_name -> model.Candle
+	4,  // 12: model.MarketBackup.PublishedSeqIDs:type_name -> model.MarketBackup.PublishedSeqIDsEntry
+	5,  // 13: model.RecentOrder.Order:type_name -> model.Order
+	7,  // 14: model.RecentOrder.Ack:type_name -> model.Event
+	8,  // 15: model.RecentTrade.Trade:type_name -> model.Trade
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_market_proto_init() }
@@ -302,6 +592,9 @@ func file_market_proto_init() {
 		return
 	}
 	file_order_proto_init()
+	file_event_proto_init()
+	file_trade_proto_init()
+	file_candle_proto_init()
 	if !protoimpl.UnsafeEnabled {
 		file_market_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*MarketBackup); i {
@@ -315,6 +608,30 @@ func file_market_proto_init() {
 				return nil
 			}
 		}
+		file_market_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecentOrder); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_market_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecentTrade); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -322,7 +639,7 @@ func file_market_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_market_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -11,10 +11,20 @@ func NewOrder(id, price, amount uint64, side MarketSide, category OrderType, eve
 
 // Valid checks if the order is valid based on the type of the order and the price/amount/funds
 func (order *Order) Valid() bool {
+	// cancel requests can identify the order by the owner and client order id instead
+	if order.CancelByClientOrderID() {
+		return order.OwnerID != 0
+	}
 	if order.ID == 0 {
 		return false
 	}
 	switch order.EventType {
+	case CommandType_TradeBust:
+		return order.TradeSeqID != 0
+	case CommandType_TradeCorrect:
+		return order.TradeSeqID != 0 && (order.Price != 0 || order.Amount != 0)
+	case CommandType_CancelAll:
+		return order.OwnerID != 0
 	case CommandType_NewOrder:
 		{
 			if order.Stop != StopLoss_None {
@@ -44,6 +54,11 @@ func (order *Order) Valid() bool {
 	return true
 }
 
+// CancelByClientOrderID checks if the cancel request identifies the order by the owner and client order id
+func (order *Order) CancelByClientOrderID() bool {
+	return order.EventType == CommandType_CancelOrder && order.ID == 0 && order.ClientOrderID != ""
+}
+
 // Filled checks if the order can be considered filled
 func (order *Order) Filled() bool {
 	if order.EventType != CommandType_NewOrder {
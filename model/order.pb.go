@@ -244,6 +244,12 @@ const (
 	// The whole market should be archived and stored in a safe location
 	// This command may not be needed since the engine should already create regular backups of the current orderbook
 	CommandType_BackupMarket CommandType = 2
+	// A trade previously generated by the market should be reversed
+	CommandType_TradeBust CommandType = 3
+	// The price or amount of a trade previously generated by the market should be corrected
+	CommandType_TradeCorrect CommandType = 4
+	// All the open orders of the owner should be cancelled, including the pending stop orders
+	CommandType_CancelAll CommandType = 5
 )
 
 // Enum value maps for CommandType.
@@ -252,11 +258,17 @@ var (
 		0: "NewOrder",
 		1: "CancelOrder",
 		2: "BackupMarket",
+		3: "TradeBust",
+		4: "TradeCorrect",
+		5: "CancelAll",
 	}
 	CommandType_value = map[string]int32{
 		"NewOrder":     0,
 		"CancelOrder":  1,
 		"BackupMarket": 2,
+		"TradeBust":    3,
+		"TradeCorrect": 4,
+		"CancelAll":    5,
 	}
 )
 
@@ -352,6 +364,19 @@ type Order struct {
 	FilledAmount uint64 `protobuf:"varint,13,opt,name=FilledAmount,proto3" json:"FilledAmount,omitempty"`
 	// The amount of used funds from the funds
 	UsedFunds uint64 `protobuf:"varint,14,opt,name=UsedFunds,proto3" json:"UsedFunds,omitempty"`
+	// Client Order ID: An id defined by the user to identify the order
+	// - Must be unique for the same owner among all open orders
+	// - Echoed back in every event generated for the order
+	// - Can be used together with the OwnerID to cancel the order instead of the ID
+	ClientOrderID string `protobuf:"bytes,15,opt,name=ClientOrderID,proto3" json:"ClientOrderID,omitempty"`
+	// The sequence id of the event with which the engine acknowledged the order
+	// - Set by the engine when the order is added in the order book
+	// - Used to report the original placement of the maker order in each trade
+	PlacedSeqID uint64 `protobuf:"varint,16,opt,name=PlacedSeqID,proto3" json:"PlacedSeqID,omitempty"`
+	// The sequence id of the trade that should be busted or corrected
+	TradeSeqID uint64 `protobuf:"varint,17,opt,name=TradeSeqID,proto3" json:"TradeSeqID,omitempty"`
+	// Restore the busted amount on the orders of the trade that are still resting in the order book
+	RestoreOrders bool `protobuf:"varint,18,opt,name=RestoreOrders,proto3" json:"RestoreOrders,omitempty"`
 }
 
 func (x *Order) Reset() {
@@ -484,11 +509,39 @@ func (x *Order) GetUsedFunds() uint64 {
 	return 0
 }
 
+func (x *Order) GetClientOrderID() string {
+	if x != nil {
+		return x.ClientOrderID
+	}
+	return ""
+}
+
+func (x *Order) GetPlacedSeqID() uint64 {
+	if x != nil {
+		return x.PlacedSeqID
+	}
+	return 0
+}
+
+func (x *Order) GetTradeSeqID() uint64 {
+	if x != nil {
+		return x.TradeSeqID
+	}
+	return 0
+}
+
+func (x *Order) GetRestoreOrders() bool {
+	if x != nil {
+		return x.RestoreOrders
+	}
+	return false
+}
+
 var File_order_proto protoreflect.FileDescriptor
 
 var file_order_proto_rawDesc = []byte{
 	0x0a, 0x0b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6d,
-	0x6f, 0x64, 0x65, 0x6c, 0x22, 0xbd, 0x03, 0x0a, 0x05, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x30,
+	0x6f, 0x64, 0x65, 0x6c, 0x22, 0xcb, 0x04, 0x0a, 0x05, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x30,
 	0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
 	0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
 	0x64, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65,
@@ -516,27 +569,39 @@ var file_order_proto_rawDesc = []byte{
 	0x6e, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x46, 0x69, 0x6c, 0x6c, 0x65, 0x64,
 	0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x55, 0x73, 0x65, 0x64, 0x46, 0x75,
 	0x6e, 0x64, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x55, 0x73, 0x65, 0x64, 0x46,
-	0x75, 0x6e, 0x64, 0x73, 0x2a, 0x1f, 0x0a, 0x0a, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x53, 0x69,
-	0x64, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x75, 0x79, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x53,
-	0x65, 0x6c, 0x6c, 0x10, 0x01, 0x2a, 0x22, 0x0a, 0x09, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x79,
-	0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x10, 0x00, 0x12, 0x0a, 0x0a,
-	0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x10, 0x01, 0x2a, 0x59, 0x0a, 0x0b, 0x4f, 0x72, 0x64,
-	0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x65, 0x6e, 0x64,
-	0x69, 0x6e, 0x67, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x55, 0x6e, 0x74, 0x6f, 0x75, 0x63, 0x68,
-	0x65, 0x64, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x6c,
-	0x79, 0x46, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x61, 0x6e,
-	0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x6c,
-	0x65, 0x64, 0x10, 0x04, 0x2a, 0x29, 0x0a, 0x08, 0x53, 0x74, 0x6f, 0x70, 0x4c, 0x6f, 0x73, 0x73,
-	0x12, 0x08, 0x0a, 0x04, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x6f,
-	0x73, 0x73, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x10, 0x02, 0x2a,
-	0x3e, 0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c,
-	0x0a, 0x08, 0x4e, 0x65, 0x77, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b,
-	0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x01, 0x12, 0x10, 0x0a,
-	0x0c, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x10, 0x02, 0x42,
-	0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72,
-	0x6f, 0x75, 0x6e, 0x64, 0x32, 0x35, 0x2f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x2f,
-	0x6d, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x2d, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x2f,
-	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x75, 0x6e, 0x64, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4f, 0x72,
+	0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x43, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x20, 0x0a, 0x0b, 0x50, 0x6c,
+	0x61, 0x63, 0x65, 0x64, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x10, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0b, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x64, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a,
+	0x54, 0x72, 0x61, 0x64, 0x65, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x11, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0a, 0x54, 0x72, 0x61, 0x64, 0x65, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x24, 0x0a, 0x0d,
+	0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x18, 0x12, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0d, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x4f, 0x72, 0x64, 0x65,
+	0x72, 0x73, 0x2a, 0x1f, 0x0a, 0x0a, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x53, 0x69, 0x64, 0x65,
+	0x12, 0x07, 0x0a, 0x03, 0x42, 0x75, 0x79, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x65, 0x6c,
+	0x6c, 0x10, 0x01, 0x2a, 0x22, 0x0a, 0x09, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x09, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x4d,
+	0x61, 0x72, 0x6b, 0x65, 0x74, 0x10, 0x01, 0x2a, 0x59, 0x0a, 0x0b, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x55, 0x6e, 0x74, 0x6f, 0x75, 0x63, 0x68, 0x65, 0x64,
+	0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x61, 0x72, 0x74, 0x69, 0x61, 0x6c, 0x6c, 0x79, 0x46,
+	0x69, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x6c, 0x65, 0x64, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x6c, 0x65, 0x64,
+	0x10, 0x04, 0x2a, 0x29, 0x0a, 0x08, 0x53, 0x74, 0x6f, 0x70, 0x4c, 0x6f, 0x73, 0x73, 0x12, 0x08,
+	0x0a, 0x04, 0x4e, 0x6f, 0x6e, 0x65, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x6f, 0x73, 0x73,
+	0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x10, 0x02, 0x2a, 0x6e, 0x0a,
+	0x0b, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08,
+	0x4e, 0x65, 0x77, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x42,
+	0x61, 0x63, 0x6b, 0x75, 0x70, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x10, 0x02, 0x12, 0x0d, 0x0a,
+	0x09, 0x54, 0x72, 0x61, 0x64, 0x65, 0x42, 0x75, 0x73, 0x74, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c,
+	0x54, 0x72, 0x61, 0x64, 0x65, 0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x10, 0x04, 0x12, 0x0d,
+	0x0a, 0x09, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x41, 0x6c, 0x6c, 0x10, 0x05, 0x42, 0x34, 0x5a,
+	0x32, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x6f, 0x75,
+	0x6e, 0x64, 0x32, 0x35, 0x2f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x2d, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x2f, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+// NewOrderFeedSnapshotMessage returns a new order feed message with a snapshot of all open orders
+func NewOrderFeedSnapshotMessage(market string, snapshot OrderFeedSnapshot) OrderFeedMessage {
+	return OrderFeedMessage{
+		Market:    market,
+		Payload:   &OrderFeedMessage_Snapshot{Snapshot: &snapshot},
+		CreatedAt: time.Now().UTC().UnixNano(),
+	}
+}
+
+// NewOrderFeedUpdatesMessage returns a new order feed message with the updates generated by a command
+func NewOrderFeedUpdatesMessage(market string, updates []*OrderFeedUpdate) OrderFeedMessage {
+	return OrderFeedMessage{
+		Market:    market,
+		Payload:   &OrderFeedMessage_Updates{Updates: &OrderFeedUpdates{Updates: updates}},
+		CreatedAt: time.Now().UTC().UnixNano(),
+	}
+}
+
+// FromBinary loads an order feed message from a byte array
+func (msg *OrderFeedMessage) FromBinary(raw []byte) error {
+	return proto.Unmarshal(raw, msg)
+}
+
+// ToBinary converts an order feed message to a byte string
+func (msg *OrderFeedMessage) ToBinary() ([]byte, error) {
+	return proto.Marshal(msg)
+}
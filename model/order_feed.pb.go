@@ -0,0 +1,684 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.14.0
+// source: order_feed.proto
+
+package model
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+type OrderFeedAction int32
+
+const (
+	// A new order was added at the end of the queue of its price
+	OrderFeedAction_AddOrder OrderFeedAction = 0
+	// The amount left to be filled on the order changed without a trade. The order keeps its priority.
+	OrderFeedAction_ModifyOrder OrderFeedAction = 1
+	// The order was removed from the order book before being filled
+	OrderFeedAction_DeleteOrder OrderFeedAction = 2
+	// The order was matched with an incoming order. It is removed from the order book once nothing is left to fill.
+	OrderFeedAction_ExecuteOrder OrderFeedAction = 3
+)
+
+// Enum value maps for OrderFeedAction.
+var (
+	OrderFeedAction_name = map[int32]string{
+		0: "AddOrder",
+		1: "ModifyOrder",
+		2: "DeleteOrder",
+		3: "ExecuteOrder",
+	}
+	OrderFeedAction_value = map[string]int32{
+		"AddOrder":     0,
+		"ModifyOrder":  1,
+		"DeleteOrder":  2,
+		"ExecuteOrder": 3,
+	}
+)
+
+func (x OrderFeedAction) Enum() *OrderFeedAction {
+	p := new(OrderFeedAction)
+	*p = x
+	return p
+}
+
+func (x OrderFeedAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderFeedAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_order_feed_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderFeedAction) Type() protoreflect.EnumType {
+	return &file_order_feed_proto_enumTypes[0]
+}
+
+func (x OrderFeedAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderFeedAction.Descriptor instead.
+func (OrderFeedAction) EnumDescriptor() ([]byte, []int) {
+	return file_order_feed_proto_rawDescGZIP(), []int{0}
+}
+
+// OrderFeedUpdate is a single change of an open order in the order book
+type OrderFeedUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The sequence number of the update in the order feed of the market
+	SeqID uint64 `protobuf:"varint,1,opt,name=SeqID,proto3" json:"SeqID,omitempty"`
+	// The sequence id of the last event generated by the market when the update was generated
+	EventSeqID uint64          `protobuf:"varint,2,opt,name=EventSeqID,proto3" json:"EventSeqID,omitempty"`
+	Action     OrderFeedAction `protobuf:"varint,3,opt,name=Action,proto3,enum=model.OrderFeedAction" json:"Action,omitempty"`
+	OrderID    uint64          `protobuf:"varint,4,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	Side       MarketSide      `protobuf:"varint,5,opt,name=Side,proto3,enum=model.MarketSide" json:"Side,omitempty"`
+	Price      uint64          `protobuf:"varint,6,opt,name=Price,proto3" json:"Price,omitempty"`
+	// The amount added, removed or executed by the update
+	Amount uint64 `protobuf:"varint,7,opt,name=Amount,proto3" json:"Amount,omitempty"`
+	// The amount left to be filled on the order after the update
+	Remaining uint64 `protobuf:"varint,8,opt,name=Remaining,proto3" json:"Remaining,omitempty"`
+	// The sequence id of the trade for executions
+	TradeSeqID uint64 `protobuf:"varint,9,opt,name=TradeSeqID,proto3" json:"TradeSeqID,omitempty"`
+}
+
+func (x *OrderFeedUpdate) Reset() {
+	*x = OrderFeedUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_order_feed_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrderFeedUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFeedUpdate) ProtoMessage() {}
+
+func (x *OrderFeedUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_order_feed_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFeedUpdate.ProtoReflect.Descriptor instead.
+func (*OrderFeedUpdate) Descriptor() ([]byte, []int) {
+	return file_order_feed_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OrderFeedUpdate) GetSeqID() uint64 {
+	if x != nil {
+		return x.SeqID
+	}
+	return 0
+}
+
+func (x *OrderFeedUpdate) GetEventSeqID() uint64 {
+	if x != nil {
+		return x.EventSeqID
+	}
+	return 0
+}
+
+func (x *OrderFeedUpdate) GetAction() OrderFeedAction {
+	if x != nil {
+		return x.Action
+	}
+	return OrderFeedAction_AddOrder
+}
+
+func (x *OrderFeedUpdate) GetOrderID() uint64 {
+	if x != nil {
+		return x.OrderID
+	}
+	return 0
+}
+
+func (x *OrderFeedUpdate) GetSide() MarketSide {
+	if x != nil {
+		return x.Side
+	}
+	return MarketSide_Buy
+}
+
+func (x *OrderFeedUpdate) GetPrice() uint64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderFeedUpdate) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *OrderFeedUpdate) GetRemaining() uint64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *OrderFeedUpdate) GetTradeSeqID() uint64 {
+	if x != nil {
+		return x.TradeSeqID
+	}
+	return 0
+}
+
+// OrderFeedOrder is an open order in a snapshot of the order feed
+type OrderFeedOrder struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OrderID uint64 `protobuf:"varint,1,opt,name=OrderID,proto3" json:"OrderID,omitempty"`
+	Price   uint64 `protobuf:"varint,2,opt,name=Price,proto3" json:"Price,omitempty"`
+	// The amount left to be filled on the order
+	Remaining uint64 `protobuf:"varint,3,opt,name=Remaining,proto3" json:"Remaining,omitempty"`
+}
+
+func (x *OrderFeedOrder) Reset() {
+	*x = OrderFeedOrder{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_order_feed_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrderFeedOrder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFeedOrder) ProtoMessage() {}
+
+func (x *OrderFeedOrder) ProtoReflect() protoreflect.Message {
+	mi := &file_order_feed_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFeedOrder.ProtoReflect.Descriptor instead.
+func (*OrderFeedOrder) Descriptor() ([]byte, []int) {
+	return file_order_feed_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderFeedOrder) GetOrderID() uint64 {
+	if x != nil {
+		return x.OrderID
+	}
+	return 0
+}
+
+func (x *OrderFeedOrder) GetPrice() uint64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderFeedOrder) GetRemaining() uint64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+// OrderFeedSnapshot contains all open orders of the order book
+type OrderFeedSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The sequence number of the last update included in the snapshot
+	SeqID uint64 `protobuf:"varint,1,opt,name=SeqID,proto3" json:"SeqID,omitempty"`
+	// The sequence id of the last event generated by the market
+	EventSeqID uint64 `protobuf:"varint,2,opt,name=EventSeqID,proto3" json:"EventSeqID,omitempty"`
+	// Bids sorted from the highest price and in the order in which they are matched for the same price
+	Bids []*OrderFeedOrder `protobuf:"bytes,3,rep,name=Bids,proto3" json:"Bids,omitempty"`
+	// Asks sorted from the lowest price and in the order in which they are matched for the same price
+	Asks []*OrderFeedOrder `protobuf:"bytes,4,rep,name=Asks,proto3" json:"Asks,omitempty"`
+}
+
+func (x *OrderFeedSnapshot) Reset() {
+	*x = OrderFeedSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_order_feed_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrderFeedSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFeedSnapshot) ProtoMessage() {}
+
+func (x *OrderFeedSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_order_feed_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFeedSnapshot.ProtoReflect.Descriptor instead.
+func (*OrderFeedSnapshot) Descriptor() ([]byte, []int) {
+	return file_order_feed_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderFeedSnapshot) GetSeqID() uint64 {
+	if x != nil {
+		return x.SeqID
+	}
+	return 0
+}
+
+func (x *OrderFeedSnapshot) GetEventSeqID() uint64 {
+	if x != nil {
+		return x.EventSeqID
+	}
+	return 0
+}
+
+func (x *OrderFeedSnapshot) GetBids() []*OrderFeedOrder {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+func (x *OrderFeedSnapshot) GetAsks() []*OrderFeedOrder {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+// OrderFeedUpdates contains the updates generated by a single command
+type OrderFeedUpdates struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Updates []*OrderFeedUpdate `protobuf:"bytes,1,rep,name=Updates,proto3" json:"Updates,omitempty"`
+}
+
+func (x *OrderFeedUpdates) Reset() {
+	*x = OrderFeedUpdates{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_order_feed_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrderFeedUpdates) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFeedUpdates) ProtoMessage() {}
+
+func (x *OrderFeedUpdates) ProtoReflect() protoreflect.Message {
+	mi := &file_order_feed_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFeedUpdates.ProtoReflect.Descriptor instead.
+func (*OrderFeedUpdates) Descriptor() ([]byte, []int) {
+	return file_order_feed_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderFeedUpdates) GetUpdates() []*OrderFeedUpdate {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+// OrderFeedMessage is published on the order feed topic of the market
+type OrderFeedMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Market    string `protobuf:"bytes,1,opt,name=Market,proto3" json:"Market,omitempty"`
+	CreatedAt int64  `protobuf:"varint,2,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	// Types that are assignable to Payload:
+	//	*OrderFeedMessage_Snapshot
+	//	*OrderFeedMessage_Updates
+	Payload isOrderFeedMessage_Payload `protobuf_oneof:"Payload"`
+}
+
+func (x *OrderFeedMessage) Reset() {
+	*x = OrderFeedMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_order_feed_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OrderFeedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderFeedMessage) ProtoMessage() {}
+
+func (x *OrderFeedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_order_feed_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderFeedMessage.ProtoReflect.Descriptor instead.
+func (*OrderFeedMessage) Descriptor() ([]byte, []int) {
+	return file_order_feed_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrderFeedMessage) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *OrderFeedMessage) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (m *OrderFeedMessage) GetPayload() isOrderFeedMessage_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (x *OrderFeedMessage) GetSnapshot() *OrderFeedSnapshot {
+	if x, ok := x.GetPayload().(*OrderFeedMessage_Snapshot); ok {
+		return x.Snapshot
+	}
+	return nil
+}
+
+func (x *OrderFeedMessage) GetUpdates() *OrderFeedUpdates {
+	if x, ok := x.GetPayload().(*OrderFeedMessage_Updates); ok {
+		return x.Updates
+	}
+	return nil
+}
+
+type isOrderFeedMessage_Payload interface {
+	isOrderFeedMessage_Payload()
+}
+
+type OrderFeedMessage_Snapshot struct {
+	Snapshot *OrderFeedSnapshot `protobuf:"bytes,3,opt,name=Snapshot,proto3,oneof"`
+}
+
+type OrderFeedMessage_Updates struct {
+	Updates *OrderFeedUpdates `protobuf:"bytes,4,opt,name=Updates,proto3,oneof"`
+}
+
+func (*OrderFeedMessage_Snapshot) isOrderFeedMessage_Payload() {}
+
+func (*OrderFeedMessage_Updates) isOrderFeedMessage_Payload() {}
+
+var File_order_feed_proto protoreflect.FileDescriptor
+
+var file_order_feed_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x66, 0x65, 0x65, 0x64, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x1a, 0x0b, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa4, 0x02, 0x0a, 0x0f, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x46, 0x65, 0x65, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x65,
+	0x71, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44,
+	0x12, 0x1e, 0x0a, 0x0a, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x71, 0x49, 0x44,
+	0x12, 0x2e, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x16, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x46, 0x65,
+	0x65, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x18, 0x0a, 0x07, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x25, 0x0a, 0x04, 0x53, 0x69,
+	0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x2e, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x53, 0x69, 0x64, 0x65, 0x52, 0x04, 0x53, 0x69, 0x64,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x05, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x1e, 0x0a,
+	0x0a, 0x54, 0x72, 0x61, 0x64, 0x65, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0a, 0x54, 0x72, 0x61, 0x64, 0x65, 0x53, 0x65, 0x71, 0x49, 0x44, 0x22, 0x5e, 0x0a,
+	0x0e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12,
+	0x18, 0x0a, 0x07, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x50, 0x72, 0x69,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x22, 0x9f, 0x01,
+	0x0a, 0x11, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x1e, 0x0a, 0x0a, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x29, 0x0a, 0x04, 0x42, 0x69, 0x64,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e,
+	0x4f, 0x72, 0x64, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x04,
+	0x42, 0x69, 0x64, 0x73, 0x12, 0x29, 0x0a, 0x04, 0x41, 0x73, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x46, 0x65, 0x65, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x04, 0x41, 0x73, 0x6b, 0x73, 0x22,
+	0x44, 0x0a, 0x10, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x07, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x07, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x73, 0x22, 0xc0, 0x01, 0x0a, 0x10, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x46,
+	0x65, 0x65, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61,
+	0x72, 0x6b, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x4d, 0x61, 0x72, 0x6b,
+	0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x12, 0x36, 0x0a, 0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x46, 0x65, 0x65, 0x64, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x48, 0x00, 0x52, 0x08,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x33, 0x0a, 0x07, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x46, 0x65, 0x65, 0x64, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x73, 0x48, 0x00, 0x52, 0x07, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x42, 0x09, 0x0a,
+	0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2a, 0x53, 0x0a, 0x0f, 0x4f, 0x72, 0x64, 0x65,
+	0x72, 0x46, 0x65, 0x65, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0c, 0x0a, 0x08, 0x41,
+	0x64, 0x64, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4d, 0x6f, 0x64,
+	0x69, 0x66, 0x79, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x45,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x10, 0x03, 0x42, 0x34, 0x5a,
+	0x32, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x6f, 0x75,
+	0x6e, 0x64, 0x32, 0x35, 0x2f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x2d, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x2f, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_order_feed_proto_rawDescOnce sync.Once
+	file_order_feed_proto_rawDescData = file_order_feed_proto_rawDesc
+)
+
+func file_order_feed_proto_rawDescGZIP() []byte {
+	file_order_feed_proto_rawDescOnce.Do(func() {
+		file_order_feed_proto_rawDescData = protoimpl.X.CompressGZIP(file_order_feed_proto_rawDescData)
+	})
+	return file_order_feed_proto_rawDescData
+}
+
+var file_order_feed_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_order_feed_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_order_feed_proto_goTypes = []interface{}{
+	(OrderFeedAction)(0),      // 0: model.OrderFeedAction
+	(*OrderFeedUpdate)(nil),   // 1: model.OrderFeedUpdate
+	(*OrderFeedOrder)(nil),    // 2: model.OrderFeedOrder
+	(*OrderFeedSnapshot)(nil), // 3: model.OrderFeedSnapshot
+	(*OrderFeedUpdates)(nil),  // 4: model.OrderFeedUpdates
+	(*OrderFeedMessage)(nil),  // 5: model.OrderFeedMessage
+	(MarketSide)(0),           // 6: model.MarketSide
+}
+var file_order_feed_proto_depIdxs = []int32{
+	0, // 0: model.OrderFeedUpdate.Action:type_name -> model.OrderFeedAction
+	6, // 1: model.OrderFeedUpdate.Side:type_name -> model.MarketSide
+	2, // 2: model.OrderFeedSnapshot.Bids:type_name -> model.OrderFeedOrder
+	2, // 3: model.OrderFeedSnapshot.Asks:type_name -> model.OrderFeedOrder
+	1, // 4: model.OrderFeedUpdates.Updates:type_name -> model.OrderFeedUpdate
+	3, // 5: model.OrderFeedMessage.Snapshot:type_name -> model.OrderFeedSnapshot
+	4, // 6: model.OrderFeedMessage.Updates:type_name -> model.OrderFeedUpdates
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_order_feed_proto_init() }
+func file_order_feed_proto_init() {
+	if File_order_feed_proto != nil {
+		return
+	}
+	file_order_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_order_feed_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrderFeedUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_order_feed_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrderFeedOrder); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_order_feed_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrderFeedSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_order_feed_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrderFeedUpdates); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_order_feed_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OrderFeedMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_order_feed_proto_msgTypes[4].OneofWrappers = []interface{}{
+		(*OrderFeedMessage_Snapshot)(nil),
+		(*OrderFeedMessage_Updates)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_order_feed_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_order_feed_proto_goTypes,
+		DependencyIndexes: file_order_feed_proto_depIdxs,
+		EnumInfos:         file_order_feed_proto_enumTypes,
+		MessageInfos:      file_order_feed_proto_msgTypes,
+	}.Build()
+	File_order_feed_proto = out.File
+	file_order_feed_proto_rawDesc = nil
+	file_order_feed_proto_goTypes = nil
+	file_order_feed_proto_depIdxs = nil
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	proto "github.com/golang/protobuf/proto"
+)
+
+// FromBinary loads an owner event from a byte array
+func (event *OwnerEvent) FromBinary(msg []byte) error {
+	return proto.Unmarshal(msg, event)
+}
+
+// ToBinary converts an owner event to a byte string
+func (event *OwnerEvent) ToBinary() ([]byte, error) {
+	return proto.Marshal(event)
+}
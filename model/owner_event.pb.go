@@ -0,0 +1,187 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.14.0
+// source: owner_event.proto
+
+package model
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// OwnerEvent is an event published on the private stream of an owner
+type OwnerEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The owner that receives the event
+	OwnerID uint64 `protobuf:"varint,1,opt,name=OwnerID,proto3" json:"OwnerID,omitempty"`
+	// Sequence of the event in the stream of the owner, increased by one for each event so gaps can be detected
+	OwnerSeqID uint64 `protobuf:"varint,2,opt,name=OwnerSeqID,proto3" json:"OwnerSeqID,omitempty"`
+	// Set when the owner is a drop copy account that receives the event of another owner
+	DropCopy bool   `protobuf:"varint,3,opt,name=DropCopy,proto3" json:"DropCopy,omitempty"`
+	Event    *Event `protobuf:"bytes,4,opt,name=Event,proto3" json:"Event,omitempty"`
+}
+
+func (x *OwnerEvent) Reset() {
+	*x = OwnerEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_owner_event_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OwnerEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OwnerEvent) ProtoMessage() {}
+
+func (x *OwnerEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_owner_event_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OwnerEvent.ProtoReflect.Descriptor instead.
+func (*OwnerEvent) Descriptor() ([]byte, []int) {
+	return file_owner_event_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OwnerEvent) GetOwnerID() uint64 {
+	if x != nil {
+		return x.OwnerID
+	}
+	return 0
+}
+
+func (x *OwnerEvent) GetOwnerSeqID() uint64 {
+	if x != nil {
+		return x.OwnerSeqID
+	}
+	return 0
+}
+
+func (x *OwnerEvent) GetDropCopy() bool {
+	if x != nil {
+		return x.DropCopy
+	}
+	return false
+}
+
+func (x *OwnerEvent) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+var File_owner_event_proto protoreflect.FileDescriptor
+
+var file_owner_event_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x1a, 0x0b, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x86, 0x01, 0x0a, 0x0a, 0x4f, 0x77, 0x6e, 0x65,
+	0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x49,
+	0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x44,
+	0x12, 0x1e, 0x0a, 0x0a, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x65, 0x71, 0x49, 0x44,
+	0x12, 0x1a, 0x0a, 0x08, 0x44, 0x72, 0x6f, 0x70, 0x43, 0x6f, 0x70, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x44, 0x72, 0x6f, 0x70, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x22, 0x0a, 0x05,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61,
+	0x72, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x35, 0x2f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73,
+	0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x2d, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65,
+	0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_owner_event_proto_rawDescOnce sync.Once
+	file_owner_event_proto_rawDescData = file_owner_event_proto_rawDesc
+)
+
+func file_owner_event_proto_rawDescGZIP() []byte {
+	file_owner_event_proto_rawDescOnce.Do(func() {
+		file_owner_event_proto_rawDescData = protoimpl.X.CompressGZIP(file_owner_event_proto_rawDescData)
+	})
+	return file_owner_event_proto_rawDescData
+}
+
+var file_owner_event_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_owner_event_proto_goTypes = []interface{}{
+	(*OwnerEvent)(nil), // 0: model.OwnerEvent
+	(*Event)(nil),      // 1: model.Event
+}
+var file_owner_event_proto_depIdxs = []int32{
+	1, // 0: model.OwnerEvent.Event:type_name -> model.Event
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_owner_event_proto_init() }
+func file_owner_event_proto_init() {
+	if File_owner_event_proto != nil {
+		return
+	}
+	file_event_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_owner_event_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OwnerEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_owner_event_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_owner_event_proto_goTypes,
+		DependencyIndexes: file_owner_event_proto_depIdxs,
+		MessageInfos:      file_owner_event_proto_msgTypes,
+	}.Build()
+	File_owner_event_proto = out.File
+	file_owner_event_proto_rawDesc = nil
+	file_owner_event_proto_goTypes = nil
+	file_owner_event_proto_depIdxs = nil
+}
@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package model
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// MatchingEngineClient is the client API for MatchingEngine service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MatchingEngineClient interface {
+	// SubmitOrder sends a new order to its market and returns the events generated by the engine
+	SubmitOrder(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Events, error)
+	// CancelOrder sends a cancel request to its market and returns the events generated by the engine
+	CancelOrder(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Events, error)
+	// Subscribe streams the events generated by a market
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (MatchingEngine_SubscribeClient, error)
+}
+
+type matchingEngineClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMatchingEngineClient(cc grpc.ClientConnInterface) MatchingEngineClient {
+	return &matchingEngineClient{cc}
+}
+
+func (c *matchingEngineClient) SubmitOrder(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Events, error) {
+	out := new(Events)
+	err := c.cc.Invoke(ctx, "/model.MatchingEngine/SubmitOrder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchingEngineClient) CancelOrder(ctx context.Context, in *Order, opts ...grpc.CallOption) (*Events, error) {
+	out := new(Events)
+	err := c.cc.Invoke(ctx, "/model.MatchingEngine/CancelOrder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchingEngineClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (MatchingEngine_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MatchingEngine_ServiceDesc.Streams[0], "/model.MatchingEngine/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &matchingEngineSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MatchingEngine_SubscribeClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type matchingEngineSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *matchingEngineSubscribeClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MatchingEngineServer is the server API for MatchingEngine service.
+// All implementations must embed UnimplementedMatchingEngineServer
+// for forward compatibility
+type MatchingEngineServer interface {
+	// SubmitOrder sends a new order to its market and returns the events generated by the engine
+	SubmitOrder(context.Context, *Order) (*Events, error)
+	// CancelOrder sends a cancel request to its market and returns the events generated by the engine
+	CancelOrder(context.Context, *Order) (*Events, error)
+	// Subscribe streams the events generated by a market
+	Subscribe(*SubscribeRequest, MatchingEngine_SubscribeServer) error
+	mustEmbedUnimplementedMatchingEngineServer()
+}
+
+// UnimplementedMatchingEngineServer must be embedded to have forward compatible implementations.
+type UnimplementedMatchingEngineServer struct {
+}
+
+func (UnimplementedMatchingEngineServer) SubmitOrder(context.Context, *Order) (*Events, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitOrder not implemented")
+}
+func (UnimplementedMatchingEngineServer) CancelOrder(context.Context, *Order) (*Events, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedMatchingEngineServer) Subscribe(*SubscribeRequest, MatchingEngine_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedMatchingEngineServer) mustEmbedUnimplementedMatchingEngineServer() {}
+
+// UnsafeMatchingEngineServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MatchingEngineServer will
+// result in compilation errors.
+type UnsafeMatchingEngineServer interface {
+	mustEmbedUnimplementedMatchingEngineServer()
+}
+
+func RegisterMatchingEngineServer(s grpc.ServiceRegistrar, srv MatchingEngineServer) {
+	s.RegisterService(&MatchingEngine_ServiceDesc, srv)
+}
+
+func _MatchingEngine_SubmitOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Order)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchingEngineServer).SubmitOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/model.MatchingEngine/SubmitOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchingEngineServer).SubmitOrder(ctx, req.(*Order))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchingEngine_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Order)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchingEngineServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/model.MatchingEngine/CancelOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchingEngineServer).CancelOrder(ctx, req.(*Order))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchingEngine_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MatchingEngineServer).Subscribe(m, &matchingEngineSubscribeServer{stream})
+}
+
+type MatchingEngine_SubscribeServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type matchingEngineSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *matchingEngineSubscribeServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// MatchingEngine_ServiceDesc is the grpc.ServiceDesc for MatchingEngine service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MatchingEngine_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "model.MatchingEngine",
+	HandlerType: (*MatchingEngineServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitOrder",
+			Handler:    _MatchingEngine_SubmitOrder_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _MatchingEngine_CancelOrder_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _MatchingEngine_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "service.proto",
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	proto "github.com/golang/protobuf/proto"
+)
+
+// FromBinary loads a ticker from a byte array
+func (ticker *Ticker) FromBinary(msg []byte) error {
+	return proto.Unmarshal(msg, ticker)
+}
+
+// ToBinary converts a ticker to a byte string
+func (ticker *Ticker) ToBinary() ([]byte, error) {
+	return proto.Marshal(ticker)
+}
@@ -0,0 +1,281 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        v3.14.0
+// source: ticker.proto
+
+package model
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// Ticker contains the statistics of the trades of a market in the last 24 hours
+type Ticker struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Market string `protobuf:"bytes,1,opt,name=Market,proto3" json:"Market,omitempty"`
+	// The start and the end of the rolling window as unix timestamps in seconds
+	OpenTime  int64  `protobuf:"varint,2,opt,name=OpenTime,proto3" json:"OpenTime,omitempty"`
+	CloseTime int64  `protobuf:"varint,3,opt,name=CloseTime,proto3" json:"CloseTime,omitempty"`
+	Open      uint64 `protobuf:"varint,4,opt,name=Open,proto3" json:"Open,omitempty"`
+	High      uint64 `protobuf:"varint,5,opt,name=High,proto3" json:"High,omitempty"`
+	Low       uint64 `protobuf:"varint,6,opt,name=Low,proto3" json:"Low,omitempty"`
+	Last      uint64 `protobuf:"varint,7,opt,name=Last,proto3" json:"Last,omitempty"`
+	// The traded amount in the base currency
+	Volume uint64 `protobuf:"varint,8,opt,name=Volume,proto3" json:"Volume,omitempty"`
+	// The traded amount in the quote currency
+	QuoteVolume uint64 `protobuf:"varint,9,opt,name=QuoteVolume,proto3" json:"QuoteVolume,omitempty"`
+	// The volume weighted average price
+	VWAP       uint64 `protobuf:"varint,10,opt,name=VWAP,proto3" json:"VWAP,omitempty"`
+	TradeCount uint64 `protobuf:"varint,11,opt,name=TradeCount,proto3" json:"TradeCount,omitempty"`
+	// The best bid and the best ask of the order book when the ticker was generated
+	BestBid uint64 `protobuf:"varint,12,opt,name=BestBid,proto3" json:"BestBid,omitempty"`
+	BestAsk uint64 `protobuf:"varint,13,opt,name=BestAsk,proto3" json:"BestAsk,omitempty"`
+	// The sequence id of the last trade included in the ticker
+	LastTradeSeqID uint64 `protobuf:"varint,14,opt,name=LastTradeSeqID,proto3" json:"LastTradeSeqID,omitempty"`
+}
+
+func (x *Ticker) Reset() {
+	*x = Ticker{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_ticker_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Ticker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ticker) ProtoMessage() {}
+
+func (x *Ticker) ProtoReflect() protoreflect.Message {
+	mi := &file_ticker_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ticker.ProtoReflect.Descriptor instead.
+func (*Ticker) Descriptor() ([]byte, []int) {
+	return file_ticker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Ticker) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *Ticker) GetOpenTime() int64 {
+	if x != nil {
+		return x.OpenTime
+	}
+	return 0
+}
+
+func (x *Ticker) GetCloseTime() int64 {
+	if x != nil {
+		return x.CloseTime
+	}
+	return 0
+}
+
+func (x *Ticker) GetOpen() uint64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Ticker) GetHigh() uint64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Ticker) GetLow() uint64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Ticker) GetLast() uint64 {
+	if x != nil {
+		return x.Last
+	}
+	return 0
+}
+
+func (x *Ticker) GetVolume() uint64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *Ticker) GetQuoteVolume() uint64 {
+	if x != nil {
+		return x.QuoteVolume
+	}
+	return 0
+}
+
+func (x *Ticker) GetVWAP() uint64 {
+	if x != nil {
+		return x.VWAP
+	}
+	return 0
+}
+
+func (x *Ticker) GetTradeCount() uint64 {
+	if x != nil {
+		return x.TradeCount
+	}
+	return 0
+}
+
+func (x *Ticker) GetBestBid() uint64 {
+	if x != nil {
+		return x.BestBid
+	}
+	return 0
+}
+
+func (x *Ticker) GetBestAsk() uint64 {
+	if x != nil {
+		return x.BestAsk
+	}
+	return 0
+}
+
+func (x *Ticker) GetLastTradeSeqID() uint64 {
+	if x != nil {
+		return x.LastTradeSeqID
+	}
+	return 0
+}
+
+var File_ticker_proto protoreflect.FileDescriptor
+
+var file_ticker_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x22, 0xf2, 0x02, 0x0a, 0x06, 0x54, 0x69, 0x63, 0x6b, 0x65, 0x72,
+	0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x6e,
+	0x54, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x4f, 0x70, 0x65, 0x6e,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x54, 0x69, 0x6d,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4f, 0x70, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x04, 0x4f, 0x70, 0x65, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x48, 0x69, 0x67, 0x68, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x48, 0x69, 0x67, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x4c, 0x6f,
+	0x77, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x4c, 0x6f, 0x77, 0x12, 0x12, 0x0a, 0x04,
+	0x4c, 0x61, 0x73, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x4c, 0x61, 0x73, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x51, 0x75, 0x6f, 0x74,
+	0x65, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x51,
+	0x75, 0x6f, 0x74, 0x65, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x56, 0x57,
+	0x41, 0x50, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x56, 0x57, 0x41, 0x50, 0x12, 0x1e,
+	0x0a, 0x0a, 0x54, 0x72, 0x61, 0x64, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0a, 0x54, 0x72, 0x61, 0x64, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x42, 0x65, 0x73, 0x74, 0x42, 0x69, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x07, 0x42, 0x65, 0x73, 0x74, 0x42, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x42, 0x65, 0x73, 0x74,
+	0x41, 0x73, 0x6b, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x42, 0x65, 0x73, 0x74, 0x41,
+	0x73, 0x6b, 0x12, 0x26, 0x0a, 0x0e, 0x4c, 0x61, 0x73, 0x74, 0x54, 0x72, 0x61, 0x64, 0x65, 0x53,
+	0x65, 0x71, 0x49, 0x44, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x4c, 0x61, 0x73, 0x74,
+	0x54, 0x72, 0x61, 0x64, 0x65, 0x53, 0x65, 0x71, 0x49, 0x44, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69,
+	0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x32,
+	0x35, 0x2f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x69, 0x6e, 0x67, 0x2d, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_ticker_proto_rawDescOnce sync.Once
+	file_ticker_proto_rawDescData = file_ticker_proto_rawDesc
+)
+
+func file_ticker_proto_rawDescGZIP() []byte {
+	file_ticker_proto_rawDescOnce.Do(func() {
+		file_ticker_proto_rawDescData = protoimpl.X.CompressGZIP(file_ticker_proto_rawDescData)
+	})
+	return file_ticker_proto_rawDescData
+}
+
+var file_ticker_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_ticker_proto_goTypes = []interface{}{
+	(*Ticker)(nil), // 0: model.Ticker
+}
+var file_ticker_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_ticker_proto_init() }
+func file_ticker_proto_init() {
+	if File_ticker_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_ticker_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ticker); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_ticker_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_ticker_proto_goTypes,
+		DependencyIndexes: file_ticker_proto_depIdxs,
+		MessageInfos:      file_ticker_proto_msgTypes,
+	}.Build()
+	File_ticker_proto = out.File
+	file_ticker_proto_rawDesc = nil
+	file_ticker_proto_goTypes = nil
+	file_ticker_proto_depIdxs = nil
+}
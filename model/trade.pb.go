@@ -38,6 +38,14 @@ type Trade struct {
 	BidOwnerID uint64     `protobuf:"varint,6,opt,name=BidOwnerID,proto3" json:"BidOwnerID,omitempty"`
 	TakerSide  MarketSide `protobuf:"varint,7,opt,name=TakerSide,proto3,enum=model.MarketSide" json:"TakerSide,omitempty"`
 	SeqID      uint64     `protobuf:"varint,8,opt,name=SeqID,proto3" json:"SeqID,omitempty"`
+	// The ids defined by the users when the ask and bid orders were added
+	AskClientOrderID string `protobuf:"bytes,9,opt,name=AskClientOrderID,proto3" json:"AskClientOrderID,omitempty"`
+	BidClientOrderID string `protobuf:"bytes,10,opt,name=BidClientOrderID,proto3" json:"BidClientOrderID,omitempty"`
+	// The amount left to be filled for the ask and bid orders after the trade
+	AskRemaining uint64 `protobuf:"varint,11,opt,name=AskRemaining,proto3" json:"AskRemaining,omitempty"`
+	BidRemaining uint64 `protobuf:"varint,12,opt,name=BidRemaining,proto3" json:"BidRemaining,omitempty"`
+	// The sequence id of the event with which the maker order was acknowledged
+	MakerPlacedSeqID uint64 `protobuf:"varint,13,opt,name=MakerPlacedSeqID,proto3" json:"MakerPlacedSeqID,omitempty"`
 }
 
 func (x *Trade) Reset() {
@@ -128,12 +136,47 @@ func (x *Trade) GetSeqID() uint64 {
 	return 0
 }
 
+func (x *Trade) GetAskClientOrderID() string {
+	if x != nil {
+		return x.AskClientOrderID
+	}
+	return ""
+}
+
+func (x *Trade) GetBidClientOrderID() string {
+	if x != nil {
+		return x.BidClientOrderID
+	}
+	return ""
+}
+
+func (x *Trade) GetAskRemaining() uint64 {
+	if x != nil {
+		return x.AskRemaining
+	}
+	return 0
+}
+
+func (x *Trade) GetBidRemaining() uint64 {
+	if x != nil {
+		return x.BidRemaining
+	}
+	return 0
+}
+
+func (x *Trade) GetMakerPlacedSeqID() uint64 {
+	if x != nil {
+		return x.MakerPlacedSeqID
+	}
+	return 0
+}
+
 var File_trade_proto protoreflect.FileDescriptor
 
 var file_trade_proto_rawDesc = []byte{
 	0x0a, 0x0b, 0x74, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6d,
 	0x6f, 0x64, 0x65, 0x6c, 0x1a, 0x0b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x22, 0xe8, 0x01, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x50,
+	0x6f, 0x22, 0xb4, 0x03, 0x0a, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x50,
 	0x72, 0x69, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x50, 0x72, 0x69, 0x63,
 	0x65, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
 	0x04, 0x52, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x41, 0x73, 0x6b,
@@ -147,11 +190,24 @@ var file_trade_proto_rawDesc = []byte{
 	0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
 	0x2e, 0x4d, 0x61, 0x72, 0x6b, 0x65, 0x74, 0x53, 0x69, 0x64, 0x65, 0x52, 0x09, 0x54, 0x61, 0x6b,
 	0x65, 0x72, 0x53, 0x69, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x18,
-	0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x42, 0x34, 0x5a, 0x32,
-	0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x6f, 0x75, 0x6e,
-	0x64, 0x32, 0x35, 0x2f, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x61, 0x74,
-	0x63, 0x68, 0x69, 0x6e, 0x67, 0x2d, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x2f, 0x6d, 0x6f, 0x64,
-	0x65, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x53, 0x65, 0x71, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x10,
+	0x41, 0x73, 0x6b, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x41, 0x73, 0x6b, 0x43, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x12, 0x2a, 0x0a, 0x10, 0x42, 0x69, 0x64, 0x43,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x49, 0x44, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x10, 0x42, 0x69, 0x64, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x49, 0x44, 0x12, 0x22, 0x0a, 0x0c, 0x41, 0x73, 0x6b, 0x52, 0x65, 0x6d, 0x61, 0x69,
+	0x6e, 0x69, 0x6e, 0x67, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x41, 0x73, 0x6b, 0x52,
+	0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x22, 0x0a, 0x0c, 0x42, 0x69, 0x64, 0x52,
+	0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
+	0x42, 0x69, 0x64, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x2a, 0x0a, 0x10,
+	0x4d, 0x61, 0x6b, 0x65, 0x72, 0x50, 0x6c, 0x61, 0x63, 0x65, 0x64, 0x53, 0x65, 0x71, 0x49, 0x44,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x4d, 0x61, 0x6b, 0x65, 0x72, 0x50, 0x6c, 0x61,
+	0x63, 0x65, 0x64, 0x53, 0x65, 0x71, 0x49, 0x44, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x6c,
+	0x61, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x32, 0x35, 0x2f,
+	0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e,
+	0x67, 0x2d, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -0,0 +1,330 @@
+package net
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// journalRecordHeader is the size of the length and the checksum written before each record
+const journalRecordHeader = 8
+
+// ErrCorruptedJournal is returned when a record of the journal does not match its checksum
+var ErrCorruptedJournal = errors.New("corrupted journal record")
+
+// FileJournal is an append-only file of messages shared by the producers and consumers created for it
+//
+// Each record is written as the length and the CRC32 checksum of its content followed by the offset, the time,
+// the key, the headers and the value of the message. A record that was not completely written when the process
+// stopped is removed when the journal is opened again. The offset of the last committed message is kept in a file
+// with the same name and the .commit extension.
+type FileJournal struct {
+	name      string
+	path      string
+	sync      bool
+	lock      sync.Mutex
+	file      *os.File
+	positions []int64
+	size      int64
+	committed int64
+	notify    chan struct{}
+}
+
+// OpenFileJournal opens or creates the journal at the given path for the named topic
+// - If sync is set every write is flushed to the disk before returning
+func OpenFileJournal(path, name string, sync bool) (*FileJournal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	journal := &FileJournal{
+		name:      name,
+		path:      path,
+		sync:      sync,
+		file:      file,
+		positions: make([]int64, 0),
+		committed: -1,
+		notify:    make(chan struct{}),
+	}
+	if err := journal.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return journal, nil
+}
+
+// NewFileJournalProducer creates a producer that appends messages to the journal
+func NewFileJournalProducer(journal *FileJournal) Producer {
+	return &logProducer{log: journal}
+}
+
+// NewFileJournalConsumer creates a consumer that reads the messages of the journal
+func NewFileJournalConsumer(journal *FileJournal, channelSize int) Consumer {
+	return newLogConsumer(journal, channelSize)
+}
+
+// load indexes the records of the file and removes the last record if it is incomplete
+func (journal *FileJournal) load() error {
+	reader := bufio.NewReader(journal.file)
+	position := int64(0)
+	for {
+		msg, size, err := readJournalRecord(reader)
+		if err == io.EOF || err == io.ErrUnexpectedEOF || err == ErrCorruptedJournal {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Offset != int64(len(journal.positions)) {
+			break
+		}
+		journal.positions = append(journal.positions, position)
+		position += size
+	}
+	if err := journal.file.Truncate(position); err != nil {
+		return err
+	}
+	journal.size = position
+
+	raw, err := ioutil.ReadFile(journal.path + ".commit")
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		committed, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64)
+		if err != nil {
+			return err
+		}
+		journal.committed = committed
+	}
+	return nil
+}
+
+// Committed returns the offset of the last committed message or -1 if no message was committed
+func (journal *FileJournal) Committed() int64 {
+	journal.lock.Lock()
+	defer journal.lock.Unlock()
+	return journal.committed
+}
+
+// Messages returns the messages of the journal from the given offset to its end
+func (journal *FileJournal) Messages(offset int64) ([]Message, error) {
+	end := journal.end()
+	if offset >= end {
+		return nil, nil
+	}
+	msgs, _, err := journal.read(offset, int(end-offset))
+	return msgs, err
+}
+
+// Close the journal file
+func (journal *FileJournal) Close() error {
+	journal.lock.Lock()
+	defer journal.lock.Unlock()
+	return journal.file.Close()
+}
+
+func (journal *FileJournal) append(msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	journal.lock.Lock()
+	defer journal.lock.Unlock()
+	now := time.Now()
+	buffer := make([]byte, 0)
+	positions := make([]int64, 0, len(msgs))
+	for _, msg := range msgs {
+		msg.Offset = int64(len(journal.positions) + len(positions))
+		if msg.Time.IsZero() {
+			msg.Time = now
+		}
+		positions = append(positions, journal.size+int64(len(buffer)))
+		buffer = appendJournalRecord(buffer, msg)
+	}
+	if _, err := journal.file.WriteAt(buffer, journal.size); err != nil {
+		// remove the partially written records
+		journal.file.Truncate(journal.size)
+		return err
+	}
+	if journal.sync {
+		if err := journal.file.Sync(); err != nil {
+			return err
+		}
+	}
+	journal.positions = append(journal.positions, positions...)
+	journal.size += int64(len(buffer))
+	close(journal.notify)
+	journal.notify = make(chan struct{})
+	return nil
+}
+
+func (journal *FileJournal) read(offset int64, max int) ([]Message, <-chan struct{}, error) {
+	journal.lock.Lock()
+	notify := journal.notify
+	if offset >= int64(len(journal.positions)) {
+		journal.lock.Unlock()
+		return nil, notify, nil
+	}
+	last := offset + int64(max)
+	end := journal.size
+	if last < int64(len(journal.positions)) {
+		end = journal.positions[last]
+	} else {
+		last = int64(len(journal.positions))
+	}
+	start := journal.positions[offset]
+	journal.lock.Unlock()
+
+	reader := bufio.NewReader(io.NewSectionReader(journal.file, start, end-start))
+	msgs := make([]Message, 0, last-offset)
+	for i := offset; i < last; i++ {
+		msg, _, err := readJournalRecord(reader)
+		if err != nil {
+			return nil, notify, err
+		}
+		msg.Topic = journal.name
+		msgs = append(msgs, msg)
+	}
+	return msgs, notify, nil
+}
+
+func (journal *FileJournal) end() int64 {
+	journal.lock.Lock()
+	defer journal.lock.Unlock()
+	return int64(len(journal.positions))
+}
+
+func (journal *FileJournal) commit(offset int64) error {
+	journal.lock.Lock()
+	defer journal.lock.Unlock()
+	if offset <= journal.committed {
+		return nil
+	}
+	file := journal.path + ".commit"
+	if err := ioutil.WriteFile(file+".tmp", []byte(strconv.FormatInt(offset, 10)), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(file+".tmp", file); err != nil {
+		return err
+	}
+	journal.committed = offset
+	return nil
+}
+
+// appendJournalRecord encodes a message as a journal record
+func appendJournalRecord(buffer []byte, msg Message) []byte {
+	start := len(buffer)
+	buffer = append(buffer, make([]byte, journalRecordHeader)...)
+	buffer = appendVarint(buffer, msg.Offset)
+	buffer = appendVarint(buffer, msg.Time.UnixNano())
+	buffer = appendBytes(buffer, msg.Key)
+	buffer = appendUvarint(buffer, uint64(len(msg.Headers)))
+	for _, header := range msg.Headers {
+		buffer = appendBytes(buffer, []byte(header.Key))
+		buffer = appendBytes(buffer, header.Value)
+	}
+	buffer = appendBytes(buffer, msg.Value)
+	content := buffer[start+journalRecordHeader:]
+	binary.BigEndian.PutUint32(buffer[start:], uint32(len(content)))
+	binary.BigEndian.PutUint32(buffer[start+4:], crc32.ChecksumIEEE(content))
+	return buffer
+}
+
+// readJournalRecord decodes the next record and returns the message and the size of the record
+func readJournalRecord(reader *bufio.Reader) (Message, int64, error) {
+	var msg Message
+	header := make([]byte, journalRecordHeader)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return msg, 0, err
+	}
+	content := make([]byte, binary.BigEndian.Uint32(header))
+	if _, err := io.ReadFull(reader, content); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return msg, 0, err
+	}
+	if crc32.ChecksumIEEE(content) != binary.BigEndian.Uint32(header[4:]) {
+		return msg, 0, ErrCorruptedJournal
+	}
+	decoder := journalDecoder{content: content}
+	msg.Offset = decoder.varint()
+	msg.Time = time.Unix(0, decoder.varint())
+	msg.Key = decoder.bytes()
+	count := decoder.uvarint()
+	if count > 0 {
+		msg.Headers = make([]Header, 0, count)
+	}
+	for i := uint64(0); i < count && decoder.err == nil; i++ {
+		key := decoder.bytes()
+		msg.Headers = append(msg.Headers, Header{Key: string(key), Value: decoder.bytes()})
+	}
+	msg.Value = decoder.bytes()
+	if decoder.err != nil {
+		return msg, 0, ErrCorruptedJournal
+	}
+	return msg, int64(journalRecordHeader + len(content)), nil
+}
+
+func appendVarint(buffer []byte, value int64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buffer, tmp[:binary.PutVarint(tmp[:], value)]...)
+}
+
+func appendUvarint(buffer []byte, value uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buffer, tmp[:binary.PutUvarint(tmp[:], value)]...)
+}
+
+func appendBytes(buffer []byte, value []byte) []byte {
+	buffer = appendUvarint(buffer, uint64(len(value)))
+	return append(buffer, value...)
+}
+
+// journalDecoder reads the fields of a record and keeps the first error
+type journalDecoder struct {
+	content []byte
+	err     error
+}
+
+func (decoder *journalDecoder) varint() int64 {
+	value, n := binary.Varint(decoder.content)
+	if n <= 0 {
+		decoder.err = ErrCorruptedJournal
+		return 0
+	}
+	decoder.content = decoder.content[n:]
+	return value
+}
+
+func (decoder *journalDecoder) uvarint() uint64 {
+	value, n := binary.Uvarint(decoder.content)
+	if n <= 0 {
+		decoder.err = ErrCorruptedJournal
+		return 0
+	}
+	decoder.content = decoder.content[n:]
+	return value
+}
+
+func (decoder *journalDecoder) bytes() []byte {
+	size := decoder.uvarint()
+	if decoder.err != nil || size > uint64(len(decoder.content)) {
+		decoder.err = ErrCorruptedJournal
+		return nil
+	}
+	if size == 0 {
+		return nil
+	}
+	value := decoder.content[:size]
+	decoder.content = decoder.content[size:]
+	return value
+}
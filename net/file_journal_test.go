@@ -0,0 +1,110 @@
+package net_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFileJournal(t *testing.T) {
+	Convey("Given a journal with a few messages", t, func() {
+		ctx := context.Background()
+		dir, err := ioutil.TempDir("", "journal")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "orders.journal")
+		journal, err := net.OpenFileJournal(path, "orders", true)
+		So(err, ShouldBeNil)
+		producer := net.NewFileJournalProducer(journal)
+		err = producer.WriteMessages(ctx,
+			net.Message{Key: []byte("k"), Value: []byte("first"), Headers: []net.Header{{Key: "request_id", Value: []byte("abc")}}},
+			net.Message{Value: []byte("second")},
+		)
+		So(err, ShouldBeNil)
+
+		Convey("A consumer should read the messages with their details", func() {
+			consumer := net.NewFileJournalConsumer(journal, 10)
+			defer consumer.Close()
+			consumer.Start(ctx)
+			msgs := readMessages(consumer, 2)
+			So(msgs, ShouldHaveLength, 2)
+			So(msgs[0].Offset, ShouldEqual, 0)
+			So(msgs[0].Topic, ShouldEqual, "orders")
+			So(string(msgs[0].Key), ShouldEqual, "k")
+			So(string(msgs[0].Value), ShouldEqual, "first")
+			So(msgs[0].Headers, ShouldHaveLength, 1)
+			So(msgs[0].Headers[0].Key, ShouldEqual, "request_id")
+			So(string(msgs[0].Headers[0].Value), ShouldEqual, "abc")
+			So(msgs[1].Offset, ShouldEqual, 1)
+			So(string(msgs[1].Value), ShouldEqual, "second")
+
+			Convey("And then the messages written after it reached the end of the journal", func() {
+				producer.WriteMessages(ctx, net.Message{Value: []byte("third")})
+				msgs := readMessages(consumer, 1)
+				So(msgs, ShouldHaveLength, 1)
+				So(msgs[0].Offset, ShouldEqual, 2)
+				So(string(msgs[0].Value), ShouldEqual, "third")
+			})
+		})
+
+		Convey("Reopening the journal should keep the messages and the committed offset", func() {
+			consumer := net.NewFileJournalConsumer(journal, 10)
+			So(consumer.CommitMessages(ctx, net.Message{Offset: 1}), ShouldBeNil)
+			So(journal.Close(), ShouldBeNil)
+
+			journal, err := net.OpenFileJournal(path, "orders", false)
+			So(err, ShouldBeNil)
+			defer journal.Close()
+			So(journal.Committed(), ShouldEqual, 1)
+			net.NewFileJournalProducer(journal).WriteMessages(ctx, net.Message{Value: []byte("third")})
+
+			consumer = net.NewFileJournalConsumer(journal, 10)
+			defer consumer.Close()
+			consumer.SetOffset(1)
+			consumer.Start(ctx)
+			msgs := readMessages(consumer, 2)
+			So(msgs, ShouldHaveLength, 2)
+			So(string(msgs[0].Value), ShouldEqual, "second")
+			So(msgs[1].Offset, ShouldEqual, 2)
+			So(string(msgs[1].Value), ShouldEqual, "third")
+		})
+
+		Convey("The messages from an offset to the end of the journal should be returned", func() {
+			msgs, err := journal.Messages(1)
+			So(err, ShouldBeNil)
+			So(msgs, ShouldHaveLength, 1)
+			So(msgs[0].Offset, ShouldEqual, 1)
+			So(string(msgs[0].Value), ShouldEqual, "second")
+
+			msgs, err = journal.Messages(2)
+			So(err, ShouldBeNil)
+			So(msgs, ShouldBeEmpty)
+		})
+
+		Convey("An incomplete record at the end of the file should be removed when the journal is opened", func() {
+			So(journal.Close(), ShouldBeNil)
+			file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+			So(err, ShouldBeNil)
+			file.Write([]byte{0, 0, 0, 20, 1, 2, 3})
+			file.Close()
+
+			journal, err := net.OpenFileJournal(path, "orders", false)
+			So(err, ShouldBeNil)
+			defer journal.Close()
+			net.NewFileJournalProducer(journal).WriteMessages(ctx, net.Message{Value: []byte("third")})
+			consumer := net.NewFileJournalConsumer(journal, 10)
+			defer consumer.Close()
+			consumer.Start(ctx)
+			msgs := readMessages(consumer, 3)
+			So(msgs, ShouldHaveLength, 3)
+			So(msgs[2].Offset, ShouldEqual, 2)
+			So(string(msgs[2].Value), ShouldEqual, "third")
+		})
+	})
+}
@@ -13,12 +13,12 @@ import (
 type kafkaConsumer struct {
 	brokers  []string
 	topic    string
-	inputs   chan kafka.Message
+	inputs   chan Message
 	consumer *kafka.Reader
 }
 
 // NewKafkaConsumer return a new Kafka consumer
-func NewKafkaConsumer(cfg KafkaReaderConfig, brokers []string, useTLS bool, topic string, partition int) KafkaConsumer {
+func NewKafkaConsumer(cfg KafkaReaderConfig, brokers []string, useTLS bool, topic string, partition int) Consumer {
 	var dialer *kafka.Dialer
 	if useTLS {
 		tlsCfg := &tls.Config{
@@ -47,7 +47,7 @@ func NewKafkaConsumer(cfg KafkaReaderConfig, brokers []string, useTLS bool, topi
 		brokers:  brokers,
 		topic:    topic,
 		consumer: consumer,
-		inputs:   make(chan kafka.Message, cfg.ChannelSize),
+		inputs:   make(chan Message, cfg.ChannelSize),
 	}
 }
 
@@ -62,13 +62,20 @@ func (conn *kafkaConsumer) Start(ctx context.Context) error {
 }
 
 // GetMessageChan returns the message channel
-func (conn *kafkaConsumer) GetMessageChan() <-chan kafka.Message {
+func (conn *kafkaConsumer) GetMessageChan() <-chan Message {
 	return conn.inputs
 }
 
 // CommitMessages for the given messages
-func (conn *kafkaConsumer) CommitMessages(ctx context.Context, msgs ...kafka.Message) {
-	conn.consumer.CommitMessages(ctx, msgs...)
+func (conn *kafkaConsumer) CommitMessages(ctx context.Context, msgs ...Message) error {
+	kafkaMsgs := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		kafkaMsgs[i] = toKafkaMessage(msg)
+		kafkaMsgs[i].Topic = msg.Topic
+		kafkaMsgs[i].Partition = msg.Partition
+		kafkaMsgs[i].Offset = msg.Offset
+	}
+	return conn.consumer.CommitMessages(ctx, kafkaMsgs...)
 }
 
 // Close the consumer connection
@@ -140,6 +147,26 @@ func (conn *kafkaConsumer) handleMessages(ctx context.Context) error {
 			}
 		}
 		// send the message to the channel for processing
-		conn.inputs <- msg
+		conn.inputs <- fromKafkaMessage(msg)
+	}
+}
+
+// fromKafkaMessage converts a message read from Kafka
+func fromKafkaMessage(msg kafka.Message) Message {
+	var headers []Header
+	if len(msg.Headers) > 0 {
+		headers = make([]Header, len(msg.Headers))
+		for i, header := range msg.Headers {
+			headers[i] = Header{Key: header.Key, Value: header.Value}
+		}
+	}
+	return Message{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Value:     msg.Value,
+		Headers:   headers,
+		Time:      msg.Time,
 	}
 }
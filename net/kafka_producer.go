@@ -16,7 +16,7 @@ type kafkaProducer struct {
 }
 
 // NewKafkaProducer returns a new producer
-func NewKafkaProducer(cfg KafkaWriterConfig, brokers []string, useTLS bool, topic string) KafkaProducer {
+func NewKafkaProducer(cfg KafkaWriterConfig, brokers []string, useTLS bool, topic string) Producer {
 	var dialer *kafka.Dialer
 	if useTLS {
 		tlsCfg := &tls.Config{
@@ -29,9 +29,11 @@ func NewKafkaProducer(cfg KafkaWriterConfig, brokers []string, useTLS bool, topi
 		}
 	}
 	producer := kafka.NewWriter(kafka.WriterConfig{
-		Dialer:           dialer,
-		Brokers:          brokers,
-		Topic:            topic,
+		Dialer:  dialer,
+		Brokers: brokers,
+		Topic:   topic,
+		// messages with a key are always written on the same partition, the others are distributed evenly
+		Balancer:         &kafka.Hash{},
 		QueueCapacity:    cfg.QueueCapacity,
 		BatchSize:        cfg.BatchSize,
 		BatchTimeout:     time.Duration(cfg.BatchTimeout) * time.Millisecond,
@@ -50,8 +52,12 @@ func (conn *kafkaProducer) Start() error {
 }
 
 // Write one or multiple messages to the topic partition
-func (conn *kafkaProducer) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
-	return conn.producer.WriteMessages(ctx, msgs...)
+func (conn *kafkaProducer) WriteMessages(ctx context.Context, msgs ...Message) error {
+	kafkaMsgs := make([]kafka.Message, len(msgs))
+	for i, msg := range msgs {
+		kafkaMsgs[i] = toKafkaMessage(msg)
+	}
+	return conn.producer.WriteMessages(ctx, kafkaMsgs...)
 }
 
 // Get statistics about the producer since the last time it was executed
@@ -63,3 +69,20 @@ func (conn *kafkaProducer) Stats() kafka.WriterStats {
 func (conn *kafkaProducer) Close() error {
 	return conn.producer.Close()
 }
+
+// toKafkaMessage converts a message written to Kafka
+func toKafkaMessage(msg Message) kafka.Message {
+	var headers []kafka.Header
+	if len(msg.Headers) > 0 {
+		headers = make([]kafka.Header, len(msg.Headers))
+		for i, header := range msg.Headers {
+			headers[i] = kafka.Header{Key: header.Key, Value: header.Value}
+		}
+	}
+	return kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+		Time:    msg.Time,
+	}
+}
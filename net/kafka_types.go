@@ -1,11 +1,5 @@
 package net
 
-import (
-	"context"
-
-	"github.com/segmentio/kafka-go"
-)
-
 // KafkaConfig godoc
 type KafkaConfig struct {
 	UseTLS bool              `mapstructure:"use_tls"`
@@ -64,19 +58,3 @@ type KafkaWriterConfig struct {
 	// whether the messages were written to kafka.
 	Async bool `mapstructure:"async"`
 }
-
-// KafkaProducer inferface
-type KafkaProducer interface {
-	Start() error
-	WriteMessages(context.Context, ...kafka.Message) error
-	Close() error
-}
-
-// KafkaConsumer interface
-type KafkaConsumer interface {
-	Start(ctx context.Context) error
-	SetOffset(offset int64) error
-	GetMessageChan() <-chan kafka.Message
-	CommitMessages(context.Context, ...kafka.Message)
-	Close() error
-}
@@ -0,0 +1,138 @@
+package net
+
+import (
+	"context"
+	"sync"
+)
+
+// logReadBatch is the maximum number of messages read from a log at once
+const logReadBatch = 1000
+
+// messageLog is an append-only list of messages identified by consecutive offsets starting from zero
+type messageLog interface {
+	// append stores the messages and sets their offsets
+	append(msgs []Message) error
+	// read returns up to max messages starting from the given offset and a channel that is closed when new
+	// messages are appended after the returned ones
+	read(offset int64, max int) ([]Message, <-chan struct{}, error)
+	// end returns the offset of the next message appended to the log
+	end() int64
+	// commit marks the messages up to the given offset as processed
+	commit(offset int64) error
+}
+
+// logProducer writes messages to a message log
+type logProducer struct {
+	log messageLog
+}
+
+// Start the producer
+func (producer *logProducer) Start() error {
+	return nil
+}
+
+// WriteMessages appends the messages to the log
+func (producer *logProducer) WriteMessages(ctx context.Context, msgs ...Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return producer.log.append(msgs)
+}
+
+// Close the producer
+func (producer *logProducer) Close() error {
+	return nil
+}
+
+// logConsumer reads the messages of a message log and waits for new ones once it reaches the end of the log
+type logConsumer struct {
+	log    messageLog
+	offset int64
+	inputs chan Message
+	done   chan struct{}
+	once   sync.Once
+}
+
+// newLogConsumer creates a consumer that starts from the first message of the log
+func newLogConsumer(log messageLog, channelSize int) *logConsumer {
+	return &logConsumer{
+		log:    log,
+		offset: FirstOffset,
+		inputs: make(chan Message, channelSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// SetOffset sets the offset of the next message to read
+func (consumer *logConsumer) SetOffset(offset int64) error {
+	consumer.offset = offset
+	return nil
+}
+
+// Start reading messages in the background until the context is done or the consumer is closed
+func (consumer *logConsumer) Start(ctx context.Context) error {
+	offset := consumer.offset
+	switch offset {
+	case FirstOffset:
+		offset = 0
+	case LastOffset:
+		offset = consumer.log.end()
+	}
+	go consumer.readMessages(ctx, offset)
+	return nil
+}
+
+func (consumer *logConsumer) readMessages(ctx context.Context, offset int64) {
+	defer close(consumer.inputs)
+	for {
+		msgs, notify, err := consumer.log.read(offset, logReadBatch)
+		if err != nil {
+			return
+		}
+		for _, msg := range msgs {
+			select {
+			case consumer.inputs <- msg:
+				offset = msg.Offset + 1
+			case <-ctx.Done():
+				return
+			case <-consumer.done:
+				return
+			}
+		}
+		if len(msgs) > 0 {
+			continue
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return
+		case <-consumer.done:
+			return
+		}
+	}
+}
+
+// GetMessageChan returns the message channel
+func (consumer *logConsumer) GetMessageChan() <-chan Message {
+	return consumer.inputs
+}
+
+// CommitMessages marks the log as processed up to the highest offset of the given messages
+func (consumer *logConsumer) CommitMessages(ctx context.Context, msgs ...Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	offset := msgs[0].Offset
+	for _, msg := range msgs {
+		if msg.Offset > offset {
+			offset = msg.Offset
+		}
+	}
+	return consumer.log.commit(offset)
+}
+
+// Close stops reading messages
+func (consumer *logConsumer) Close() error {
+	consumer.once.Do(func() { close(consumer.done) })
+	return nil
+}
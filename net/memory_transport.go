@@ -0,0 +1,103 @@
+package net
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryTopic is an in-memory stream of messages shared by the producers and consumers created for it
+// - Used to run the server without a message broker in tests
+type MemoryTopic struct {
+	name      string
+	lock      sync.Mutex
+	messages  []Message
+	committed int64
+	notify    chan struct{}
+}
+
+// NewMemoryTopic creates an empty topic
+func NewMemoryTopic(name string) *MemoryTopic {
+	return &MemoryTopic{
+		name:      name,
+		messages:  make([]Message, 0),
+		committed: -1,
+		notify:    make(chan struct{}),
+	}
+}
+
+// NewMemoryProducer creates a producer that appends messages to the topic
+func NewMemoryProducer(topic *MemoryTopic) Producer {
+	return &logProducer{log: topic}
+}
+
+// NewMemoryConsumer creates a consumer that reads the messages of the topic
+func NewMemoryConsumer(topic *MemoryTopic, channelSize int) Consumer {
+	return newLogConsumer(topic, channelSize)
+}
+
+// Messages returns all the messages written to the topic
+func (topic *MemoryTopic) Messages() []Message {
+	topic.lock.Lock()
+	defer topic.lock.Unlock()
+	msgs := make([]Message, len(topic.messages))
+	copy(msgs, topic.messages)
+	return msgs
+}
+
+// Committed returns the offset of the last committed message or -1 if no message was committed
+func (topic *MemoryTopic) Committed() int64 {
+	topic.lock.Lock()
+	defer topic.lock.Unlock()
+	return topic.committed
+}
+
+func (topic *MemoryTopic) append(msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	topic.lock.Lock()
+	defer topic.lock.Unlock()
+	now := time.Now()
+	for _, msg := range msgs {
+		msg.Topic = topic.name
+		msg.Partition = 0
+		msg.Offset = int64(len(topic.messages))
+		if msg.Time.IsZero() {
+			msg.Time = now
+		}
+		topic.messages = append(topic.messages, msg)
+	}
+	close(topic.notify)
+	topic.notify = make(chan struct{})
+	return nil
+}
+
+func (topic *MemoryTopic) read(offset int64, max int) ([]Message, <-chan struct{}, error) {
+	topic.lock.Lock()
+	defer topic.lock.Unlock()
+	if offset >= int64(len(topic.messages)) {
+		return nil, topic.notify, nil
+	}
+	last := offset + int64(max)
+	if last > int64(len(topic.messages)) {
+		last = int64(len(topic.messages))
+	}
+	msgs := make([]Message, last-offset)
+	copy(msgs, topic.messages[offset:last])
+	return msgs, topic.notify, nil
+}
+
+func (topic *MemoryTopic) end() int64 {
+	topic.lock.Lock()
+	defer topic.lock.Unlock()
+	return int64(len(topic.messages))
+}
+
+func (topic *MemoryTopic) commit(offset int64) error {
+	topic.lock.Lock()
+	defer topic.lock.Unlock()
+	if offset > topic.committed {
+		topic.committed = offset
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package net_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// readMessages reads the given number of messages from the consumer or fails after a timeout
+func readMessages(consumer net.Consumer, count int) []net.Message {
+	msgs := make([]net.Message, 0, count)
+	timeout := time.After(time.Second)
+	for len(msgs) < count {
+		select {
+		case msg, ok := <-consumer.GetMessageChan():
+			if !ok {
+				return msgs
+			}
+			msgs = append(msgs, msg)
+		case <-timeout:
+			return msgs
+		}
+	}
+	return msgs
+}
+
+func TestMemoryTransport(t *testing.T) {
+	Convey("Given a memory topic with a few messages", t, func() {
+		ctx := context.Background()
+		topic := net.NewMemoryTopic("engine.orders.btcusd")
+		producer := net.NewMemoryProducer(topic)
+		So(producer.Start(), ShouldBeNil)
+		err := producer.WriteMessages(ctx,
+			net.Message{Value: []byte("a"), Headers: []net.Header{{Key: "request_id", Value: []byte("1")}}},
+			net.Message{Value: []byte("b")},
+			net.Message{Value: []byte("c")},
+		)
+		So(err, ShouldBeNil)
+
+		Convey("The messages should get consecutive offsets", func() {
+			msgs := topic.Messages()
+			So(msgs, ShouldHaveLength, 3)
+			So(msgs[0].Offset, ShouldEqual, 0)
+			So(msgs[2].Offset, ShouldEqual, 2)
+			So(msgs[0].Topic, ShouldEqual, "engine.orders.btcusd")
+			So(msgs[0].Time.IsZero(), ShouldBeFalse)
+		})
+
+		Convey("A consumer should read all messages from the first offset and then the new ones", func() {
+			consumer := net.NewMemoryConsumer(topic, 10)
+			defer consumer.Close()
+			So(consumer.Start(ctx), ShouldBeNil)
+			msgs := readMessages(consumer, 3)
+			So(msgs, ShouldHaveLength, 3)
+			So(string(msgs[0].Value), ShouldEqual, "a")
+			So(string(msgs[0].Headers[0].Value), ShouldEqual, "1")
+
+			producer.WriteMessages(ctx, net.Message{Value: []byte("d")})
+			msgs = readMessages(consumer, 1)
+			So(msgs, ShouldHaveLength, 1)
+			So(string(msgs[0].Value), ShouldEqual, "d")
+			So(msgs[0].Offset, ShouldEqual, 3)
+		})
+
+		Convey("A consumer should resume from the given offset", func() {
+			consumer := net.NewMemoryConsumer(topic, 10)
+			defer consumer.Close()
+			consumer.SetOffset(2)
+			consumer.Start(ctx)
+			msgs := readMessages(consumer, 1)
+			So(msgs, ShouldHaveLength, 1)
+			So(string(msgs[0].Value), ShouldEqual, "c")
+		})
+
+		Convey("A consumer started from the last offset should only read new messages", func() {
+			consumer := net.NewMemoryConsumer(topic, 10)
+			defer consumer.Close()
+			consumer.SetOffset(net.LastOffset)
+			consumer.Start(ctx)
+			producer.WriteMessages(ctx, net.Message{Value: []byte("d")})
+			msgs := readMessages(consumer, 1)
+			So(msgs, ShouldHaveLength, 1)
+			So(string(msgs[0].Value), ShouldEqual, "d")
+		})
+
+		Convey("Committing messages should keep the highest offset", func() {
+			consumer := net.NewMemoryConsumer(topic, 10)
+			So(topic.Committed(), ShouldEqual, -1)
+			msgs := topic.Messages()
+			So(consumer.CommitMessages(ctx, msgs[1], msgs[0]), ShouldBeNil)
+			So(topic.Committed(), ShouldEqual, 1)
+			consumer.CommitMessages(ctx, msgs[0])
+			So(topic.Committed(), ShouldEqual, 1)
+		})
+
+		Convey("Closing a consumer should close its message channel", func() {
+			consumer := net.NewMemoryConsumer(topic, 0)
+			consumer.Start(ctx)
+			consumer.Close()
+			closed := false
+			for i := 0; i < 4 && !closed; i++ {
+				select {
+				case _, ok := <-consumer.GetMessageChan():
+					closed = !ok
+				case <-time.After(time.Second):
+					i = 4
+				}
+			}
+			So(closed, ShouldBeTrue)
+		})
+	})
+}
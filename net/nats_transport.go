@@ -0,0 +1,317 @@
+package net
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// NATSKeyHeader is the header in which the key of a message is sent over NATS
+const NATSKeyHeader = "Engine-Key"
+
+// DefaultNATSTimeout is the default number of milliseconds to wait for the acknowledgement of a published message
+const DefaultNATSTimeout = 5000
+
+// NATSConfig godoc
+type NATSConfig struct {
+	// Timeout is the number of milliseconds to wait for the acknowledgement of a published message
+	Timeout int `mapstructure:"timeout"` // default 5000 ms
+	// Replicas is the number of replicas of the streams created by the engine
+	Replicas int `mapstructure:"replicas"` // default 1
+	// ChannelSize sets the size of the channel used to read messages from the stream
+	ChannelSize int `mapstructure:"channel_size"` // 20000
+}
+
+// NATSStreamName returns the name of the JetStream stream that stores the messages of a topic
+// - The topic is used as the subject of the messages
+func NATSStreamName(topic string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_").Replace(topic))
+}
+
+// connectNATS connects to the servers and creates the stream of the topic if it does not exist
+func connectNATS(cfg NATSConfig, servers []string, topic string) (*nats.Conn, nats.JetStreamContext, error) {
+	conn, err := nats.Connect(strings.Join(servers, ","), nats.Name("matching-engine"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, nil, err
+	}
+	js, err := conn.JetStream(nats.MaxWait(natsTimeout(cfg)))
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	stream := NATSStreamName(topic)
+	if _, err := js.StreamInfo(stream); err != nil {
+		replicas := cfg.Replicas
+		if replicas <= 0 {
+			replicas = 1
+		}
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:     stream,
+			Subjects: []string{topic},
+			Storage:  nats.FileStorage,
+			Replicas: replicas,
+		})
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+	}
+	return conn, js, nil
+}
+
+func natsTimeout(cfg NATSConfig) time.Duration {
+	if cfg.Timeout <= 0 {
+		return DefaultNATSTimeout * time.Millisecond
+	}
+	return time.Duration(cfg.Timeout) * time.Millisecond
+}
+
+// natsProducer publishes messages on the stream of a topic
+type natsProducer struct {
+	cfg     NATSConfig
+	servers []string
+	topic   string
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+}
+
+// NewNATSProducer returns a new JetStream producer that connects to the servers when started
+func NewNATSProducer(cfg NATSConfig, servers []string, topic string) Producer {
+	return &natsProducer{cfg: cfg, servers: servers, topic: topic}
+}
+
+// Start connects to the servers and creates the stream of the topic if needed
+func (conn *natsProducer) Start() error {
+	nc, js, err := connectNATS(conn.cfg, conn.servers, conn.topic)
+	if err != nil {
+		return err
+	}
+	conn.conn = nc
+	conn.js = js
+	return nil
+}
+
+// WriteMessages publishes the messages in order and waits until all of them are stored by the stream
+func (conn *natsProducer) WriteMessages(ctx context.Context, msgs ...Message) error {
+	futures := make([]nats.PubAckFuture, 0, len(msgs))
+	for _, msg := range msgs {
+		future, err := conn.js.PublishMsgAsync(toNATSMessage(conn.topic, msg))
+		if err != nil {
+			return err
+		}
+		futures = append(futures, future)
+	}
+	timeout := time.NewTimer(natsTimeout(conn.cfg))
+	defer timeout.Stop()
+	for _, future := range futures {
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout.C:
+			return nats.ErrTimeout
+		}
+	}
+	return nil
+}
+
+// Close the connection to the servers
+func (conn *natsProducer) Close() error {
+	if conn.conn != nil {
+		conn.conn.Close()
+	}
+	return nil
+}
+
+// natsConsumer reads the messages of the stream of a topic in order
+//
+// The offset of a message is its sequence in the stream, starting from 1. The consumer is an ephemeral consumer that
+// does not acknowledge messages since the offset of the last processed message is kept in the market backup.
+// Messages removed from the stream before they were read leave gaps in the offsets that are skipped.
+type natsConsumer struct {
+	cfg     NATSConfig
+	servers []string
+	topic   string
+	offset  int64
+	inputs  chan Message
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewNATSConsumer returns a new JetStream consumer that starts from the first message of the stream by default
+func NewNATSConsumer(cfg NATSConfig, servers []string, topic string) Consumer {
+	return &natsConsumer{
+		cfg:     cfg,
+		servers: servers,
+		topic:   topic,
+		offset:  FirstOffset,
+		inputs:  make(chan Message, cfg.ChannelSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// SetOffset sets the sequence of the next message to read from the stream
+func (conn *natsConsumer) SetOffset(offset int64) error {
+	conn.offset = offset
+	return nil
+}
+
+// Start connects to the servers and reads the messages in the background
+func (conn *natsConsumer) Start(ctx context.Context) error {
+	nc, js, err := connectNATS(conn.cfg, conn.servers, conn.topic)
+	if err != nil {
+		return err
+	}
+	conn.conn = nc
+	conn.js = js
+	go conn.handleMessages(ctx)
+	return nil
+}
+
+// subscribe creates an ordered subscription that starts from the given sequence
+func (conn *natsConsumer) subscribe(next uint64) (*nats.Subscription, error) {
+	start := nats.DeliverAll()
+	switch {
+	case next > 0:
+		start = nats.StartSequence(next)
+	case conn.offset == LastOffset:
+		start = nats.DeliverNew()
+	}
+	sub, err := conn.js.SubscribeSync(conn.topic, start, nats.AckNone())
+	if err != nil {
+		return nil, err
+	}
+	// keep all delivered messages instead of dropping them when the engine falls behind
+	sub.SetPendingLimits(-1, -1)
+	return sub, nil
+}
+
+func (conn *natsConsumer) handleMessages(ctx context.Context) {
+	defer close(conn.inputs)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-conn.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	next := uint64(0)
+	if conn.offset > 0 {
+		next = uint64(conn.offset)
+	}
+	log.Info().Str("section", "nats").Str("topic", conn.topic).Uint64("sequence", next).Msg("Starting message consumer")
+	var sub *nats.Subscription
+	for {
+		if sub == nil {
+			var err error
+			if sub, err = conn.subscribe(next); err != nil {
+				log.Warn().Err(err).Str("section", "nats").Str("topic", conn.topic).Msg("Unable to subscribe to stream. Retrying in 1 second")
+				select {
+				case <-time.After(time.Second):
+					continue
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		msg, err := sub.NextMsgWithContext(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				sub.Unsubscribe()
+				return
+			}
+			log.Warn().Err(err).Str("section", "nats").Str("topic", conn.topic).Uint64("sequence", next).Msg("Unable to read message from stream. Resubscribing")
+			sub.Unsubscribe()
+			sub = nil
+			continue
+		}
+		meta, err := msg.Metadata()
+		if err != nil {
+			continue
+		}
+		sequence := meta.Sequence.Stream
+		if next != 0 && sequence < next {
+			// resume from the expected message so no message is processed twice
+			log.Warn().Str("section", "nats").Str("topic", conn.topic).Uint64("sequence", sequence).Uint64("expected", next).Msg("Unexpected message sequence. Resubscribing")
+			sub.Unsubscribe()
+			sub = nil
+			continue
+		}
+		if next != 0 && sequence > next {
+			// the missing messages were deleted, purged or removed by the retention limits of the stream and
+			// will never be delivered, so the consumer moves on to the next message available
+			log.Warn().Str("section", "nats").Str("topic", conn.topic).Uint64("sequence", sequence).Uint64("expected", next).Msg("Messages missing from the stream. Skipping")
+		}
+		select {
+		case conn.inputs <- fromNATSMessage(msg, int64(sequence), meta.Timestamp):
+			next = sequence + 1
+		case <-ctx.Done():
+			sub.Unsubscribe()
+			return
+		}
+	}
+}
+
+// GetMessageChan returns the message channel
+func (conn *natsConsumer) GetMessageChan() <-chan Message {
+	return conn.inputs
+}
+
+// CommitMessages does nothing since the offset of the last processed message is kept in the market backup
+func (conn *natsConsumer) CommitMessages(ctx context.Context, msgs ...Message) error {
+	return nil
+}
+
+// Close stops reading messages and closes the connection to the servers
+func (conn *natsConsumer) Close() error {
+	conn.once.Do(func() {
+		close(conn.done)
+		if conn.conn != nil {
+			conn.conn.Close()
+		}
+	})
+	return nil
+}
+
+// toNATSMessage converts a message published on the subject of the topic
+func toNATSMessage(topic string, msg Message) *nats.Msg {
+	natsMsg := nats.NewMsg(topic)
+	natsMsg.Data = msg.Value
+	for _, header := range msg.Headers {
+		natsMsg.Header.Add(header.Key, string(header.Value))
+	}
+	if len(msg.Key) > 0 {
+		natsMsg.Header.Set(NATSKeyHeader, string(msg.Key))
+	}
+	return natsMsg
+}
+
+// fromNATSMessage converts a message read from the stream of a topic
+func fromNATSMessage(natsMsg *nats.Msg, offset int64, timestamp time.Time) Message {
+	msg := Message{
+		Topic:  natsMsg.Subject,
+		Offset: offset,
+		Value:  natsMsg.Data,
+		Time:   timestamp,
+	}
+	for key, values := range natsMsg.Header {
+		if key == NATSKeyHeader {
+			msg.Key = []byte(natsMsg.Header.Get(key))
+			continue
+		}
+		for _, value := range values {
+			msg.Headers = append(msg.Headers, Header{Key: key, Value: []byte(value)})
+		}
+	}
+	return msg
+}
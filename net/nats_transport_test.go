@@ -0,0 +1,128 @@
+package net_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// startNATSServer starts an embedded NATS server with JetStream enabled
+func startNATSServer(t *testing.T, dir string) *server.Server {
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: dir, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	return srv
+}
+
+func TestNATSTransport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	srv := startNATSServer(t, dir)
+	defer srv.Shutdown()
+	servers := []string{srv.ClientURL()}
+	cfg := net.NATSConfig{ChannelSize: 10}
+
+	Convey("Given a stream with a few messages published by a producer", t, func() {
+		ctx := context.Background()
+		topic := "engine.orders." + t.Name()
+		producer := net.NewNATSProducer(cfg, servers, topic)
+		So(producer.Start(), ShouldBeNil)
+		defer producer.Close()
+		err := producer.WriteMessages(ctx,
+			net.Message{Key: []byte("btcusd"), Value: []byte("first"), Headers: []net.Header{{Key: "request_id", Value: []byte("abc")}}},
+			net.Message{Value: []byte("second")},
+			net.Message{Value: []byte("third")},
+		)
+		So(err, ShouldBeNil)
+		So(net.NATSStreamName(topic), ShouldEqual, "ENGINE_ORDERS_TESTNATSTRANSPORT")
+
+		Convey("A consumer should read the messages in order with the stream sequence as offset", func() {
+			consumer := net.NewNATSConsumer(cfg, servers, topic)
+			So(consumer.Start(ctx), ShouldBeNil)
+			defer consumer.Close()
+			msgs := readMessages(consumer, 3)
+			So(msgs, ShouldHaveLength, 3)
+			So(msgs[0].Offset, ShouldEqual, 1)
+			So(msgs[0].Topic, ShouldEqual, topic)
+			So(string(msgs[0].Key), ShouldEqual, "btcusd")
+			So(string(msgs[0].Value), ShouldEqual, "first")
+			So(msgs[0].Headers, ShouldHaveLength, 1)
+			So(msgs[0].Headers[0].Key, ShouldEqual, "request_id")
+			So(string(msgs[0].Headers[0].Value), ShouldEqual, "abc")
+			So(msgs[2].Offset, ShouldEqual, 3)
+			So(string(msgs[2].Value), ShouldEqual, "third")
+
+			Convey("And then the messages published after it started", func() {
+				producer.WriteMessages(ctx, net.Message{Value: []byte("fourth")})
+				msgs := readMessages(consumer, 1)
+				So(msgs, ShouldHaveLength, 1)
+				So(msgs[0].Offset, ShouldEqual, 4)
+				So(string(msgs[0].Value), ShouldEqual, "fourth")
+			})
+		})
+
+		Convey("A consumer should replay the stream from the offset restored from a backup", func() {
+			consumer := net.NewNATSConsumer(cfg, servers, topic)
+			consumer.SetOffset(2)
+			So(consumer.Start(ctx), ShouldBeNil)
+			defer consumer.Close()
+			msgs := readMessages(consumer, 1)
+			So(msgs, ShouldHaveLength, 1)
+			So(msgs[0].Offset, ShouldEqual, 2)
+			So(string(msgs[0].Value), ShouldEqual, "second")
+		})
+
+		Convey("A consumer should skip the messages deleted from the stream", func() {
+			nc, err := nats.Connect(srv.ClientURL())
+			So(err, ShouldBeNil)
+			defer nc.Close()
+			js, err := nc.JetStream()
+			So(err, ShouldBeNil)
+			So(js.DeleteMsg(net.NATSStreamName(topic), 2), ShouldBeNil)
+
+			consumer := net.NewNATSConsumer(cfg, servers, topic)
+			consumer.SetOffset(2)
+			So(consumer.Start(ctx), ShouldBeNil)
+			defer consumer.Close()
+			msgs := readMessages(consumer, 1)
+			So(msgs, ShouldHaveLength, 1)
+			So(msgs[0].Offset, ShouldEqual, 3)
+			So(string(msgs[0].Value), ShouldEqual, "third")
+
+			Convey("And keep reading the messages published after the gap", func() {
+				producer.WriteMessages(ctx, net.Message{Value: []byte("fourth")})
+				msgs := readMessages(consumer, 1)
+				So(msgs, ShouldHaveLength, 1)
+				So(msgs[0].Offset, ShouldEqual, 4)
+			})
+		})
+
+		Convey("A consumer started from the last offset should only read new messages", func() {
+			consumer := net.NewNATSConsumer(cfg, servers, topic)
+			consumer.SetOffset(net.LastOffset)
+			So(consumer.Start(ctx), ShouldBeNil)
+			defer consumer.Close()
+			time.Sleep(100 * time.Millisecond)
+			producer.WriteMessages(ctx, net.Message{Value: []byte("new")})
+			msgs := readMessages(consumer, 1)
+			So(msgs, ShouldHaveLength, 1)
+			So(string(msgs[0].Value), ShouldEqual, "new")
+		})
+	})
+}
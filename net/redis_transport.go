@@ -0,0 +1,404 @@
+package net
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rs/zerolog/log"
+)
+
+// Fields of the stream entries used to store the parts of a message
+const (
+	RedisValueField   = "value"
+	RedisKeyField     = "key"
+	RedisHeaderPrefix = "header:"
+)
+
+// Defaults used when the values are not set in the configuration
+const (
+	DefaultRedisBlock     = 1000
+	DefaultRedisBatchSize = 100
+)
+
+const (
+	redisSequenceBits  = 20
+	redisSequenceMask  = 1<<redisSequenceBits - 1
+	redisMaxMillis     = 1<<(63-redisSequenceBits) - 1
+	redisReconnectWait = time.Second
+)
+
+// ErrRedisStreamID is returned for a stream ID that can't be represented as an offset
+var ErrRedisStreamID = errors.New("invalid redis stream id")
+
+// RedisConfig godoc
+type RedisConfig struct {
+	// Password used to authenticate on the server
+	Password string `mapstructure:"password"`
+	// Database selected after connecting to the server
+	Database int `mapstructure:"database"`
+	// MaxLen trims the streams written by the engine to approximately this number of entries. 0 keeps all entries.
+	MaxLen int64 `mapstructure:"max_len"`
+	// Block is the number of milliseconds a read waits for new entries of a stream
+	Block int `mapstructure:"block"` // default 1000 ms
+	// BatchSize is the maximum number of entries returned by a read
+	BatchSize int `mapstructure:"batch_size"` // default 100
+	// ChannelSize sets the size of the channel used to read messages from the stream
+	ChannelSize int `mapstructure:"channel_size"` // 20000
+}
+
+// RedisOffset converts a stream ID into the offset of a message
+//
+// The milliseconds part of the ID is kept in the upper 43 bits of the offset and the sequence part in the lower 20
+// bits so the offsets of the entries of a stream keep the order of their IDs.
+func RedisOffset(id string) (int64, error) {
+	parts := strings.SplitN(id, "-", 2)
+	millis, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, ErrRedisStreamID
+	}
+	sequence := uint64(0)
+	if len(parts) == 2 {
+		if sequence, err = strconv.ParseUint(parts[1], 10, 64); err != nil {
+			return 0, ErrRedisStreamID
+		}
+	}
+	if millis > redisMaxMillis || sequence > redisSequenceMask {
+		return 0, ErrRedisStreamID
+	}
+	return int64(millis<<redisSequenceBits | sequence), nil
+}
+
+// RedisStreamID converts the offset of a message into its stream ID
+func RedisStreamID(offset int64) string {
+	return strconv.FormatInt(offset>>redisSequenceBits, 10) + "-" + strconv.FormatInt(offset&redisSequenceMask, 10)
+}
+
+// dialRedis connects to the first server of the list
+func dialRedis(cfg RedisConfig, servers []string) (redis.Conn, error) {
+	if len(servers) == 0 {
+		return nil, errors.New("no redis server configured")
+	}
+	return redis.Dial("tcp", servers[0], redis.DialPassword(cfg.Password), redis.DialDatabase(cfg.Database))
+}
+
+// redisProducer appends messages to the stream of a topic
+type redisProducer struct {
+	cfg     RedisConfig
+	servers []string
+	topic   string
+	pool    *redis.Pool
+}
+
+// NewRedisProducer returns a new Redis Streams producer that connects to the server when started
+func NewRedisProducer(cfg RedisConfig, servers []string, topic string) Producer {
+	return &redisProducer{cfg: cfg, servers: servers, topic: topic}
+}
+
+// Start connects to the server
+func (conn *redisProducer) Start() error {
+	conn.pool = &redis.Pool{
+		MaxIdle: 1,
+		Dial:    func() (redis.Conn, error) { return dialRedis(conn.cfg, conn.servers) },
+	}
+	c := conn.pool.Get()
+	defer c.Close()
+	_, err := c.Do("PING")
+	return err
+}
+
+// WriteMessages appends the messages to the stream in a single transaction
+func (conn *redisProducer) WriteMessages(ctx context.Context, msgs ...Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	c, err := conn.pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := c.Send("MULTI"); err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		if err := c.Send("XADD", conn.xaddArgs(msg)...); err != nil {
+			return err
+		}
+	}
+	replies, err := redis.Values(redis.DoContext(c, ctx, "EXEC"))
+	if err != nil {
+		return err
+	}
+	for _, reply := range replies {
+		if err, ok := reply.(redis.Error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// xaddArgs returns the arguments of the command that appends the message to the stream
+func (conn *redisProducer) xaddArgs(msg Message) []interface{} {
+	args := make([]interface{}, 0, 8+2*len(msg.Headers))
+	args = append(args, conn.topic)
+	if conn.cfg.MaxLen > 0 {
+		args = append(args, "MAXLEN", "~", conn.cfg.MaxLen)
+	}
+	args = append(args, "*", RedisValueField, msg.Value)
+	if len(msg.Key) > 0 {
+		args = append(args, RedisKeyField, msg.Key)
+	}
+	for _, header := range msg.Headers {
+		args = append(args, RedisHeaderPrefix+header.Key, header.Value)
+	}
+	return args
+}
+
+// Close the connections to the server
+func (conn *redisProducer) Close() error {
+	if conn.pool != nil {
+		return conn.pool.Close()
+	}
+	return nil
+}
+
+// redisConsumer reads the entries of the stream of a topic in order
+//
+// The offset of a message is its stream ID converted with RedisOffset. Entries are read with XREAD and are not
+// acknowledged since the offset of the last processed message is kept in the market backup.
+type redisConsumer struct {
+	cfg     RedisConfig
+	servers []string
+	topic   string
+	offset  int64
+	inputs  chan Message
+	lock    sync.Mutex
+	conn    redis.Conn
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewRedisConsumer returns a new Redis Streams consumer that starts from the first entry of the stream by default
+func NewRedisConsumer(cfg RedisConfig, servers []string, topic string) Consumer {
+	return &redisConsumer{
+		cfg:     cfg,
+		servers: servers,
+		topic:   topic,
+		offset:  FirstOffset,
+		inputs:  make(chan Message, cfg.ChannelSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// SetOffset sets the offset of the next message to read from the stream
+func (conn *redisConsumer) SetOffset(offset int64) error {
+	conn.offset = offset
+	return nil
+}
+
+// Start connects to the server and reads the messages in the background
+func (conn *redisConsumer) Start(ctx context.Context) error {
+	c, err := dialRedis(conn.cfg, conn.servers)
+	if err != nil {
+		return err
+	}
+	conn.setConn(c)
+	go conn.handleMessages(ctx)
+	return nil
+}
+
+// startID returns the ID after which the stream is read for the configured offset
+func (conn *redisConsumer) startID() string {
+	switch {
+	case conn.offset == LastOffset:
+		return "$"
+	case conn.offset > 0:
+		// XREAD returns the entries with an ID greater than the given one
+		return RedisStreamID(conn.offset - 1)
+	}
+	return "0-0"
+}
+
+func (conn *redisConsumer) setConn(c redis.Conn) {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	conn.conn = c
+}
+
+func (conn *redisConsumer) getConn() redis.Conn {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	return conn.conn
+}
+
+// reconnect replaces the connection after an error and returns false once the consumer is closed
+func (conn *redisConsumer) reconnect(ctx context.Context) bool {
+	conn.getConn().Close()
+	for {
+		select {
+		case <-time.After(redisReconnectWait):
+		case <-conn.done:
+			return false
+		case <-ctx.Done():
+			return false
+		}
+		c, err := dialRedis(conn.cfg, conn.servers)
+		if err == nil {
+			conn.setConn(c)
+			return true
+		}
+		log.Warn().Err(err).Str("section", "redis").Str("topic", conn.topic).Msg("Unable to connect to server. Retrying in 1 second")
+	}
+}
+
+func (conn *redisConsumer) handleMessages(ctx context.Context) {
+	defer close(conn.inputs)
+	defer func() { conn.getConn().Close() }()
+	block := conn.cfg.Block
+	if block <= 0 {
+		block = DefaultRedisBlock
+	}
+	count := conn.cfg.BatchSize
+	if count <= 0 {
+		count = DefaultRedisBatchSize
+	}
+	lastID := conn.startID()
+	log.Info().Str("section", "redis").Str("topic", conn.topic).Str("last_id", lastID).Msg("Starting message consumer")
+	for {
+		select {
+		case <-conn.done:
+			return
+		case <-ctx.Done():
+			return
+		default:
+		}
+		reply, err := redis.DoWithTimeout(conn.getConn(), time.Duration(block)*time.Millisecond+redisReconnectWait,
+			"XREAD", "COUNT", count, "BLOCK", block, "STREAMS", conn.topic, lastID)
+		if err == redis.ErrNil {
+			continue
+		}
+		if err != nil {
+			select {
+			case <-conn.done:
+				return
+			default:
+			}
+			log.Warn().Err(err).Str("section", "redis").Str("topic", conn.topic).Str("last_id", lastID).Msg("Unable to read from stream. Reconnecting")
+			if !conn.reconnect(ctx) {
+				return
+			}
+			continue
+		}
+		msgs, err := conn.parseEntries(reply)
+		if err != nil {
+			log.Error().Err(err).Str("section", "redis").Str("topic", conn.topic).Str("last_id", lastID).Msg("Unable to decode stream entries")
+			if !conn.reconnect(ctx) {
+				return
+			}
+			continue
+		}
+		for _, entry := range msgs {
+			lastID = entry.id
+			if entry.err != nil {
+				log.Error().Err(entry.err).Str("section", "redis").Str("topic", conn.topic).Str("id", entry.id).Msg("Skipping stream entry with an ID that can't be used as offset")
+				continue
+			}
+			select {
+			case conn.inputs <- entry.msg:
+			case <-conn.done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// redisEntry is a message read from the stream together with its stream ID
+type redisEntry struct {
+	id  string
+	msg Message
+	err error
+}
+
+// parseEntries converts the reply of XREAD into the messages of the stream
+func (conn *redisConsumer) parseEntries(reply interface{}) ([]redisEntry, error) {
+	streams, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]redisEntry, 0)
+	for _, stream := range streams {
+		parts, err := redis.Values(stream, nil)
+		if err != nil || len(parts) != 2 {
+			return nil, ErrRedisStreamID
+		}
+		items, err := redis.Values(parts[1], nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			fields, err := redis.Values(item, nil)
+			if err != nil || len(fields) != 2 {
+				return nil, ErrRedisStreamID
+			}
+			id, err := redis.String(fields[0], nil)
+			if err != nil {
+				return nil, err
+			}
+			values, err := redis.ByteSlices(fields[1], nil)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, fromRedisEntry(conn.topic, id, values))
+		}
+	}
+	return entries, nil
+}
+
+// GetMessageChan returns the message channel
+func (conn *redisConsumer) GetMessageChan() <-chan Message {
+	return conn.inputs
+}
+
+// CommitMessages does nothing since the offset of the last processed message is kept in the market backup
+func (conn *redisConsumer) CommitMessages(ctx context.Context, msgs ...Message) error {
+	return nil
+}
+
+// Close stops reading messages and closes the connection to the server
+func (conn *redisConsumer) Close() error {
+	conn.once.Do(func() {
+		close(conn.done)
+		if c := conn.getConn(); c != nil {
+			c.Close()
+		}
+	})
+	return nil
+}
+
+// fromRedisEntry converts the fields of a stream entry into a message
+func fromRedisEntry(topic, id string, values [][]byte) redisEntry {
+	entry := redisEntry{id: id}
+	entry.msg.Topic = topic
+	entry.msg.Offset, entry.err = RedisOffset(id)
+	if entry.err == nil {
+		entry.msg.Time = time.Unix(0, (entry.msg.Offset>>redisSequenceBits)*int64(time.Millisecond))
+	}
+	for i := 0; i+1 < len(values); i += 2 {
+		field := string(values[i])
+		switch {
+		case field == RedisValueField:
+			entry.msg.Value = values[i+1]
+		case field == RedisKeyField:
+			entry.msg.Key = values[i+1]
+		case strings.HasPrefix(field, RedisHeaderPrefix):
+			entry.msg.Headers = append(entry.msg.Headers, Header{Key: strings.TrimPrefix(field, RedisHeaderPrefix), Value: values[i+1]})
+		}
+	}
+	return entry
+}
@@ -0,0 +1,108 @@
+package net_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRedisStreamID(t *testing.T) {
+	Convey("Stream IDs should be converted into ordered offsets and back", t, func() {
+		first, err := net.RedisOffset("1526919030474-55")
+		So(err, ShouldBeNil)
+		second, err := net.RedisOffset("1526919030474-56")
+		So(err, ShouldBeNil)
+		third, err := net.RedisOffset("1526919030475-0")
+		So(err, ShouldBeNil)
+		So(second, ShouldEqual, first+1)
+		So(third, ShouldBeGreaterThan, second)
+		So(net.RedisStreamID(first), ShouldEqual, "1526919030474-55")
+		So(net.RedisStreamID(third), ShouldEqual, "1526919030475-0")
+
+		_, err = net.RedisOffset("1526919030474-2000000")
+		So(err, ShouldEqual, net.ErrRedisStreamID)
+		_, err = net.RedisOffset("invalid")
+		So(err, ShouldEqual, net.ErrRedisStreamID)
+	})
+}
+
+func TestRedisTransport(t *testing.T) {
+	Convey("Given a stream with a few messages written by a producer", t, func() {
+		srv, err := miniredis.Run()
+		So(err, ShouldBeNil)
+		defer srv.Close()
+		ctx := context.Background()
+		servers := []string{srv.Addr()}
+		cfg := net.RedisConfig{ChannelSize: 10, Block: 50, MaxLen: 1000}
+		topic := "engine.orders.btcusd"
+		producer := net.NewRedisProducer(cfg, servers, topic)
+		So(producer.Start(), ShouldBeNil)
+		defer producer.Close()
+		err = producer.WriteMessages(ctx,
+			net.Message{Key: []byte("btcusd"), Value: []byte("first"), Headers: []net.Header{{Key: "request_id", Value: []byte("abc")}}},
+			net.Message{Value: []byte("second")},
+			net.Message{Value: []byte("third")},
+		)
+		So(err, ShouldBeNil)
+
+		Convey("A consumer should read the messages in order with the stream ID as offset", func() {
+			consumer := net.NewRedisConsumer(cfg, servers, topic)
+			So(consumer.Start(ctx), ShouldBeNil)
+			defer consumer.Close()
+			msgs := readMessages(consumer, 3)
+			So(msgs, ShouldHaveLength, 3)
+			So(msgs[0].Topic, ShouldEqual, topic)
+			So(string(msgs[0].Key), ShouldEqual, "btcusd")
+			So(string(msgs[0].Value), ShouldEqual, "first")
+			So(msgs[0].Headers, ShouldHaveLength, 1)
+			So(msgs[0].Headers[0].Key, ShouldEqual, "request_id")
+			So(string(msgs[0].Headers[0].Value), ShouldEqual, "abc")
+			So(msgs[1].Offset, ShouldBeGreaterThan, msgs[0].Offset)
+			So(msgs[2].Offset, ShouldBeGreaterThan, msgs[1].Offset)
+			So(string(msgs[2].Value), ShouldEqual, "third")
+
+			Convey("And then the messages written after it started", func() {
+				producer.WriteMessages(ctx, net.Message{Value: []byte("fourth")})
+				msgs := readMessages(consumer, 1)
+				So(msgs, ShouldHaveLength, 1)
+				So(string(msgs[0].Value), ShouldEqual, "fourth")
+			})
+		})
+
+		Convey("A consumer should replay the stream after the offset restored from a backup", func() {
+			consumer := net.NewRedisConsumer(cfg, servers, topic)
+			So(consumer.Start(ctx), ShouldBeNil)
+			msgs := readMessages(consumer, 3)
+			consumer.Close()
+			So(msgs, ShouldHaveLength, 3)
+
+			// the backup keeps the offset of the last processed message and the next one is read after a restart
+			replay := net.NewRedisConsumer(cfg, servers, topic)
+			replay.SetOffset(msgs[0].Offset + 1)
+			So(replay.Start(ctx), ShouldBeNil)
+			defer replay.Close()
+			replayed := readMessages(replay, 2)
+			So(replayed, ShouldHaveLength, 2)
+			So(replayed[0].Offset, ShouldEqual, msgs[1].Offset)
+			So(string(replayed[0].Value), ShouldEqual, "second")
+			So(string(replayed[1].Value), ShouldEqual, "third")
+		})
+
+		Convey("A consumer started from the last offset should only read new messages", func() {
+			consumer := net.NewRedisConsumer(cfg, servers, topic)
+			consumer.SetOffset(net.LastOffset)
+			So(consumer.Start(ctx), ShouldBeNil)
+			defer consumer.Close()
+			time.Sleep(20 * time.Millisecond)
+			producer.WriteMessages(ctx, net.Message{Value: []byte("new")})
+			msgs := readMessages(consumer, 1)
+			So(msgs, ShouldHaveLength, 1)
+			So(string(msgs[0].Value), ShouldEqual, "new")
+		})
+	})
+}
@@ -0,0 +1,66 @@
+package net
+
+import (
+	"context"
+	"time"
+)
+
+// Defaults of the retry policy used when the values are not set in the configuration
+const (
+	DefaultRetryAttempts   = 10
+	DefaultRetryBackoff    = 100
+	DefaultRetryMaxBackoff = 10000
+)
+
+// RetryPolicy godoc
+type RetryPolicy struct {
+	// Attempts is the maximum number of writes of the same messages before giving up
+	Attempts int `mapstructure:"attempts"` // default 10
+	// Backoff is the number of milliseconds to wait after the first failed write, doubled after each failure
+	Backoff int `mapstructure:"backoff"` // default 100 ms
+	// MaxBackoff is the maximum number of milliseconds to wait between two writes
+	MaxBackoff int `mapstructure:"max_backoff"` // default 10000 ms
+}
+
+// RetryFunc is called after a failed write with the number of the attempt and the time until the next one
+type RetryFunc func(attempt int, err error, wait time.Duration)
+
+// WriteMessagesWithRetry writes the messages with the producer and retries with an exponential backoff on failure
+//
+// The error of the last attempt is returned once all the attempts failed or the context is done. Since the messages
+// of a failed write may have been partially stored they can be delivered more than once.
+func WriteMessagesWithRetry(ctx context.Context, producer Producer, policy RetryPolicy, onRetry RetryFunc, msgs ...Message) error {
+	attempts := policy.Attempts
+	if attempts <= 0 {
+		attempts = DefaultRetryAttempts
+	}
+	backoff := time.Duration(policy.Backoff) * time.Millisecond
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff * time.Millisecond
+	}
+	maxBackoff := time.Duration(policy.MaxBackoff) * time.Millisecond
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff * time.Millisecond
+	}
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = producer.WriteMessages(ctx, msgs...); err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return err
+		}
+		if onRetry != nil {
+			onRetry(attempt, err, backoff)
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
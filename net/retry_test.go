@@ -0,0 +1,66 @@
+package net_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// flakyProducer fails the given number of writes before storing the messages in a memory topic
+type flakyProducer struct {
+	net.Producer
+	failures int
+	writes   int
+}
+
+func (producer *flakyProducer) WriteMessages(ctx context.Context, msgs ...net.Message) error {
+	producer.writes++
+	if producer.writes <= producer.failures {
+		return errors.New("broker not available")
+	}
+	return producer.Producer.WriteMessages(ctx, msgs...)
+}
+
+func TestWriteMessagesWithRetry(t *testing.T) {
+	policy := net.RetryPolicy{Attempts: 3, Backoff: 1, MaxBackoff: 2}
+
+	Convey("Given a producer that fails a few writes", t, func() {
+		topic := net.NewMemoryTopic("engine.events.btcusd")
+		producer := &flakyProducer{Producer: net.NewMemoryProducer(topic), failures: 2}
+		waits := make([]time.Duration, 0)
+		onRetry := func(attempt int, err error, wait time.Duration) {
+			waits = append(waits, wait)
+		}
+
+		Convey("The messages should be written once the producer recovers", func() {
+			err := net.WriteMessagesWithRetry(context.Background(), producer, policy, onRetry, net.Message{Value: []byte("a")})
+			So(err, ShouldBeNil)
+			So(producer.writes, ShouldEqual, 3)
+			So(topic.Messages(), ShouldHaveLength, 1)
+			So(waits, ShouldResemble, []time.Duration{time.Millisecond, 2 * time.Millisecond})
+		})
+
+		Convey("The error should be returned when all the attempts fail", func() {
+			producer.failures = 5
+			err := net.WriteMessagesWithRetry(context.Background(), producer, policy, onRetry, net.Message{Value: []byte("a")})
+			So(err, ShouldNotBeNil)
+			So(producer.writes, ShouldEqual, 3)
+			So(waits, ShouldHaveLength, 2)
+			So(topic.Messages(), ShouldBeEmpty)
+		})
+
+		Convey("The retries should stop when the context is done", func() {
+			producer.failures = 5
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := net.WriteMessagesWithRetry(ctx, producer, policy, nil, net.Message{Value: []byte("a")})
+			So(err, ShouldNotBeNil)
+			So(producer.writes, ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,52 @@
+package net
+
+import (
+	"context"
+	"time"
+)
+
+// Special offsets that can be given to a consumer instead of the offset of a message
+const (
+	// FirstOffset starts reading from the oldest message kept by the transport
+	FirstOffset int64 = -2
+	// LastOffset starts reading from the next message written after the consumer started
+	LastOffset int64 = -1
+)
+
+// Header is a key and value pair attached to a message
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Message is read from an input stream or written to an output stream independently of the transport used
+type Message struct {
+	// Topic, Partition and Offset identify a message read from a stream and are ignored when writing
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []Header
+	Time      time.Time
+}
+
+// Producer writes messages at the end of an output stream
+type Producer interface {
+	Start() error
+	// WriteMessages returns once the messages were stored by the transport, in the order in which they were given
+	WriteMessages(context.Context, ...Message) error
+	Close() error
+}
+
+// Consumer reads the messages of an input stream in the order in which they were written
+type Consumer interface {
+	// Start reading messages from the offset set before starting the consumer, the first message by default
+	Start(ctx context.Context) error
+	// SetOffset sets the offset of the next message to read
+	SetOffset(offset int64) error
+	GetMessageChan() <-chan Message
+	// CommitMessages marks the messages as processed so a new consumer of the stream can resume after them
+	CommitMessages(context.Context, ...Message) error
+	Close() error
+}
@@ -1,19 +1,36 @@
 package net
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"net"
 	"os"
 	"time"
 )
 
+// MaxSocketMessageSize is the maximum size in bytes of a message read from a socket
+const MaxSocketMessageSize = 1 << 20
+
+// DefaultSocketTimeout is the default time allowed to read or write a message
+const DefaultSocketTimeout = time.Second
+
+// ErrMessageTooLarge is returned when a message read from the socket exceeds the maximum size
+// - The rest of the message is discarded so the next message can still be read
+var ErrMessageTooLarge = errors.New("message exceeds the maximum size")
+
 // Socket stores the connection to a Unix Domain Socket and allows the communication through JSON messages between them
+//
+// Each message is a JSON document on a single line. A message that can't be decoded or that is too large does not
+// affect the messages that follow it on the connection.
 type Socket interface {
 	Listen() error
-	Accept() error
+	Accept() (Socket, error)
 	Dial() error
 	Read(msg interface{}) error
 	Send(msg interface{}) error
+	SetTimeout(timeout time.Duration)
 	More() bool
 	Close() error
 	CloseListener() error
@@ -21,15 +38,16 @@ type Socket interface {
 
 type unixSocket struct {
 	address  string
+	timeout  time.Duration
 	listener net.Listener
 	conn     net.Conn
-	decoder  *json.Decoder
+	reader   *bufio.Reader
 	encoder  *json.Encoder
 }
 
 // NewSocket create a new socket object
 func NewSocket(address string) Socket {
-	return &unixSocket{address: address}
+	return &unixSocket{address: address, timeout: DefaultSocketTimeout}
 }
 
 // Listen start a socket connection
@@ -40,16 +58,16 @@ func (socket *unixSocket) Listen() error {
 	return err
 }
 
-// Accept connection on the listener
-func (socket *unixSocket) Accept() error {
+// Accept waits for the next connection on the listener and returns a socket for it
+// - The returned socket can be used concurrently with the ones created for the other connections
+func (socket *unixSocket) Accept() (Socket, error) {
 	conn, err := socket.listener.Accept()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	socket.conn = conn
-	socket.decoder = json.NewDecoder(conn)
-	socket.encoder = json.NewEncoder(conn)
-	return nil
+	client := &unixSocket{address: socket.address, timeout: socket.timeout}
+	client.setConn(conn)
+	return client, nil
 }
 
 // Dial connects a client to a server via Unix Sockets
@@ -58,28 +76,80 @@ func (socket *unixSocket) Dial() error {
 	if err != nil {
 		return err
 	}
+	socket.setConn(conn)
+	return nil
+}
+
+func (socket *unixSocket) setConn(conn net.Conn) {
 	socket.conn = conn
-	socket.decoder = json.NewDecoder(conn)
+	socket.reader = bufio.NewReader(conn)
 	socket.encoder = json.NewEncoder(conn)
-	return nil
 }
 
+// SetTimeout sets the time allowed to read or write a message, zero waits forever
+func (socket *unixSocket) SetTimeout(timeout time.Duration) {
+	socket.timeout = timeout
+}
+
+// Read the next message from the socket and decode it from json
 func (socket *unixSocket) Read(msg interface{}) error {
-	socket.conn.SetReadDeadline(time.Now().Add(time.Second))
-	err := socket.decoder.Decode(msg)
-	return err
+	socket.conn.SetReadDeadline(socket.deadline())
+	line, err := socket.readLine()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(line, msg)
+}
+
+// readLine reads the next line without the line separator
+func (socket *unixSocket) readLine() ([]byte, error) {
+	line := make([]byte, 0)
+	for {
+		chunk, err := socket.reader.ReadSlice('\n')
+		if len(line)+len(chunk) > MaxSocketMessageSize {
+			// discard the rest of the message
+			for err == bufio.ErrBufferFull {
+				_, err = socket.reader.ReadSlice('\n')
+			}
+			if err != nil {
+				return nil, err
+			}
+			return nil, ErrMessageTooLarge
+		}
+		line = append(line, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			// skip empty lines between messages
+			continue
+		}
+		return line, nil
+	}
 }
 
 // Send a message as json to the socket
 func (socket *unixSocket) Send(msg interface{}) error {
-	socket.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	socket.conn.SetWriteDeadline(socket.deadline())
 	err := socket.encoder.Encode(msg)
 	return err
 }
 
+func (socket *unixSocket) deadline() time.Time {
+	if socket.timeout == 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(socket.timeout)
+}
+
 // More checks if there is more to read from the connection
-func (socket unixSocket) More() bool {
-	return socket.decoder.More()
+func (socket *unixSocket) More() bool {
+	_, err := socket.reader.Peek(1)
+	return err == nil
 }
 
 // Close the socket connection
@@ -0,0 +1,113 @@
+package net_test
+
+import (
+	"io/ioutil"
+	stdnet "net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type socketMessage struct {
+	ID int `json:"id"`
+}
+
+func TestUnixSocket(t *testing.T) {
+	Convey("Given a socket server with a connected client", t, func() {
+		dir, err := ioutil.TempDir("", "socket")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		address := filepath.Join(dir, "engine.sock")
+		server := net.NewSocket(address)
+		So(server.Listen(), ShouldBeNil)
+		defer server.CloseListener()
+
+		accepted := make(chan net.Socket, 1)
+		go func() {
+			conn, err := server.Accept()
+			if err == nil {
+				accepted <- conn
+			}
+		}()
+		client, err := stdnet.Dial("unix", address)
+		So(err, ShouldBeNil)
+		defer client.Close()
+		conn := <-accepted
+		defer conn.Close()
+		conn.SetTimeout(5 * time.Second)
+
+		Convey("Each line should be decoded as a message and empty lines skipped", func() {
+			client.Write([]byte("{\"id\":1}\n\n  \n{\"id\":2}\r\n"))
+			var msg socketMessage
+			So(conn.Read(&msg), ShouldBeNil)
+			So(msg.ID, ShouldEqual, 1)
+			So(conn.Read(&msg), ShouldBeNil)
+			So(msg.ID, ShouldEqual, 2)
+		})
+
+		Convey("A message written in several parts should be read once its line is complete", func() {
+			go func() {
+				for _, part := range []string{"{\"i", "d\":", "3}", "\n"} {
+					client.Write([]byte(part))
+					time.Sleep(20 * time.Millisecond)
+				}
+			}()
+			var msg socketMessage
+			So(conn.Read(&msg), ShouldBeNil)
+			So(msg.ID, ShouldEqual, 3)
+		})
+
+		Convey("A message over the maximum size should be rejected without affecting the next one", func() {
+			go func() {
+				client.Write([]byte("{\"id\":\"" + strings.Repeat("x", net.MaxSocketMessageSize) + "\"}\n{\"id\":4}\n"))
+			}()
+			var msg socketMessage
+			So(conn.Read(&msg), ShouldEqual, net.ErrMessageTooLarge)
+			So(conn.Read(&msg), ShouldBeNil)
+			So(msg.ID, ShouldEqual, 4)
+		})
+
+		Convey("An invalid message should not affect the next one", func() {
+			client.Write([]byte("{\"id\":\n{\"id\":5}\n"))
+			var msg socketMessage
+			So(conn.Read(&msg), ShouldNotBeNil)
+			So(conn.Read(&msg), ShouldBeNil)
+			So(msg.ID, ShouldEqual, 5)
+		})
+
+		Convey("A read should time out if the line is not complete", func() {
+			conn.SetTimeout(50 * time.Millisecond)
+			client.Write([]byte("{\"id\":6"))
+			var msg socketMessage
+			err := conn.Read(&msg)
+			So(err, ShouldNotBeNil)
+			netErr, ok := err.(stdnet.Error)
+			So(ok, ShouldBeTrue)
+			So(netErr.Timeout(), ShouldBeTrue)
+		})
+
+		Convey("Messages sent by the server should be read by a socket client", func() {
+			other := net.NewSocket(address)
+			go func() {
+				conn, err := server.Accept()
+				if err == nil {
+					accepted <- conn
+				}
+			}()
+			So(other.Dial(), ShouldBeNil)
+			defer other.Close()
+			otherConn := <-accepted
+			defer otherConn.Close()
+			So(otherConn.Send(socketMessage{ID: 7}), ShouldBeNil)
+			var msg socketMessage
+			So(other.Read(&msg), ShouldBeNil)
+			So(msg.ID, ShouldEqual, 7)
+		})
+	})
+}
@@ -34,16 +34,125 @@ type MarketConfig struct {
 	PricePrecision  int    `mapstructure:"price_precision"`
 	VolumePrecision int    `mapstructure:"volume_precision"`
 
+	// QuoteIncrements and BaseIncrements are the smallest steps of the prices and of the amounts of the new orders,
+	// the orders that don't match them are rejected with WrongPrecision. 0 accepts any value with the market precision.
 	QuoteIncrements float64 `mapstructure:"quote_increments"`
+	BaseIncrements  float64 `mapstructure:"base_increments"`
 	BaseMin         float64 `mapstructure:"base_min"`
 	BaseMax         float64 `mapstructure:"base_max"`
 
+	// DuplicateWindow is the number of recent orders kept to detect duplicate submissions
+	DuplicateWindow int `mapstructure:"duplicate_window"`
+	// TradeWindow is the number of recent trades that can be busted or corrected
+	TradeWindow int `mapstructure:"trade_window"`
+	// TradeOperators are the owners allowed to bust or correct the trades of the market.
+	// There are none by default: every bust or correction is rejected with UnauthorizedOperator until they are set.
+	TradeOperators []uint64 `mapstructure:"trade_operators"`
+	// BBOEvents enables the events generated when the best bid or the best ask of the market changes
+	BBOEvents bool `mapstructure:"bbo_events"`
+
 	Backup MarketBackupConfig
 
-	Listen  TopicConfig
+	// Transport used for the topics of the market: kafka (default), nats, redis, journal or memory
+	Transport string
+	Listen    TopicConfig
+	Publish   TopicConfig
+	// Output selects how events are published: event (default) sends each event as a message and batch sends
+	// a single Events message with all the events generated by a command
+	// - With event topics each topic receives its own Events message with the events of the command on that topic
+	Output string
+	// EventKey sets the key of the published events: market (default), owner, order or none
+	// - With the owner or order key the trades are keyed by the taker, the maker receives them on the private stream
+	EventKey string `mapstructure:"event_key"`
+	// EventTopics sets the topics of the types of events that are not published on the topic of the market
+	EventTopics EventTopicsConfig `mapstructure:"event_topics"`
+
+	Depth     DepthConfig
+	OrderFeed OrderFeedConfig `mapstructure:"order_feed"`
+	Candles   CandlesConfig
+	Ticker    TickerConfig
+	Query     QueryConfig
+	Private   PrivateConfig
+	// DeadLetter receives the input messages that could not be decoded or failed validation
+	DeadLetter DeadLetterConfig `mapstructure:"dead_letter"`
+}
+
+// Output modes of the events of a market
+const (
+	OutputEvent = "event"
+	OutputBatch = "batch"
+)
+
+// EventTopicsConfig structure
+// - Types of events without a topic are published on the topic of the market. The depth is configured separately.
+type EventTopicsConfig struct {
+	// Trades also receives the busted and the corrected trades
+	Trades      TopicConfig
+	OrderStatus TopicConfig `mapstructure:"order_status"`
+	Activations TopicConfig
+	Errors      TopicConfig
+	BBO         TopicConfig `mapstructure:"bbo"`
+}
+
+// PrivateConfig structure
+type PrivateConfig struct {
+	Enabled bool
+	// DropCopy lists the accounts that receive the trades of all the owners on their private stream
+	DropCopy []uint64 `mapstructure:"drop_copy"`
+	Publish  TopicConfig
+}
+
+// DeadLetterConfig structure
+type DeadLetterConfig struct {
+	Enabled bool
 	Publish TopicConfig
 }
 
+// QueryConfig structure
+type QueryConfig struct {
+	Enabled bool
+	// Interval is the number of milliseconds between two snapshots used to answer queries, defaults to 100
+	Interval int
+}
+
+// TickerConfig structure
+type TickerConfig struct {
+	Enabled bool
+	// Throttle is the minimum number of milliseconds between two published tickers, defaults to 1000
+	Throttle int
+	Publish  TopicConfig
+}
+
+// CandlesConfig structure
+type CandlesConfig struct {
+	Enabled bool
+	// Intervals of the generated candles like 1m, 5m, 1h or 1d
+	Intervals []string
+	Publish   TopicConfig
+}
+
+// DepthConfig structure
+type DepthConfig struct {
+	Enabled bool
+	// Interval is the number of seconds between two full depth snapshots
+	Interval int
+	// Levels is the maximum number of price levels on each side included in a snapshot, 0 for all levels
+	Levels int
+	// ChecksumLevels is the number of price levels on each side included in the checksum, defaults to 10
+	ChecksumLevels int `mapstructure:"checksum_levels"`
+	Publish        TopicConfig
+}
+
+// OrderFeedConfig structure
+type OrderFeedConfig struct {
+	Enabled bool
+	// Interval is the number of seconds between two full order feed snapshots
+	Interval int
+	// AnonymiseKey is the secret used to anonymise the published order ids, required when the feed is enabled
+	AnonymiseKey string `mapstructure:"anonymise_key"`
+	Publish      TopicConfig
+}
+
 // MarketBackupConfig structure
 type MarketBackupConfig struct {
 	Interval int
@@ -54,6 +163,70 @@ type MarketBackupConfig struct {
 type ServerConfig struct {
 	Debug      bool
 	Monitoring MonitoringConfig
+	GRPC       GRPCConfig
+	FIX        FIXConfig
+	WebSocket  WebSocketConfig
+	Socket     SocketConfig
+}
+
+// SocketConfig structure
+type SocketConfig struct {
+	Enabled bool
+	// Path of the Unix domain socket, defaults to /tmp/matching_engine.sock
+	Path string
+	// Timeout is the number of milliseconds a command waits for its events, defaults to 5000
+	Timeout int
+}
+
+// WebSocketConfig structure
+type WebSocketConfig struct {
+	Enabled bool
+	Host    string
+	Port    string
+	// Path on which the connections are accepted, defaults to /ws
+	Path string
+	// Buffer is the number of messages that can wait to be sent to a client before it is dropped, defaults to 1000
+	Buffer int
+}
+
+// FIXConfig structure
+type FIXConfig struct {
+	Enabled      bool
+	Host         string
+	Port         string
+	SenderCompID string `mapstructure:"sender_comp_id"`
+	// StorePath is the directory where the sequence numbers and the sent messages of the sessions are kept
+	StorePath string `mapstructure:"store_path"`
+	// StoreLimit is the number of sent messages kept by each session for resend requests, defaults to 10000
+	StoreLimit int `mapstructure:"store_limit"`
+	// Timeout is the number of milliseconds a command waits for its events, defaults to 5000
+	Timeout int
+	// FirstOrderID and LastOrderID bound the order ids reserved for the gateway, defaults to 2^62 and 2^63-1
+	// - The orders sent through the other inputs must use ids outside of the range
+	FirstOrderID uint64 `mapstructure:"first_order_id"`
+	LastOrderID  uint64 `mapstructure:"last_order_id"`
+	Sessions     []FIXSessionConfig
+}
+
+// FIXSessionConfig structure
+type FIXSessionConfig struct {
+	TargetCompID string `mapstructure:"target_comp_id"`
+	// OwnerID is the owner of the orders sent on the session
+	OwnerID uint64 `mapstructure:"owner_id"`
+	// Username and Password must be sent by the client in its logon message
+	Username string
+	Password string
+}
+
+// GRPCConfig structure
+type GRPCConfig struct {
+	Enabled bool
+	Host    string
+	Port    string
+	// Timeout is the number of milliseconds a command waits for its events, defaults to 5000
+	Timeout int
+	// History is the number of recent events kept by each market to resume subscriptions, defaults to 10000
+	History int
 }
 
 // MonitoringConfig structure
@@ -71,6 +244,19 @@ type Config struct {
 	Brokers     BrokersConfig
 	Server      ServerConfig
 	Kafka       net.KafkaConfig
+	NATS        net.NATSConfig
+	Redis       net.RedisConfig
+	Journal     JournalConfig
+	// Retry is the policy used to publish the events and the market data of the markets before stopping them
+	Retry net.RetryPolicy
+}
+
+// JournalConfig structure
+type JournalConfig struct {
+	// Path of the directory that contains the journal file of each topic
+	Path string
+	// Sync flushes every write to the disk before it is acknowledged
+	Sync bool
 }
 
 // LoadConfig Load server configuration from the yaml file
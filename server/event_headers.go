@@ -0,0 +1,153 @@
+package server
+
+import (
+	"strconv"
+
+	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// Keys used to partition the published events
+const (
+	EventKeyMarket = "market"
+	EventKeyOwner  = "owner"
+	EventKeyOrder  = "order"
+	EventKeyNone   = "none"
+)
+
+// Headers added to the published events so consumers can route and filter them without decoding the payload
+const (
+	HeaderEventType      = "event_type"
+	HeaderMarket         = "market"
+	HeaderSeqID          = "seq_id"
+	HeaderFirstSeqID     = "first_seq_id"
+	HeaderLastSeqID      = "last_seq_id"
+	HeaderSchemaVersion  = "schema_version"
+	HeaderInputTopic     = "input_topic"
+	HeaderInputPartition = "input_partition"
+	HeaderInputOffset    = "input_offset"
+	HeaderReplay         = "replay"
+)
+
+// BatchEventType is the value of the event type header of the messages published by the batch output
+const BatchEventType = "Events"
+
+// validEventKey checks if the configured event key is supported
+func validEventKey(key string) bool {
+	switch key {
+	case "", EventKeyMarket, EventKeyOwner, EventKeyOrder, EventKeyNone:
+		return true
+	}
+	return false
+}
+
+// eventKey returns the key of the message of an event
+// - Events without an owner or an order, like the best bid and offer, are keyed by market
+// - A trade has a single message keyed by the taker, so with the owner or order key the trades of a maker are on the
+// partition of the taker while its order status events are on its own partition. Consumers that need every trade of
+// an owner on one partition should read the private stream, which delivers the trades to both owners.
+func eventKey(keyType, market string, ev *model.Event) []byte {
+	switch keyType {
+	case EventKeyNone:
+		return nil
+	case EventKeyOwner:
+		if ownerID, _ := eventOwnerAndOrder(ev); ownerID != 0 {
+			return []byte(strconv.FormatUint(ownerID, 10))
+		}
+	case EventKeyOrder:
+		if _, orderID := eventOwnerAndOrder(ev); orderID != 0 {
+			return []byte(strconv.FormatUint(orderID, 10))
+		}
+	}
+	return []byte(market)
+}
+
+// batchKey returns the key of the message with the events generated by a command
+func batchKey(keyType, market string, order *model.Order) []byte {
+	switch keyType {
+	case EventKeyNone:
+		return nil
+	case EventKeyOwner:
+		if order.OwnerID != 0 {
+			return []byte(strconv.FormatUint(order.OwnerID, 10))
+		}
+	case EventKeyOrder:
+		if order.ID != 0 {
+			return []byte(strconv.FormatUint(order.ID, 10))
+		}
+	}
+	return []byte(market)
+}
+
+// eventOwnerAndOrder returns the owner and the order that caused the event
+// - For trades these are the owner and the order of the taker
+func eventOwnerAndOrder(ev *model.Event) (uint64, uint64) {
+	switch ev.Type {
+	case model.EventType_OrderStatusChange:
+		payload := ev.GetOrderStatus()
+		return payload.GetOwnerID(), payload.GetID()
+	case model.EventType_OrderActivated:
+		payload := ev.GetOrderActivation()
+		return payload.GetOwnerID(), payload.GetID()
+	case model.EventType_Error:
+		payload := ev.GetError()
+		return payload.GetOwnerID(), payload.GetOrderID()
+	case model.EventType_NewTrade:
+		return takerOwnerAndOrder(ev.GetTrade())
+	case model.EventType_TradeBusted:
+		return takerOwnerAndOrder(ev.GetTradeBust().GetTrade())
+	case model.EventType_TradeCorrected:
+		return takerOwnerAndOrder(ev.GetTradeCorrect().GetCorrected())
+	}
+	return 0, 0
+}
+
+func takerOwnerAndOrder(trade *model.Trade) (uint64, uint64) {
+	if trade.GetTakerSide() == model.MarketSide_Sell {
+		return trade.GetAskOwnerID(), trade.GetAskID()
+	}
+	return trade.GetBidOwnerID(), trade.GetBidID()
+}
+
+// eventHeaders returns the headers of the message of an event
+func eventHeaders(market string, ev *model.Event, event *engine.Event) []net.Header {
+	headers := make([]net.Header, 0, 7)
+	headers = append(headers,
+		net.Header{Key: HeaderEventType, Value: []byte(ev.Type.String())},
+		net.Header{Key: HeaderMarket, Value: []byte(market)},
+		net.Header{Key: HeaderSeqID, Value: []byte(strconv.FormatUint(ev.SeqID, 10))},
+		net.Header{Key: HeaderSchemaVersion, Value: []byte(strconv.Itoa(model.EventSchemaVersion))},
+	)
+	if ev.Replay {
+		// the event keeps the sequence id of the original acknowledgement so consumers can skip it
+		headers = append(headers, net.Header{Key: HeaderReplay, Value: []byte("true")})
+	}
+	return appendInputHeaders(headers, event)
+}
+
+// batchHeaders returns the headers of the message with the events generated by a command
+func batchHeaders(batch *model.Events, event *engine.Event) []net.Header {
+	headers := make([]net.Header, 0, 8)
+	headers = append(headers,
+		net.Header{Key: HeaderEventType, Value: []byte(BatchEventType)},
+		net.Header{Key: HeaderMarket, Value: []byte(batch.Market)},
+		net.Header{Key: HeaderFirstSeqID, Value: []byte(strconv.FormatUint(batch.FirstSeqID, 10))},
+		net.Header{Key: HeaderLastSeqID, Value: []byte(strconv.FormatUint(batch.LastSeqID, 10))},
+		net.Header{Key: HeaderSchemaVersion, Value: []byte(strconv.Itoa(model.EventSchemaVersion))},
+	)
+	return appendInputHeaders(headers, event)
+}
+
+// appendInputHeaders adds the position of the command in the input topic
+// - Commands received directly are not read from a topic and have no position
+func appendInputHeaders(headers []net.Header, event *engine.Event) []net.Header {
+	if event.Msg.Topic == "" {
+		return headers
+	}
+	return append(headers,
+		net.Header{Key: HeaderInputTopic, Value: []byte(event.Msg.Topic)},
+		net.Header{Key: HeaderInputPartition, Value: []byte(strconv.Itoa(event.Msg.Partition))},
+		net.Header{Key: HeaderInputOffset, Value: []byte(strconv.FormatInt(event.Msg.Offset, 10))},
+	)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// headerMap returns the headers of a message by key
+func headerMap(headers []net.Header) map[string]string {
+	values := make(map[string]string, len(headers))
+	for _, header := range headers {
+		values[header.Key] = string(header.Value)
+	}
+	return values
+}
+
+func TestEventKeys(t *testing.T) {
+	Convey("Given the events generated by a trade between two owners", t, func() {
+		status := model.NewOrderStatusEvent(1, "btcusd", model.OrderType_Limit, model.MarketSide_Sell, 11, 1, "", 100, 1, 0, model.OrderStatus_Filled, 1, 100)
+		trade := model.NewTradeEvent(2, "btcusd", 1, model.MarketSide_Buy, 11, 22, 1, 2, "", "", 1, 100)
+		sellTrade := model.NewTradeEvent(3, "btcusd", 2, model.MarketSide_Sell, 11, 22, 1, 2, "", "", 1, 100)
+		bust := model.NewTradeBustEvent(4, "btcusd", 5, 99, *trade.GetTrade(), nil)
+		bbo := model.NewBBOEvent(5, "btcusd", 100, 1, 110, 1)
+
+		Convey("The market key should be used by default", func() {
+			So(string(eventKey("", "btcusd", &trade)), ShouldEqual, "btcusd")
+			So(string(eventKey(EventKeyMarket, "btcusd", &status)), ShouldEqual, "btcusd")
+		})
+
+		Convey("The owner key should use the owner of the order or the taker of the trade", func() {
+			So(string(eventKey(EventKeyOwner, "btcusd", &status)), ShouldEqual, "1")
+			So(string(eventKey(EventKeyOwner, "btcusd", &trade)), ShouldEqual, "2")
+			So(string(eventKey(EventKeyOwner, "btcusd", &sellTrade)), ShouldEqual, "1")
+			So(string(eventKey(EventKeyOwner, "btcusd", &bust)), ShouldEqual, "2")
+		})
+
+		Convey("The order key should use the order or the taker order of the trade", func() {
+			So(string(eventKey(EventKeyOrder, "btcusd", &status)), ShouldEqual, "11")
+			So(string(eventKey(EventKeyOrder, "btcusd", &trade)), ShouldEqual, "22")
+			So(string(eventKey(EventKeyOrder, "btcusd", &sellTrade)), ShouldEqual, "11")
+		})
+
+		Convey("Events without an owner should be keyed by market", func() {
+			So(string(eventKey(EventKeyOwner, "btcusd", &bbo)), ShouldEqual, "btcusd")
+			So(string(eventKey(EventKeyOrder, "btcusd", &bbo)), ShouldEqual, "btcusd")
+		})
+
+		Convey("No key should be set with the none key", func() {
+			So(eventKey(EventKeyNone, "btcusd", &trade), ShouldBeNil)
+		})
+	})
+
+	Convey("Given a command", t, func() {
+		order := &model.Order{ID: 22, OwnerID: 2}
+
+		Convey("Its batch should be keyed by its owner, its order or its market", func() {
+			So(string(batchKey(EventKeyOwner, "btcusd", order)), ShouldEqual, "2")
+			So(string(batchKey(EventKeyOrder, "btcusd", order)), ShouldEqual, "22")
+			So(string(batchKey(EventKeyMarket, "btcusd", order)), ShouldEqual, "btcusd")
+			So(string(batchKey(EventKeyOwner, "btcusd", &model.Order{})), ShouldEqual, "btcusd")
+			So(batchKey(EventKeyNone, "btcusd", order), ShouldBeNil)
+		})
+	})
+
+	Convey("Only the supported keys should be valid", t, func() {
+		for _, key := range []string{"", EventKeyMarket, EventKeyOwner, EventKeyOrder, EventKeyNone} {
+			So(validEventKey(key), ShouldBeTrue)
+		}
+		So(validEventKey("taker"), ShouldBeFalse)
+	})
+}
+
+func TestEventHeaders(t *testing.T) {
+	Convey("Given an event generated by a command read from the input topic", t, func() {
+		ev := model.NewBBOEvent(7, "btcusd", 100, 1, 110, 1)
+		command := engine.NewEvent(net.Message{Topic: "engine.orders.btcusd", Partition: 2, Offset: 42})
+
+		Convey("Its headers should include the type, the sequence id and the input position", func() {
+			headers := headerMap(eventHeaders("btcusd", &ev, &command))
+			So(headers, ShouldResemble, map[string]string{
+				HeaderEventType:      "BestBidOffer",
+				HeaderMarket:         "btcusd",
+				HeaderSeqID:          "7",
+				HeaderSchemaVersion:  "1",
+				HeaderInputTopic:     "engine.orders.btcusd",
+				HeaderInputPartition: "2",
+				HeaderInputOffset:    "42",
+			})
+		})
+
+		Convey("A replayed event should be marked", func() {
+			ev.Replay = true
+			So(headerMap(eventHeaders("btcusd", &ev, &command))[HeaderReplay], ShouldEqual, "true")
+		})
+
+		Convey("A command received directly should have no input position", func() {
+			direct := engine.NewEvent(net.Message{})
+			headers := headerMap(eventHeaders("btcusd", &ev, &direct))
+			So(headers, ShouldNotContainKey, HeaderInputTopic)
+			So(headers, ShouldNotContainKey, HeaderInputOffset)
+			So(headers, ShouldNotContainKey, HeaderReplay)
+		})
+
+		Convey("The headers of a batch should include the sequence ids of its first and last events", func() {
+			other := model.NewBBOEvent(8, "btcusd", 100, 1, 105, 1)
+			batch := model.NewEvents("btcusd", "engine.orders.btcusd", 2, 42, []*model.Event{&ev, &other})
+			headers := headerMap(batchHeaders(batch, &command))
+			So(headers[HeaderEventType], ShouldEqual, BatchEventType)
+			So(headers[HeaderFirstSeqID], ShouldEqual, "7")
+			So(headers[HeaderLastSeqID], ShouldEqual, "8")
+			So(headers[HeaderInputOffset], ShouldEqual, "42")
+		})
+	})
+}
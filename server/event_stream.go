@@ -0,0 +1,144 @@
+package server
+
+import (
+	"errors"
+	"sync"
+
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+// DefaultEventHistory is the default number of recent events kept to resume subscriptions
+const DefaultEventHistory = 10000
+
+// subscriberBuffer is the number of event batches that can wait to be sent to a subscriber before it is dropped
+const subscriberBuffer = 1000
+
+// ErrEventsUnavailable is returned when the events requested by a subscription are no longer kept in memory
+var ErrEventsUnavailable = errors.New("requested events are no longer available")
+
+// EventSubscription receives the events published by a market after it was created
+type EventSubscription struct {
+	// Backlog contains the kept events generated after the requested sequence id
+	Backlog []*model.Event
+	// Events receives the batches of events generated by each command. It is closed when the subscriber was
+	// dropped for not keeping up with the market or when the subscription was closed.
+	Events  <-chan []*model.Event
+	events  chan []*model.Event
+	stream  *eventStream
+	dropped bool
+}
+
+// Dropped returns true if the subscription was closed because the subscriber did not keep up with the market
+func (sub *EventSubscription) Dropped() bool {
+	sub.stream.lock.Lock()
+	defer sub.stream.lock.Unlock()
+	return sub.dropped
+}
+
+// Close stops the subscription
+func (sub *EventSubscription) Close() {
+	sub.stream.lock.Lock()
+	defer sub.stream.lock.Unlock()
+	if _, ok := sub.stream.subscribers[sub]; ok {
+		delete(sub.stream.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// eventStream keeps the recent events of a market and forwards the new ones to the subscribers
+type eventStream struct {
+	lock        sync.Mutex
+	history     []*model.Event
+	size        int
+	lastSeqID   uint64
+	subscribers map[*EventSubscription]bool
+}
+
+// newEventStream creates a new stream that keeps the given number of recent events
+func newEventStream(size int) *eventStream {
+	if size <= 0 {
+		size = DefaultEventHistory
+	}
+	return &eventStream{
+		history:     make([]*model.Event, 0, size),
+		size:        size,
+		subscribers: make(map[*EventSubscription]bool),
+	}
+}
+
+// Reset sets the sequence id of the last event generated by the market when it is loaded from a backup
+func (stream *eventStream) Reset(lastSeqID uint64) {
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+	stream.history = stream.history[:0]
+	stream.lastSeqID = lastSeqID
+}
+
+// Publish adds the events generated by a command to the history and sends them to the subscribers
+// - Replayed acknowledgements are only sent to the caller of the command since they are out of sequence
+// - Subscribers that have too many batches waiting to be sent are dropped
+func (stream *eventStream) Publish(events []*model.Event) {
+	events = sequencedEvents(events)
+	if len(events) == 0 {
+		return
+	}
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+	for _, event := range events {
+		if len(stream.history) == stream.size {
+			copy(stream.history, stream.history[1:])
+			stream.history = stream.history[:stream.size-1]
+		}
+		stream.history = append(stream.history, event)
+	}
+	stream.lastSeqID = events[len(events)-1].SeqID
+	for sub := range stream.subscribers {
+		select {
+		case sub.events <- events:
+		default:
+			sub.dropped = true
+			delete(stream.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Subscribe creates a subscription for the events generated after the given sequence id
+// - A zero sequence id only subscribes to the new events
+func (stream *eventStream) Subscribe(fromSeqID uint64) (*EventSubscription, error) {
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+	backlog := make([]*model.Event, 0)
+	if fromSeqID != 0 && fromSeqID < stream.lastSeqID {
+		if len(stream.history) == 0 || stream.history[0].SeqID > fromSeqID+1 {
+			return nil, ErrEventsUnavailable
+		}
+		for _, event := range stream.history {
+			if event.SeqID > fromSeqID {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+	events := make(chan []*model.Event, subscriberBuffer)
+	sub := &EventSubscription{Backlog: backlog, Events: events, events: events, stream: stream}
+	stream.subscribers[sub] = true
+	return sub, nil
+}
+
+// sequencedEvents removes the replayed events that are not part of the sequence of the market
+func sequencedEvents(events []*model.Event) []*model.Event {
+	for i, event := range events {
+		if !event.Replay {
+			continue
+		}
+		sequenced := make([]*model.Event, i, len(events))
+		copy(sequenced, events[:i])
+		for _, event := range events[i+1:] {
+			if !event.Replay {
+				sequenced = append(sequenced, event)
+			}
+		}
+		return sequenced
+	}
+	return events
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// newStreamEvents creates a batch of events with consecutive sequence ids
+func newStreamEvents(from, to uint64) []*model.Event {
+	events := make([]*model.Event, 0, to-from+1)
+	for seqID := from; seqID <= to; seqID++ {
+		event := model.NewBBOEvent(seqID, "btcusd", 0, 0, 100, seqID)
+		events = append(events, &event)
+	}
+	return events
+}
+
+func eventSeqIDs(events []*model.Event) []uint64 {
+	seqIDs := make([]uint64, len(events))
+	for i, event := range events {
+		seqIDs[i] = event.SeqID
+	}
+	return seqIDs
+}
+
+func TestEventStream(t *testing.T) {
+	Convey("Given a stream that keeps the last 4 events", t, func() {
+		stream := newEventStream(4)
+		stream.Publish(newStreamEvents(1, 3))
+		stream.Publish(newStreamEvents(4, 6))
+
+		Convey("A subscription should receive the kept events after the requested sequence id", func() {
+			sub, err := stream.Subscribe(3)
+			So(err, ShouldBeNil)
+			defer sub.Close()
+			So(eventSeqIDs(sub.Backlog), ShouldResemble, []uint64{4, 5, 6})
+
+			Convey("and then the new events of the market", func() {
+				stream.Publish(newStreamEvents(7, 8))
+				So(eventSeqIDs(<-sub.Events), ShouldResemble, []uint64{7, 8})
+			})
+		})
+
+		Convey("A subscription from events that are no longer kept should fail", func() {
+			_, err := stream.Subscribe(1)
+			So(err, ShouldEqual, ErrEventsUnavailable)
+			sub, err := stream.Subscribe(2)
+			So(err, ShouldBeNil)
+			So(eventSeqIDs(sub.Backlog), ShouldResemble, []uint64{3, 4, 5, 6})
+		})
+
+		Convey("A subscription from zero or the last event should only receive the new events", func() {
+			sub, err := stream.Subscribe(0)
+			So(err, ShouldBeNil)
+			So(sub.Backlog, ShouldBeEmpty)
+			sub, err = stream.Subscribe(6)
+			So(err, ShouldBeNil)
+			So(sub.Backlog, ShouldBeEmpty)
+		})
+
+		Convey("A subscriber that does not keep up should be dropped", func() {
+			slow, _ := stream.Subscribe(0)
+			fast, _ := stream.Subscribe(0)
+			for i := uint64(0); i <= subscriberBuffer; i++ {
+				stream.Publish(newStreamEvents(7+i, 7+i))
+				if i < subscriberBuffer {
+					<-fast.Events
+				}
+			}
+			So(slow.Dropped(), ShouldBeTrue)
+			count := 0
+			for range slow.Events {
+				count++
+			}
+			So(count, ShouldEqual, subscriberBuffer)
+			So(fast.Dropped(), ShouldBeFalse)
+			So(eventSeqIDs(<-fast.Events), ShouldResemble, []uint64{7 + subscriberBuffer})
+		})
+
+		Convey("A closed subscription should no longer receive events", func() {
+			sub, _ := stream.Subscribe(0)
+			sub.Close()
+			stream.Publish(newStreamEvents(7, 7))
+			_, more := <-sub.Events
+			So(more, ShouldBeFalse)
+			So(sub.Dropped(), ShouldBeFalse)
+		})
+
+		Convey("Replayed acknowledgements should not be kept or sent to the subscribers", func() {
+			sub, _ := stream.Subscribe(0)
+			events := newStreamEvents(7, 8)
+			replay := model.NewOrderStatusEvent(2, "btcusd", model.OrderType_Limit, model.MarketSide_Buy, 1, 10, "", 100, 1, 0, model.OrderStatus_Untouched, 0, 0)
+			replay.Replay = true
+			stream.Publish([]*model.Event{events[0], &replay, events[1]})
+			So(eventSeqIDs(<-sub.Events), ShouldResemble, []uint64{7, 8})
+			stream.Publish([]*model.Event{&replay})
+			next, _ := stream.Subscribe(6)
+			So(eventSeqIDs(next.Backlog), ShouldResemble, []uint64{7, 8})
+		})
+
+		Convey("Resetting the stream from a backup should clear the kept events", func() {
+			stream.Reset(100)
+			_, err := stream.Subscribe(50)
+			So(err, ShouldEqual, ErrEventsUnavailable)
+			sub, err := stream.Subscribe(100)
+			So(err, ShouldBeNil)
+			So(sub.Backlog, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// topic returns the topic configured for the type of event
+// - An empty topic means that the events of the type are published on the topic of the market
+func (cfg EventTopicsConfig) topic(eventType model.EventType) TopicConfig {
+	switch eventType {
+	case model.EventType_NewTrade, model.EventType_TradeBusted, model.EventType_TradeCorrected:
+		return cfg.Trades
+	case model.EventType_OrderStatusChange:
+		return cfg.OrderStatus
+	case model.EventType_OrderActivated:
+		return cfg.Activations
+	case model.EventType_Error:
+		return cfg.Errors
+	case model.EventType_BestBidOffer:
+		return cfg.BBO
+	}
+	return TopicConfig{}
+}
+
+// newEventProducers creates the producers of the types of events published on their own topic
+// - Types configured with the same topic share a producer
+func newEventProducers(t *transports, transport string, brokers map[string]ProducerConfig, cfg MarketConfig) map[model.EventType]net.Producer {
+	eventTypes := []model.EventType{
+		model.EventType_NewTrade,
+		model.EventType_TradeBusted,
+		model.EventType_TradeCorrected,
+		model.EventType_OrderStatusChange,
+		model.EventType_OrderActivated,
+		model.EventType_Error,
+		model.EventType_BestBidOffer,
+	}
+	producers := make(map[model.EventType]net.Producer)
+	byTopic := make(map[TopicConfig]net.Producer)
+	for _, eventType := range eventTypes {
+		topic := cfg.EventTopics.topic(eventType)
+		if topic.Topic == "" || topic == cfg.Publish {
+			continue
+		}
+		producer, ok := byTopic[topic]
+		if !ok {
+			producer = t.producer(transport, brokers[topic.Broker], topic.Topic)
+			byTopic[topic] = producer
+		}
+		producers[eventType] = producer
+	}
+	return producers
+}
+
+// eventProducer returns the producer of the topic on which the type of event is published
+func (mkt *marketEngine) eventProducer(eventType model.EventType) net.Producer {
+	if producer, ok := mkt.config.eventProducers[eventType]; ok {
+		return producer
+	}
+	return mkt.producer
+}
+
+// eventTopic returns the name of the topic on which the type of event is published
+func (mkt *marketEngine) eventTopic(eventType model.EventType) string {
+	if _, ok := mkt.config.eventProducers[eventType]; ok {
+		return mkt.config.config.EventTopics.topic(eventType).Topic
+	}
+	return mkt.config.config.Publish.Topic
+}
+
+// unpublishedEvents removes the events already written on the topic before the market was restored
+// - The events regenerated by the replay of the input keep their sequence ids, so the ones up to the last sequence id
+// published on the topic were already received by its consumers
+// - The replayed events of duplicate commands are always published since they carry the sequence ids of the original
+func (mkt *marketEngine) unpublishedEvents(topic string, events []*model.Event) []*model.Event {
+	lastSeqID := mkt.output.topicSeqID(topic)
+	if lastSeqID == 0 {
+		return events
+	}
+	unpublished := make([]*model.Event, 0, len(events))
+	for _, ev := range events {
+		if ev.Replay || ev.SeqID > lastSeqID {
+			unpublished = append(unpublished, ev)
+		}
+	}
+	return unpublished
+}
+
+// lastEventSeqID returns the sequence id of the last event generated by the command, ignoring the replayed ones
+func lastEventSeqID(events []*model.Event) uint64 {
+	var seqID uint64
+	for _, ev := range events {
+		if !ev.Replay {
+			seqID = ev.SeqID
+		}
+	}
+	return seqID
+}
+
+// groupEventsByProducer splits the events of a command by the producer of their topic keeping their order
+// - The producers are returned in the order of their first event
+func (mkt *marketEngine) groupEventsByProducer(events []model.Event) ([]net.Producer, map[net.Producer][]*model.Event) {
+	producers := make([]net.Producer, 0, 1)
+	groups := make(map[net.Producer][]*model.Event)
+	for i := range events {
+		producer := mkt.eventProducer(events[i].Type)
+		if _, ok := groups[producer]; !ok {
+			producers = append(producers, producer)
+		}
+		groups[producer] = append(groups[producer], &events[i])
+	}
+	return producers, groups
+}
+
+// eventMessages encodes the events of a command published on the same topic
+// - With the batch output all of them are sent as a single Events message
+// - The batch only holds the events of the topic, so with event topics a command is split in one batch per topic.
+// The batches of a command share the input position and consumers of several topics join them by it.
+func (mkt *marketEngine) eventMessages(event *engine.Event, events []*model.Event) []net.Message {
+	if mkt.config.batchOutput {
+		// the events of the command on this topic are published in a single message so consumers can apply them atomically
+		batch := model.NewEvents(mkt.name, event.Msg.Topic, int32(event.Msg.Partition), event.Msg.Offset, events)
+		rawBatch, _ := batch.ToBinary()
+		return []net.Message{{
+			Key:     batchKey(mkt.config.config.EventKey, mkt.name, &event.Order),
+			Value:   rawBatch,
+			Headers: batchHeaders(batch, event),
+		}}
+	}
+	msgs := make([]net.Message, len(events))
+	for i, ev := range events {
+		rawEvent, _ := ev.ToBinary() // @todo add better error handling on encoding
+		msgs[i] = net.Message{
+			Key:     eventKey(mkt.config.config.EventKey, mkt.name, ev),
+			Value:   rawEvent,
+			Headers: eventHeaders(mkt.name, ev, event),
+		}
+	}
+	return msgs
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEventTopics(t *testing.T) {
+	Convey("Given a market with the trades and the errors on the same topic and the bbo on the market topic", t, func() {
+		cfg := MarketConfig{MarketID: "btcusd", Publish: TopicConfig{Topic: "engine.events.btcusd"}}
+		cfg.EventTopics.Trades = TopicConfig{Topic: "engine.trades.btcusd"}
+		cfg.EventTopics.Errors = TopicConfig{Topic: "engine.trades.btcusd"}
+		cfg.EventTopics.BBO = TopicConfig{Topic: "engine.events.btcusd"}
+		transports := newTransports(Config{})
+		producers := newEventProducers(transports, TransportMemory, nil, cfg)
+
+		Convey("Only the types with their own topic should have a producer", func() {
+			So(producers, ShouldHaveLength, 4)
+			So(producers, ShouldContainKey, model.EventType_NewTrade)
+			So(producers, ShouldContainKey, model.EventType_TradeBusted)
+			So(producers, ShouldContainKey, model.EventType_TradeCorrected)
+			So(producers, ShouldContainKey, model.EventType_Error)
+			So(producers, ShouldNotContainKey, model.EventType_BestBidOffer)
+			So(producers, ShouldNotContainKey, model.EventType_OrderStatusChange)
+		})
+
+		Convey("Types with the same topic should share a producer", func() {
+			So(producers[model.EventType_Error], ShouldEqual, producers[model.EventType_NewTrade])
+		})
+
+		Convey("The events of a command should be grouped by producer in the order of their first event", func() {
+			marketProducer := net.NewMemoryProducer(net.NewMemoryTopic("engine.events.btcusd"))
+			mkt := &marketEngine{producer: marketProducer, config: MarketEngineConfig{eventProducers: producers}}
+			events := []model.Event{
+				model.NewOrderStatusEvent(1, "btcusd", model.OrderType_Limit, model.MarketSide_Buy, 2, 2, "", 100, 1, 0, model.OrderStatus_Untouched, 0, 0),
+				model.NewTradeEvent(2, "btcusd", 1, model.MarketSide_Buy, 1, 2, 1, 2, "", "", 1, 100),
+				model.NewOrderStatusEvent(3, "btcusd", model.OrderType_Limit, model.MarketSide_Buy, 2, 2, "", 100, 1, 1, model.OrderStatus_Filled, 1, 100),
+			}
+			order, groups := mkt.groupEventsByProducer(events)
+			So(order, ShouldResemble, []net.Producer{marketProducer, producers[model.EventType_NewTrade]})
+			So(groups[marketProducer], ShouldResemble, []*model.Event{&events[0], &events[2]})
+			So(groups[producers[model.EventType_NewTrade]], ShouldResemble, []*model.Event{&events[1]})
+		})
+	})
+
+	Convey("Given a market that publishes its trades on their own topic as a batch", t, func() {
+		dir, err := ioutil.TempDir("", "event_topics")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		input := net.NewMemoryTopic("engine.orders.btcusd")
+		output := net.NewMemoryTopic("engine.events.btcusd")
+		trades := net.NewMemoryTopic("engine.trades.btcusd")
+		tradeProducer := net.NewMemoryProducer(trades)
+		mkt := startTestMarket(dir, input, output, MarketEngineConfig{batchOutput: true, eventProducers: map[model.EventType]net.Producer{
+			model.EventType_NewTrade: tradeProducer,
+		}})
+		defer mkt.Close()
+		defer mkt.consumer.Close()
+
+		writeTestOrders(input, newTestOrder(1, model.MarketSide_Sell), newTestOrder(2, model.MarketSide_Buy))
+
+		Convey("The batch of a matching command should be split between the topics with the same input position", func() {
+			msgs := waitMessages(output, 2)
+			tradeMsgs := waitMessages(trades, 1)
+			So(msgs, ShouldHaveLength, 2)
+			So(tradeMsgs, ShouldHaveLength, 1)
+			var statuses, tradeBatch model.Events
+			So(statuses.FromBinary(msgs[1].Value), ShouldBeNil)
+			So(tradeBatch.FromBinary(tradeMsgs[0].Value), ShouldBeNil)
+			So(tradeBatch.Events, ShouldHaveLength, 1)
+			So(tradeBatch.Events[0].Type, ShouldEqual, model.EventType_NewTrade)
+			So(statuses.Events, ShouldHaveLength, 3)
+			for _, ev := range statuses.Events {
+				So(ev.Type, ShouldNotEqual, model.EventType_NewTrade)
+			}
+			So(tradeBatch.Topic, ShouldEqual, statuses.Topic)
+			So(tradeBatch.Offset, ShouldEqual, statuses.Offset)
+			So(headerValue(tradeMsgs[0], HeaderInputOffset), ShouldEqual, "1")
+
+			Convey("and together the batches should hold every sequence id of the command", func() {
+				seqIDs := map[uint64]bool{}
+				for _, ev := range append(statuses.Events, tradeBatch.Events...) {
+					seqIDs[ev.SeqID] = true
+				}
+				So(seqIDs, ShouldResemble, map[uint64]bool{2: true, 3: true, 4: true, 5: true})
+			})
+		})
+	})
+}
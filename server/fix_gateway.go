@@ -0,0 +1,386 @@
+package server
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/fix"
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+// fixSessionQueue is the number of application messages of a session that can wait to be processed
+const fixSessionQueue = 10000
+
+// Delays between two attempts to subscribe to the events of a market, doubled after each failure
+const (
+	fixSubscribeBackoff    = 100 * time.Millisecond
+	fixSubscribeMaxBackoff = 10 * time.Second
+)
+
+// fixOrderKey identifies an order of an owner by its client order id
+type fixOrderKey struct {
+	OwnerID uint64
+	ClOrdID string
+}
+
+// fixCancel is a cancel request waiting for the cancellation of the original order
+type fixCancel struct {
+	ClOrdID string
+	// the cancellation is part of a cancel/replace request and is reported by the Replaced report of the new order
+	Replace bool
+}
+
+// fixGateway maps the messages received on the FIX sessions to commands and the events of the markets to
+// execution reports
+// - Each session sends the orders of a single owner and logs on with the credentials configured for it
+// - Commands are written on the input topic of the markets like the commands received over gRPC
+// - The order ids are allocated from the range reserved for the gateway in the configuration
+type fixGateway struct {
+	config   FIXConfig
+	markets  map[string]MarketEngine
+	configs  map[string]MarketConfig
+	acceptor *fix.Acceptor
+	timeout  time.Duration
+	orderIDs *fixOrderIDs
+
+	sessions map[uint64]*fix.Session
+	owners   map[fix.SessionID]uint64
+	queues   map[fix.SessionID]chan *fix.Message
+
+	lock sync.Mutex
+	// cancel requests waiting for the cancellation of the original order
+	cancels map[fixOrderKey]fixCancel
+	// original client order ids of the replacement orders waiting for their first report
+	replaces map[fixOrderKey]string
+	// closed when the gateway is stopped
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newFIXGateway creates the FIX sessions configured for the server
+func newFIXGateway(config Config, markets map[string]MarketEngine) *fixGateway {
+	fixConfig := config.Server.FIX
+	timeout := fixConfig.Timeout
+	if timeout <= 0 {
+		timeout = DefaultGRPCTimeout
+	}
+	gateway := &fixGateway{
+		config:   fixConfig,
+		markets:  markets,
+		configs:  config.Markets,
+		acceptor: fix.NewAcceptor(fixConfig.Host + ":" + fixConfig.Port),
+		timeout:  time.Duration(timeout) * time.Millisecond,
+		sessions: make(map[uint64]*fix.Session),
+		owners:   make(map[fix.SessionID]uint64),
+		queues:   make(map[fix.SessionID]chan *fix.Message),
+		cancels:  make(map[fixOrderKey]fixCancel),
+		replaces: make(map[fixOrderKey]string),
+		done:     make(chan struct{}),
+	}
+	if err := os.MkdirAll(fixConfig.StorePath, 0755); err != nil {
+		log.Fatal().Err(err).Str("section", "init:fix").Str("action", "create_store").Str("path", fixConfig.StorePath).Msg("Unable to create FIX store directory")
+	}
+	firstID, lastID := fixConfig.FirstOrderID, fixConfig.LastOrderID
+	if firstID == 0 {
+		firstID = DefaultFIXFirstOrderID
+	}
+	if lastID == 0 {
+		lastID = DefaultFIXLastOrderID
+	}
+	orderIDs, err := newFIXOrderIDs(fixOrderIDsPath(fixConfig), firstID, lastID)
+	if err != nil {
+		log.Fatal().Err(err).Str("section", "init:fix").Str("action", "load_order_ids").Msg("Unable to load FIX order ids")
+	}
+	gateway.orderIDs = orderIDs
+	for _, sessionCfg := range fixConfig.Sessions {
+		id := fix.SessionID{SenderCompID: fixConfig.SenderCompID, TargetCompID: sessionCfg.TargetCompID}
+		if sessionCfg.Username == "" || sessionCfg.Password == "" {
+			log.Fatal().Str("section", "init:fix").Str("action", "load_session").Str("session", id.String()).Msg("FIX session requires a username and a password")
+		}
+		store, err := fix.NewFileStore(fixConfig.StorePath, id.String(), fixConfig.StoreLimit)
+		if err != nil {
+			log.Fatal().Err(err).Str("section", "init:fix").Str("action", "load_store").Str("session", id.String()).Msg("Unable to load FIX session store")
+		}
+		session := fix.NewSession(id, store, gateway)
+		session.SetCredentials(sessionCfg.Username, sessionCfg.Password)
+		gateway.sessions[sessionCfg.OwnerID] = session
+		gateway.owners[id] = sessionCfg.OwnerID
+		gateway.queues[id] = make(chan *fix.Message, fixSessionQueue)
+		gateway.acceptor.AddSession(session)
+	}
+	return gateway
+}
+
+// Start processing the messages of the sessions and listening for connections
+func (gateway *fixGateway) Start() error {
+	for _, session := range gateway.sessions {
+		go gateway.processMessages(session, gateway.queues[session.ID])
+	}
+	for name, market := range gateway.markets {
+		go gateway.streamReports(name, market)
+	}
+	return gateway.acceptor.Start()
+}
+
+// Stop listening for connections and streaming the execution reports
+func (gateway *fixGateway) Stop() {
+	gateway.stopOnce.Do(func() {
+		close(gateway.done)
+		if err := gateway.acceptor.Stop(); err != nil {
+			log.Warn().Err(err).Str("section", "fix").Str("action", "stop").Msg("Unable to stop FIX acceptor")
+		}
+	})
+}
+
+// OnLogon is called when a client logs on to a session
+func (gateway *fixGateway) OnLogon(session *fix.Session) {}
+
+// OnLogout is called when the connection of a session is closed
+func (gateway *fixGateway) OnLogout(session *fix.Session) {}
+
+// FromApp queues the application messages of a session so they are processed in order without blocking the session
+func (gateway *fixGateway) FromApp(session *fix.Session, msg *fix.Message) {
+	gateway.queues[session.ID] <- msg
+}
+
+// processMessages sends the commands received on a session to the markets one at a time
+func (gateway *fixGateway) processMessages(session *fix.Session, queue chan *fix.Message) {
+	ownerID := gateway.owners[session.ID]
+	for msg := range queue {
+		switch msg.Type() {
+		case fix.MsgTypeNewOrderSingle:
+			gateway.newOrder(session, ownerID, msg, "")
+		case fix.MsgTypeOrderCancelRequest:
+			gateway.cancelOrder(session, ownerID, msg, fix.CxlRejResponseToCancel)
+		case fix.MsgTypeOrderCancelReplaceRequest:
+			if gateway.cancelOrder(session, ownerID, msg, fix.CxlRejResponseToReplace) {
+				gateway.newOrder(session, ownerID, msg, msg.Get(fix.TagOrigClOrdID))
+			}
+		case fix.MsgTypeOrderMassCancelRequest:
+			gateway.massCancel(session, ownerID, msg)
+		default:
+			session.Send(fix.NewBusinessMessageReject(msg, fix.BusinessRejectReasonUnsupportedMsgType, "Unsupported message type"))
+		}
+	}
+}
+
+// newOrder sends a new order to its market and rejects it if it cannot be mapped to a command
+// - The replaced client order id is set for the replacement orders of cancel/replace requests
+func (gateway *fixGateway) newOrder(session *fix.Session, ownerID uint64, msg *fix.Message, replaces string) {
+	market, ok := gateway.markets[msg.Get(fix.TagSymbol)]
+	if !ok {
+		session.Send(fix.NewRejectedReport(msg, model.ErrorCode_UnknownMarket.String()))
+		return
+	}
+	marketCfg := gateway.configs[msg.Get(fix.TagSymbol)]
+	order, err := fix.NewOrderFromMessage(msg, ownerID, marketCfg.PricePrecision, marketCfg.VolumePrecision)
+	if err != nil {
+		session.Send(fix.NewRejectedReport(msg, err.Error()))
+		return
+	}
+	if order.ID, err = gateway.orderIDs.Next(); err != nil {
+		log.Error().Err(err).Str("section", "fix").Str("action", "new_order").Str("session", session.ID.String()).Msg("Unable to allocate order id")
+		session.Send(fix.NewRejectedReport(msg, err.Error()))
+		return
+	}
+	if replaces != "" {
+		gateway.lock.Lock()
+		gateway.replaces[fixOrderKey{OwnerID: ownerID, ClOrdID: order.ClientOrderID}] = replaces
+		gateway.lock.Unlock()
+	}
+	if _, err := gateway.submit(market, order); err != nil {
+		log.Error().Err(err).Str("section", "fix").Str("action", "new_order").Str("session", session.ID.String()).Msg("Unable to submit order")
+		session.Send(fix.NewRejectedReport(msg, err.Error()))
+	}
+}
+
+// cancelOrder sends a cancel request for the original order of the message and returns true if it was cancelled
+func (gateway *fixGateway) cancelOrder(session *fix.Session, ownerID uint64, msg *fix.Message, responseTo string) bool {
+	market, ok := gateway.markets[msg.Get(fix.TagSymbol)]
+	if !ok {
+		session.Send(fix.NewOrderCancelReject(msg, responseTo, fix.CxlRejReasonOther, model.ErrorCode_UnknownMarket.String()))
+		return false
+	}
+	order, err := fix.CancelOrderFromMessage(msg, ownerID)
+	if err != nil {
+		session.Send(fix.NewOrderCancelReject(msg, responseTo, fix.CxlRejReasonOther, err.Error()))
+		return false
+	}
+	key := fixOrderKey{OwnerID: ownerID, ClOrdID: order.ClientOrderID}
+	gateway.lock.Lock()
+	gateway.cancels[key] = fixCancel{ClOrdID: msg.Get(fix.TagClOrdID), Replace: responseTo == fix.CxlRejResponseToReplace}
+	gateway.lock.Unlock()
+
+	events, err := gateway.submit(market, order)
+	if err != nil {
+		log.Error().Err(err).Str("section", "fix").Str("action", "cancel_order").Str("session", session.ID.String()).Msg("Unable to submit cancel request")
+		gateway.forgetCancel(key)
+		session.Send(fix.NewOrderCancelReject(msg, responseTo, fix.CxlRejReasonOther, err.Error()))
+		return false
+	}
+	for _, event := range events {
+		if event.Type == model.EventType_Error {
+			gateway.forgetCancel(key)
+			reason := fix.CxlRejReasonOther
+			if code := event.GetError().Code; code == model.ErrorCode_UnknownOrder || code == model.ErrorCode_CancelFailed {
+				reason = fix.CxlRejReasonUnknownOrder
+			}
+			session.Send(fix.NewOrderCancelReject(msg, responseTo, reason, event.GetError().Code.String()))
+			return false
+		}
+	}
+	return true
+}
+
+// massCancel cancels the open orders of the owner for a market or for all markets
+// - The orders are resolved by the markets so the orders sent through the other inputs are cancelled as well
+func (gateway *fixGateway) massCancel(session *fix.Session, ownerID uint64, msg *fix.Message) {
+	requestType := msg.Get(fix.TagMassCancelRequestType)
+	symbol := msg.Get(fix.TagSymbol)
+	report := fix.NewMessage(fix.MsgTypeOrderMassCancelReport).
+		Set(fix.TagOrderID, msg.Get(fix.TagClOrdID)).
+		Set(fix.TagClOrdID, msg.Get(fix.TagClOrdID)).
+		Set(fix.TagMassCancelRequestType, requestType)
+	if symbol != "" {
+		report.Set(fix.TagSymbol, symbol)
+	}
+	markets := make([]string, 0, len(gateway.markets))
+	switch requestType {
+	case fix.MassCancelRequestTypeAll:
+		for name := range gateway.markets {
+			markets = append(markets, name)
+		}
+	case fix.MassCancelRequestTypeSecurity:
+		if _, ok := gateway.markets[symbol]; !ok {
+			session.Send(report.Set(fix.TagMassCancelResponse, fix.MassCancelResponseRejected).Set(fix.TagText, model.ErrorCode_UnknownMarket.String()))
+			return
+		}
+		markets = append(markets, symbol)
+	default:
+		session.Send(report.Set(fix.TagMassCancelResponse, fix.MassCancelResponseRejected).Set(fix.TagText, "Unsupported mass cancel request type"))
+		return
+	}
+
+	affected := 0
+	for _, name := range markets {
+		id, err := gateway.orderIDs.Next()
+		if err != nil {
+			log.Error().Err(err).Str("section", "fix").Str("action", "mass_cancel").Str("session", session.ID.String()).Msg("Unable to allocate order id")
+			session.Send(report.Set(fix.TagMassCancelResponse, fix.MassCancelResponseRejected).Set(fix.TagText, err.Error()))
+			return
+		}
+		command := &model.Order{ID: id, EventType: model.CommandType_CancelAll, Market: name, OwnerID: ownerID}
+		events, err := gateway.submit(gateway.markets[name], command)
+		if err != nil {
+			log.Error().Err(err).Str("section", "fix").Str("action", "mass_cancel").Str("session", session.ID.String()).Str("market", name).Msg("Unable to submit mass cancel request")
+			session.Send(report.Set(fix.TagMassCancelResponse, fix.MassCancelResponseRejected).Set(fix.TagText, err.Error()))
+			return
+		}
+		for _, event := range events {
+			if event.Type == model.EventType_OrderStatusChange && event.GetOrderStatus().Reason == model.CancelReason_MassCancel {
+				affected++
+			}
+		}
+	}
+	session.Send(report.Set(fix.TagMassCancelResponse, requestType).SetInt(fix.TagTotalAffectedOrders, affected))
+}
+
+// submit sends the command to the market and waits for the generated events
+func (gateway *fixGateway) submit(market MarketEngine, order *model.Order) ([]*model.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gateway.timeout)
+	defer cancel()
+	return market.SubmitCommand(ctx, order)
+}
+
+func (gateway *fixGateway) forgetCancel(key fixOrderKey) {
+	gateway.lock.Lock()
+	delete(gateway.cancels, key)
+	gateway.lock.Unlock()
+}
+
+func (gateway *fixGateway) isOwner(ownerID uint64) bool {
+	_, ok := gateway.sessions[ownerID]
+	return ok
+}
+
+// streamReports sends the execution reports for the events of a market to the sessions of the owners
+// - A failed subscription is retried with an exponential backoff until the gateway is stopped
+func (gateway *fixGateway) streamReports(name string, market MarketEngine) {
+	marketCfg := gateway.configs[name]
+	lastSeqID := uint64(0)
+	backoff := fixSubscribeBackoff
+	for {
+		sub, err := market.SubscribeEvents(lastSeqID)
+		if err != nil {
+			log.Error().Err(err).Str("section", "fix").Str("action", "subscribe").Str("market", name).Uint64("seqid", lastSeqID).Dur("retry_in", backoff).Msg("Unable to resume the events of the market, some execution reports were lost")
+			lastSeqID = 0
+			select {
+			case <-time.After(backoff):
+			case <-gateway.done:
+				return
+			}
+			backoff *= 2
+			if backoff > fixSubscribeMaxBackoff {
+				backoff = fixSubscribeMaxBackoff
+			}
+			continue
+		}
+		backoff = fixSubscribeBackoff
+		gateway.sendReports(sub.Backlog, marketCfg)
+		for events := range sub.Events {
+			gateway.sendReports(events, marketCfg)
+			if len(events) > 0 {
+				lastSeqID = events[len(events)-1].SeqID
+			}
+		}
+		if !sub.Dropped() {
+			return
+		}
+		log.Warn().Str("section", "fix").Str("action", "subscribe").Str("market", name).Msg("Execution reports fell behind the market, resubscribing")
+	}
+}
+
+// sendReports maps the events of a command to execution reports and sends them to the sessions of the owners
+func (gateway *fixGateway) sendReports(events []*model.Event, marketCfg MarketConfig) {
+	for _, report := range fix.ExecutionReports(events, gateway.isOwner, marketCfg.PricePrecision, marketCfg.VolumePrecision) {
+		key := fixOrderKey{OwnerID: report.OwnerID, ClOrdID: report.ClOrdID}
+		msg := report.Message
+		gateway.lock.Lock()
+		if cancel, ok := gateway.cancels[key]; ok && msg.Get(fix.TagExecType) == fix.ExecTypeCanceled {
+			delete(gateway.cancels, key)
+			if cancel.Replace {
+				// a replaced order is only reported by the Replaced report of the replacement order
+				gateway.lock.Unlock()
+				continue
+			}
+			msg.Set(fix.TagOrigClOrdID, report.ClOrdID).Set(fix.TagClOrdID, cancel.ClOrdID)
+		}
+		if original, ok := gateway.replaces[key]; ok {
+			delete(gateway.replaces, key)
+			if msg.Get(fix.TagExecType) == fix.ExecTypeNew {
+				msg.Set(fix.TagExecType, fix.ExecTypeReplaced).Set(fix.TagOrigClOrdID, original)
+			}
+		}
+		gateway.lock.Unlock()
+		if err := gateway.sessions[report.OwnerID].Send(msg); err != nil {
+			log.Error().Err(err).Str("section", "fix").Str("action", "report").Uint64("owner_id", report.OwnerID).Msg("Unable to send execution report")
+		}
+	}
+}
+
+// loopFIXGateway starts the FIX acceptor when it is enabled and returns the gateway so it can be stopped
+func loopFIXGateway(config Config, markets map[string]MarketEngine) *fixGateway {
+	if !config.Server.FIX.Enabled {
+		return nil
+	}
+	gateway := newFIXGateway(config, markets)
+	if err := gateway.Start(); err != nil {
+		log.Fatal().Err(err).Str("section", "server").Str("action", "init").Str("goroutine", "server.fix").Msg("Unable to start FIX acceptor")
+	}
+	log.Debug().Str("section", "server").Str("action", "init").Str("goroutine", "server.fix").Str("address", gateway.acceptor.Addr().String()).Msg("Started FIX acceptor")
+	return gateway
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gitlab.com/around25/products/matching-engine/fix"
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// fixClient is the application of a FIX initiator that collects the messages received from the gateway
+type fixClient struct {
+	logons   chan bool
+	messages chan *fix.Message
+}
+
+func (client *fixClient) OnLogon(session *fix.Session)                   { client.logons <- true }
+func (client *fixClient) OnLogout(session *fix.Session)                  {}
+func (client *fixClient) FromApp(session *fix.Session, msg *fix.Message) { client.messages <- msg }
+
+// next waits for the next application message sent by the gateway
+func (client *fixClient) next() *fix.Message {
+	select {
+	case msg := <-client.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		return nil
+	}
+}
+
+// newFIXOrder returns a NewOrderSingle or the replacement order of an OrderCancelReplaceRequest
+func newFIXOrder(msgType, clOrdID, side, qty, price string) *fix.Message {
+	return fix.NewMessage(msgType).
+		Set(fix.TagClOrdID, clOrdID).
+		Set(fix.TagSymbol, "btcusd").
+		Set(fix.TagSide, side).
+		Set(fix.TagOrdType, fix.OrdTypeLimit).
+		Set(fix.TagOrderQty, qty).
+		Set(fix.TagPrice, price)
+}
+
+func TestFIXGateway(t *testing.T) {
+	Convey("Given a FIX initiator logged on to the gateway of a market", t, func() {
+		dir, err := ioutil.TempDir("", "fix_gateway")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		input := net.NewMemoryTopic("btcusd")
+		mkt := startTestMarket(dir, input, net.NewMemoryTopic("events"), MarketEngineConfig{commandProducer: net.NewMemoryProducer(input), eventHistory: 100})
+		defer mkt.Close()
+		defer mkt.consumer.Close()
+
+		config := Config{Markets: map[string]MarketConfig{"btcusd": mkt.config.config}}
+		config.Server.FIX = FIXConfig{
+			Host:         "127.0.0.1",
+			Port:         "0",
+			SenderCompID: "ENGINE",
+			StorePath:    filepath.Join(dir, "fix"),
+			Sessions:     []FIXSessionConfig{{TargetCompID: "CLIENT", OwnerID: 10, Username: "client", Password: "secret"}},
+		}
+		gateway := newFIXGateway(config, map[string]MarketEngine{"btcusd": mkt})
+		So(gateway.Start(), ShouldBeNil)
+		defer gateway.Stop()
+
+		client := &fixClient{logons: make(chan bool, 1), messages: make(chan *fix.Message, 10)}
+		initiator := fix.NewSession(fix.SessionID{SenderCompID: "CLIENT", TargetCompID: "ENGINE"}, fix.NewMemoryStore(), client)
+		initiator.SetCredentials("client", "secret")
+		So(initiator.Dial(gateway.acceptor.Addr().String(), time.Second, true), ShouldBeNil)
+		select {
+		case <-client.logons:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the initiator did not log on")
+		}
+
+		Convey("Orders should be reported through their whole lifecycle", func() {
+			So(initiator.Send(newFIXOrder(fix.MsgTypeNewOrderSingle, "A1", fix.SideSell, "1", "100")), ShouldBeNil)
+			report := client.next()
+			So(report.Type(), ShouldEqual, fix.MsgTypeExecutionReport)
+			So(report.Get(fix.TagExecType), ShouldEqual, fix.ExecTypeNew)
+			So(report.Get(fix.TagClOrdID), ShouldEqual, "A1")
+			orderID := report.Get(fix.TagOrderID)
+
+			So(initiator.Send(newFIXOrder(fix.MsgTypeNewOrderSingle, "B1", fix.SideBuy, "1", "90")), ShouldBeNil)
+			report = client.next()
+			So(report.Get(fix.TagExecType), ShouldEqual, fix.ExecTypeNew)
+			So(report.Get(fix.TagClOrdID), ShouldEqual, "B1")
+
+			// a cancel/replace is reported with a single Replaced report of the replacement order
+			So(initiator.Send(newFIXOrder(fix.MsgTypeOrderCancelReplaceRequest, "A2", fix.SideSell, "2", "101").Set(fix.TagOrigClOrdID, "A1")), ShouldBeNil)
+			report = client.next()
+			So(report.Type(), ShouldEqual, fix.MsgTypeExecutionReport)
+			So(report.Get(fix.TagExecType), ShouldEqual, fix.ExecTypeReplaced)
+			So(report.Get(fix.TagClOrdID), ShouldEqual, "A2")
+			So(report.Get(fix.TagOrigClOrdID), ShouldEqual, "A1")
+			So(report.Get(fix.TagOrderID), ShouldNotEqual, orderID)
+
+			// a cancel is reported with the client order id of the request and the one of the cancelled order
+			So(initiator.Send(fix.NewMessage(fix.MsgTypeOrderCancelRequest).
+				Set(fix.TagClOrdID, "C1").
+				Set(fix.TagOrigClOrdID, "B1").
+				Set(fix.TagSymbol, "btcusd").
+				Set(fix.TagSide, fix.SideBuy)), ShouldBeNil)
+			report = client.next()
+			So(report.Get(fix.TagExecType), ShouldEqual, fix.ExecTypeCanceled)
+			So(report.Get(fix.TagClOrdID), ShouldEqual, "C1")
+			So(report.Get(fix.TagOrigClOrdID), ShouldEqual, "B1")
+
+			// a cancel of an order that is no longer open is rejected
+			So(initiator.Send(fix.NewMessage(fix.MsgTypeOrderCancelRequest).
+				Set(fix.TagClOrdID, "C2").
+				Set(fix.TagOrigClOrdID, "A1").
+				Set(fix.TagSymbol, "btcusd").
+				Set(fix.TagSide, fix.SideSell)), ShouldBeNil)
+			report = client.next()
+			So(report.Type(), ShouldEqual, fix.MsgTypeOrderCancelReject)
+			So(report.Get(fix.TagClOrdID), ShouldEqual, "C2")
+			So(report.Get(fix.TagCxlRejReason), ShouldEqual, fix.CxlRejReasonUnknownOrder)
+
+			// a mass cancel reports the cancelled orders and the number of affected orders
+			So(initiator.Send(fix.NewMessage(fix.MsgTypeOrderMassCancelRequest).
+				Set(fix.TagClOrdID, "M1").
+				Set(fix.TagMassCancelRequestType, fix.MassCancelRequestTypeAll)), ShouldBeNil)
+			received := map[string]*fix.Message{}
+			for i := 0; i < 2; i++ {
+				msg := client.next()
+				So(msg, ShouldNotBeNil)
+				received[msg.Type()] = msg
+			}
+			So(received[fix.MsgTypeExecutionReport].Get(fix.TagExecType), ShouldEqual, fix.ExecTypeCanceled)
+			So(received[fix.MsgTypeExecutionReport].Get(fix.TagClOrdID), ShouldEqual, "A2")
+			So(received[fix.MsgTypeOrderMassCancelReport].Get(fix.TagClOrdID), ShouldEqual, "M1")
+			So(received[fix.MsgTypeOrderMassCancelReport].Get(fix.TagMassCancelResponse), ShouldEqual, fix.MassCancelRequestTypeAll)
+			So(received[fix.MsgTypeOrderMassCancelReport].Get(fix.TagTotalAffectedOrders), ShouldEqual, "1")
+		})
+	})
+
+	Convey("Failed subscriptions to the events of a market should be retried with a backoff until the gateway stops", t, func() {
+		market := &stubMarket{}
+		gateway := &fixGateway{done: make(chan struct{})}
+		stopped := make(chan bool)
+		go func() {
+			gateway.streamReports("btcusd", market)
+			stopped <- true
+		}()
+		time.Sleep(250 * time.Millisecond)
+		So(atomic.LoadInt32(&market.subscriptions), ShouldEqual, 2)
+		close(gateway.done)
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("the reports were still streamed after the gateway stopped")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultFIXFirstOrderID is the first order id of the range reserved for the FIX gateway
+const DefaultFIXFirstOrderID = uint64(1) << 62
+
+// DefaultFIXLastOrderID is the last order id of the range reserved for the FIX gateway
+const DefaultFIXLastOrderID = uint64(1)<<63 - 1
+
+// fixOrderIDBlock is the number of order ids reserved at once by the FIX gateway
+const fixOrderIDBlock = 10000
+
+// ErrOrderIDsExhausted is returned when all the order ids reserved for the FIX gateway were used
+var ErrOrderIDsExhausted = errors.New("the order ids reserved for the FIX gateway are exhausted")
+
+// fixOrderIDs allocates the ids of the commands sent by the FIX gateway from the range reserved for it
+// - The orders sent through the other inputs must use ids outside of the range
+// - The ids are reserved in blocks and the last reserved id is saved before any id of the block is used,
+// so the ids are never reused after a restart. The unused ids of the last block are skipped.
+type fixOrderIDs struct {
+	lock     sync.Mutex
+	path     string
+	next     uint64
+	reserved uint64
+	last     uint64
+}
+
+// newFIXOrderIDs loads the last reserved id from the given file and continues the range after it
+func newFIXOrderIDs(path string, first, last uint64) (*fixOrderIDs, error) {
+	if first == 0 || first > last {
+		return nil, fmt.Errorf("invalid FIX order id range %d-%d", first, last)
+	}
+	ids := &fixOrderIDs{path: path, next: first, reserved: first - 1, last: last}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ids, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FIX order id file %s: %w", path, err)
+	}
+	if reserved >= first {
+		ids.next = reserved + 1
+		ids.reserved = reserved
+	}
+	return ids, nil
+}
+
+// Next returns the next order id of the range, reserving a new block when the current one is used
+func (ids *fixOrderIDs) Next() (uint64, error) {
+	ids.lock.Lock()
+	defer ids.lock.Unlock()
+	if ids.next > ids.last || ids.next == 0 {
+		return 0, ErrOrderIDsExhausted
+	}
+	if ids.next > ids.reserved {
+		reserved := ids.next + fixOrderIDBlock - 1
+		if reserved > ids.last || reserved < ids.next {
+			reserved = ids.last
+		}
+		if err := ids.save(reserved); err != nil {
+			return 0, err
+		}
+		ids.reserved = reserved
+	}
+	id := ids.next
+	ids.next++
+	return id, nil
+}
+
+// save writes the last reserved id to a temporary file and renames it over the previous one
+func (ids *fixOrderIDs) save(reserved uint64) error {
+	tmp := ids.path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(strconv.FormatUint(reserved, 10) + "\n"); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, ids.path)
+}
+
+// fixOrderIDsPath returns the file where the FIX gateway keeps the last reserved order id
+func fixOrderIDsPath(config FIXConfig) string {
+	return filepath.Join(config.StorePath, config.SenderCompID+".orderids")
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFIXOrderIDs(t *testing.T) {
+	Convey("Given the order ids reserved for a FIX gateway", t, func() {
+		dir, err := ioutil.TempDir("", "fix_order_ids")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "ENGINE.orderids")
+
+		ids, err := newFIXOrderIDs(path, 1000, 1000+fixOrderIDBlock*2)
+		So(err, ShouldBeNil)
+
+		Convey("The ids should start at the beginning of the range", func() {
+			first, err := ids.Next()
+			So(err, ShouldBeNil)
+			second, err := ids.Next()
+			So(err, ShouldBeNil)
+			So(first, ShouldEqual, 1000)
+			So(second, ShouldEqual, 1001)
+		})
+
+		Convey("A restarted gateway should continue after the reserved block", func() {
+			_, err := ids.Next()
+			So(err, ShouldBeNil)
+
+			restarted, err := newFIXOrderIDs(path, 1000, 1000+fixOrderIDBlock*2)
+			So(err, ShouldBeNil)
+			id, err := restarted.Next()
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, 1000+fixOrderIDBlock)
+		})
+
+		Convey("The ids should be rejected once the range is used", func() {
+			restarted, err := newFIXOrderIDs(path, 1000, 1001)
+			So(err, ShouldBeNil)
+			_, err = restarted.Next()
+			So(err, ShouldBeNil)
+			_, err = restarted.Next()
+			So(err, ShouldBeNil)
+			_, err = restarted.Next()
+			So(err, ShouldEqual, ErrOrderIDsExhausted)
+		})
+
+		Convey("The last id of the largest range should not overflow", func() {
+			restarted, err := newFIXOrderIDs(path, ^uint64(0), ^uint64(0))
+			So(err, ShouldBeNil)
+			id, err := restarted.Next()
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, ^uint64(0))
+			_, err = restarted.Next()
+			So(err, ShouldEqual, ErrOrderIDsExhausted)
+		})
+
+		Convey("An empty range should be refused", func() {
+			_, err := newFIXOrderIDs(path, 2000, 1000)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+// DefaultGRPCTimeout is the default number of milliseconds a command waits for its events
+const DefaultGRPCTimeout = 5000
+
+// grpcServer implements the matching engine gRPC service on top of the markets of the server
+type grpcServer struct {
+	model.UnimplementedMatchingEngineServer
+	markets map[string]MarketEngine
+	timeout time.Duration
+}
+
+// newGRPCServer creates the gRPC service for the given markets
+func newGRPCServer(config GRPCConfig, markets map[string]MarketEngine) *grpcServer {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultGRPCTimeout
+	}
+	return &grpcServer{markets: markets, timeout: time.Duration(timeout) * time.Millisecond}
+}
+
+// SubmitOrder sends a new order to its market and returns the events generated by the engine
+func (srv *grpcServer) SubmitOrder(ctx context.Context, order *model.Order) (*model.Events, error) {
+	if order.EventType != model.CommandType_NewOrder {
+		return nil, status.Error(codes.InvalidArgument, "SubmitOrder only accepts new orders")
+	}
+	return srv.submit(ctx, order)
+}
+
+// CancelOrder sends a cancel request to its market and returns the events generated by the engine
+func (srv *grpcServer) CancelOrder(ctx context.Context, order *model.Order) (*model.Events, error) {
+	if order.EventType != model.CommandType_CancelOrder {
+		return nil, status.Error(codes.InvalidArgument, "CancelOrder only accepts cancel requests")
+	}
+	return srv.submit(ctx, order)
+}
+
+// submit sends the command to its market and waits for the generated events
+func (srv *grpcServer) submit(ctx context.Context, order *model.Order) (*model.Events, error) {
+	market, ok := srv.markets[order.Market]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "unknown market")
+	}
+	ctx, cancel := context.WithTimeout(ctx, srv.timeout)
+	defer cancel()
+	events, err := market.SubmitCommand(ctx, order)
+	switch {
+	case err == context.DeadlineExceeded:
+		return nil, status.Error(codes.DeadlineExceeded, "timeout waiting for the events of the command")
+	case err == context.Canceled:
+		return nil, status.Error(codes.Canceled, err.Error())
+	case err == ErrSubscriptionsDisabled:
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		log.Error().Err(err).Str("section", "grpc").Str("action", "submit").Str("market", order.Market).Msg("Unable to submit command")
+		return nil, status.Error(codes.Unavailable, "unable to submit command")
+	}
+	return &model.Events{Events: events}, nil
+}
+
+// Subscribe streams the events generated by a market starting after the requested sequence id
+func (srv *grpcServer) Subscribe(req *model.SubscribeRequest, stream model.MatchingEngine_SubscribeServer) error {
+	market, ok := srv.markets[req.Market]
+	if !ok {
+		return status.Error(codes.NotFound, "unknown market")
+	}
+	sub, err := market.SubscribeEvents(req.FromSeqID)
+	switch {
+	case err == ErrEventsUnavailable:
+		return status.Error(codes.OutOfRange, err.Error())
+	case err != nil:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	defer sub.Close()
+	for _, event := range sub.Backlog {
+		if err := stream.Send(event); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case events, more := <-sub.Events:
+			if !more {
+				if sub.Dropped() {
+					return status.Error(codes.ResourceExhausted, "subscriber is not keeping up with the market")
+				}
+				return status.Error(codes.Unavailable, "market closed")
+			}
+			for _, event := range events {
+				if err := stream.Send(event); err != nil {
+					return err
+				}
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// loopGRPCServer starts the gRPC service on the configured address
+func loopGRPCServer(config GRPCConfig, markets map[string]MarketEngine) {
+	if !config.Enabled {
+		return
+	}
+	address := config.Host + ":" + config.Port
+	log.Debug().Str("section", "server").Str("action", "init").Str("goroutine", "server.grpc").Str("address", address).Msg("Starting gRPC server")
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatal().Err(err).Str("section", "server").Str("action", "init").Str("goroutine", "server.grpc").Str("address", address).Msg("Unable to listen for gRPC connections")
+		return
+	}
+	grpcSrv := grpc.NewServer()
+	model.RegisterMatchingEngineServer(grpcSrv, newGRPCServer(config, markets))
+	if err := grpcSrv.Serve(listener); err != nil {
+		log.Error().Err(err).Str("section", "server").Str("action", "init").Str("goroutine", "server.grpc").Str("address", address).Msg("Error starting gRPC server")
+	}
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// subscribeStream collects the events sent to a subscriber of the gRPC service
+type subscribeStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	sent    chan *model.Event
+	release chan bool
+}
+
+func newSubscribeStream(ctx context.Context) *subscribeStream {
+	return &subscribeStream{ctx: ctx, sent: make(chan *model.Event, 100)}
+}
+
+func (stream *subscribeStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *subscribeStream) Send(event *model.Event) error {
+	if stream.release != nil {
+		<-stream.release
+		stream.release = nil
+	}
+	stream.sent <- event
+	return nil
+}
+
+func subscriberCount(stream *eventStream) int {
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+	return len(stream.subscribers)
+}
+
+// waitSubscribers waits for the subscriptions started by another goroutine to be registered
+func waitSubscribers(stream *eventStream, count int) {
+	for subscriberCount(stream) < count {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGRPCServer(t *testing.T) {
+	Convey("Given the gRPC service of a market", t, func() {
+		market := &stubMarket{stream: newEventStream(10)}
+		srv := newGRPCServer(GRPCConfig{Timeout: 100}, map[string]MarketEngine{"btcusd": market})
+		ctx := context.Background()
+
+		Convey("Orders should be submitted with the rpc of their type", func() {
+			_, err := srv.SubmitOrder(ctx, &model.Order{Market: "btcusd", EventType: model.CommandType_CancelOrder})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			_, err = srv.CancelOrder(ctx, &model.Order{Market: "btcusd", EventType: model.CommandType_NewOrder})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			_, err = srv.SubmitOrder(ctx, &model.Order{Market: "btcusd", EventType: model.CommandType_TradeBust})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			So(market.received, ShouldBeEmpty)
+		})
+
+		Convey("The events generated by a command should be returned", func() {
+			market.submit = func(ctx context.Context, order *model.Order) ([]*model.Event, error) {
+				_, hasDeadline := ctx.Deadline()
+				So(hasDeadline, ShouldBeTrue)
+				return newStreamEvents(1, 2), nil
+			}
+			events, err := srv.SubmitOrder(ctx, &model.Order{Market: "btcusd", EventType: model.CommandType_NewOrder})
+			So(err, ShouldBeNil)
+			So(eventSeqIDs(events.Events), ShouldResemble, []uint64{1, 2})
+			_, err = srv.CancelOrder(ctx, &model.Order{Market: "btcusd", EventType: model.CommandType_CancelOrder})
+			So(err, ShouldBeNil)
+			So(market.received, ShouldHaveLength, 2)
+		})
+
+		Convey("Errors of the market should be converted to status codes", func() {
+			_, err := srv.SubmitOrder(ctx, &model.Order{Market: "ethbtc", EventType: model.CommandType_NewOrder})
+			So(status.Code(err), ShouldEqual, codes.NotFound)
+			_, err = srv.SubmitOrder(ctx, &model.Order{Market: "btcusd", EventType: model.CommandType_NewOrder})
+			So(status.Code(err), ShouldEqual, codes.FailedPrecondition)
+			market.submit = func(ctx context.Context, order *model.Order) ([]*model.Event, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			_, err = srv.SubmitOrder(ctx, &model.Order{Market: "btcusd", EventType: model.CommandType_NewOrder})
+			So(status.Code(err), ShouldEqual, codes.DeadlineExceeded)
+		})
+
+		Convey("A subscription should replay the kept events before the new ones", func() {
+			market.stream.Publish(newStreamEvents(1, 3))
+			subCtx, cancel := context.WithCancel(ctx)
+			stream := newSubscribeStream(subCtx)
+			done := make(chan error, 1)
+			go func() { done <- srv.Subscribe(&model.SubscribeRequest{Market: "btcusd", FromSeqID: 1}, stream) }()
+			So((<-stream.sent).SeqID, ShouldEqual, 2)
+			So((<-stream.sent).SeqID, ShouldEqual, 3)
+			// wait for the subscription to be registered before publishing the next events
+			waitSubscribers(market.stream, 1)
+			market.stream.Publish(newStreamEvents(4, 4))
+			So((<-stream.sent).SeqID, ShouldEqual, 4)
+			cancel()
+			So(<-done, ShouldEqual, context.Canceled)
+		})
+
+		Convey("A subscription from events that are no longer kept should be rejected", func() {
+			market.stream.Publish(newStreamEvents(5, 20))
+			err := srv.Subscribe(&model.SubscribeRequest{Market: "btcusd", FromSeqID: 1}, newSubscribeStream(ctx))
+			So(status.Code(err), ShouldEqual, codes.OutOfRange)
+			err = srv.Subscribe(&model.SubscribeRequest{Market: "ethbtc"}, newSubscribeStream(ctx))
+			So(status.Code(err), ShouldEqual, codes.NotFound)
+		})
+
+		Convey("A subscriber that does not keep up should be dropped", func() {
+			stream := newSubscribeStream(ctx)
+			stream.sent = make(chan *model.Event, subscriberBuffer+10)
+			stream.release = make(chan bool)
+			done := make(chan error, 1)
+			go func() { done <- srv.Subscribe(&model.SubscribeRequest{Market: "btcusd"}, stream) }()
+			waitSubscribers(market.stream, 1)
+			// the first batch is taken by the blocked send and the next ones fill the buffer of the subscriber
+			for seqID := uint64(1); seqID <= subscriberBuffer+2; seqID++ {
+				market.stream.Publish(newStreamEvents(seqID, seqID))
+			}
+			close(stream.release)
+			So(status.Code(<-done), ShouldEqual, codes.ResourceExhausted)
+			So(subscriberCount(market.stream), ShouldEqual, 0)
+		})
+	})
+}
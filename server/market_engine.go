@@ -2,66 +2,246 @@ package server
 
 import (
 	"context"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/marketdata"
 	"gitlab.com/around25/products/matching-engine/model"
 	"gitlab.com/around25/products/matching-engine/net"
-
-	"github.com/segmentio/kafka-go"
 )
 
 // MarketEngine defines how we can communicate to the trading engine for a specific market
 type MarketEngine interface {
 	Start(context.Context)
 	Close()
-	GetMessageChan() <-chan kafka.Message
+	GetMessageChan() <-chan net.Message
 	LoadMarketFromBackup() error
-	Process(kafka.Message)
+	Process(net.Message)
+	GetTicker() *model.Ticker
+	GetSnapshot() *MarketSnapshot
+	SubmitCommand(context.Context, *model.Order) ([]*model.Event, error)
+	SubmitDirectCommand(context.Context, *model.Order) ([]*model.Event, error)
+	SubscribeEvents(fromSeqID uint64) (*EventSubscription, error)
+	GetFeed() *MarketFeed
 }
 
 // marketEngine structure
 type marketEngine struct {
 	name     string
 	engine   engine.TradingEngine
-	inputs   chan kafka.Message
+	inputs   chan net.Message
 	messages chan engine.Event
 	orders   chan engine.Event
 	events   chan engine.Event
 	backup   chan bool
 	stats    chan bool
-	producer net.KafkaProducer
-	consumer net.KafkaConsumer
+	producer net.Producer
+	consumer net.Consumer
 	config   MarketEngineConfig
+
+	depth         chan model.DepthMessage
+	depthSnapshot chan bool
+
+	orderFeed         chan model.OrderFeedMessage
+	orderFeedSnapshot chan bool
+
+	candleAggregator *marketdata.CandleAggregator
+	candles          chan *model.Candle
+	candleTick       chan time.Time
+
+	ticker         *marketdata.Ticker
+	tickers        chan *model.Ticker
+	tickerTick     chan time.Time
+	lastTicker     *model.Ticker
+	lastTickerLock sync.RWMutex
+
+	querySources       chan *querySnapshotSource
+	queryTick          chan time.Time
+	querySnapshot      *MarketSnapshot
+	querySnapshotLock  sync.RWMutex
+	querySnapshotTaken bool
+	querySeqID         uint64
+
+	// commands waiting for their events by request id
+	requests    sync.Map
+	eventStream *eventStream
+	// number of commands received directly, used to enforce the limit of the development version
+	directCommands int64
+	// writes the commands received directly on the direct journal before they are processed
+	directProducer net.Producer
+	// number of commands of the direct journal processed by the market
+	directOffset int64
+	// journaled direct commands that were not included in the backup and must be replayed
+	directPending []net.Message
+
+	// position of the last input message included in the backup the market was restored from
+	inputTopic     string
+	inputPartition int32
+	inputOffset    int64
+
+	// closed before the channels of the market so the commands are no longer queued
+	closing   chan struct{}
+	closeLock sync.RWMutex
+	closeOnce sync.Once
+	// closed when the market is closed or its output stopped to end the schedulers
+	halted   chan struct{}
+	haltOnce sync.Once
+
+	// public market data streamed to the websocket clients
+	feed *MarketFeed
+
+	// events relevant to each owner published on the private streams
+	privateStream *marketdata.PrivateStream
+	private       chan []*model.OwnerEvent
+
+	// input messages that could not be decoded or failed validation
+	deadLetters chan net.Message
+
+	// pauses the processing of commands while the events can't be published
+	output *outputBreaker
+	// serialises the backups of the matching process with the update of a stopped market
+	backupLock sync.Mutex
 }
 
 // MarketEngineConfig structure
 type MarketEngineConfig struct {
-	producer  net.KafkaProducer
-	consumer  net.KafkaConsumer
+	producer  net.Producer
+	consumer  net.Consumer
 	config    MarketConfig
 	maxOffset int64
+	// policy used to retry the failed writes of the events
+	retry net.RetryPolicy
+
+	// optional producers of the types of events published on their own topic
+	eventProducers map[model.EventType]net.Producer
+	// optional producer for the market depth topic
+	depthProducer net.Producer
+	// optional producer for the order feed topic
+	orderFeedProducer net.Producer
+	// optional producer for the candles topic
+	candlesProducer net.Producer
+	// optional producer for the ticker topic
+	tickerProducer net.Producer
+	// optional producer for the private streams of the owners
+	privateProducer net.Producer
+	// optional producer for the dead-letter topic
+	deadLetterProducer net.Producer
+	// optional producer used to write the commands received over gRPC or FIX on the input topic
+	commandProducer net.Producer
+	// number of recent events kept to resume subscriptions, used when commandProducer is set
+	eventHistory int
+	// stream the public market data to the websocket clients
+	marketFeed bool
+	// accept commands that are processed without being written on the input topic
+	directCommands bool
+	// journal where the commands received directly are written before they are processed
+	directJournal *net.FileJournal
+	// publish the events generated by a command as a single Events message
+	batchOutput bool
 }
 
 // NewMarketEngine open a new market
 func NewMarketEngine(config MarketEngineConfig) MarketEngine {
+	tradingEngine := engine.NewTradingEngine(config.config.MarketID, config.config.PricePrecision, config.config.VolumePrecision)
+	if config.config.DuplicateWindow > 0 {
+		tradingEngine.GetOrderBook().SetRecentOrdersWindow(config.config.DuplicateWindow)
+	}
+	if config.config.TradeWindow > 0 {
+		tradingEngine.GetOrderBook().SetRecentTradesWindow(config.config.TradeWindow)
+	}
+	if len(config.config.TradeOperators) == 0 {
+		log.Warn().Str("section", "init:market").Str("action", "set_trade_operators").Str("market", config.config.MarketID).Msg("No trade operators configured, all trade busts and corrections are rejected")
+	}
+	tradingEngine.GetOrderBook().SetTradeOperators(config.config.TradeOperators)
+	tradingEngine.GetOrderBook().SetBBOEvents(config.config.BBOEvents)
+	tradingEngine.GetOrderBook().SetIncrements(
+		incrementUnits(config.config.QuoteIncrements, config.config.PricePrecision),
+		incrementUnits(config.config.BaseIncrements, config.config.VolumePrecision),
+	)
+	var candleAggregator *marketdata.CandleAggregator
+	if config.candlesProducer != nil {
+		candleAggregator = newCandleAggregator(config.config)
+	}
+	var stream *eventStream
+	if config.commandProducer != nil {
+		stream = newEventStream(config.eventHistory)
+	}
+	var feed *MarketFeed
+	if config.marketFeed {
+		feed = newMarketFeed(config.config)
+	}
+	var ticker *marketdata.Ticker
+	if config.tickerProducer != nil {
+		ticker = marketdata.NewTicker(config.config.MarketID, config.config.PricePrecision, config.config.VolumePrecision)
+	}
+	var privateStream *marketdata.PrivateStream
+	if config.privateProducer != nil {
+		privateStream = marketdata.NewPrivateStream(config.config.Private.DropCopy)
+	}
+	var directProducer net.Producer
+	if config.directJournal != nil {
+		directProducer = net.NewFileJournalProducer(config.directJournal)
+	}
 	return &marketEngine{
 		producer: config.producer,
 		consumer: config.consumer,
 		config:   config,
 		name:     config.config.MarketID,
-		engine:   engine.NewTradingEngine(config.config.MarketID, config.config.PricePrecision, config.config.VolumePrecision),
+		engine:   tradingEngine,
 		backup:   make(chan bool),
 		orders:   make(chan engine.Event, 20000),
 		events:   make(chan engine.Event, 20000),
 		messages: make(chan engine.Event, 20000),
+
+		depth:         make(chan model.DepthMessage, 20000),
+		depthSnapshot: make(chan bool),
+
+		orderFeed:         make(chan model.OrderFeedMessage, 20000),
+		orderFeedSnapshot: make(chan bool),
+
+		candleAggregator: candleAggregator,
+		candles:          make(chan *model.Candle, 20000),
+		candleTick:       make(chan time.Time),
+
+		ticker:     ticker,
+		tickers:    make(chan *model.Ticker, 20000),
+		tickerTick: make(chan time.Time),
+
+		querySources: make(chan *querySnapshotSource, 1),
+		queryTick:    make(chan time.Time),
+
+		eventStream: stream,
+		feed:        feed,
+
+		privateStream: privateStream,
+		private:       make(chan []*model.OwnerEvent, 20000),
+
+		deadLetters: make(chan net.Message, 20000),
+
+		directProducer: directProducer,
+		closing:        make(chan struct{}),
+		halted:         make(chan struct{}),
+
+		output: newOutputBreaker(),
 	}
 }
 
-func (mkt *marketEngine) GetMessageChan() <-chan kafka.Message {
+// incrementUnits converts an increment of the market configuration into units with the given precision
+func incrementUnits(increment float64, precision int) uint64 {
+	return uint64(math.Round(increment * math.Pow10(precision)))
+}
+
+// GetFeed returns the public market data streamed to the websocket clients or nil if it is disabled
+func (mkt *marketEngine) GetFeed() *MarketFeed {
+	return mkt.feed
+}
+
+func (mkt *marketEngine) GetMessageChan() <-chan net.Message {
 	return mkt.consumer.GetMessageChan()
 }
 
@@ -69,9 +249,28 @@ func (mkt *marketEngine) GetMessageChan() <-chan kafka.Message {
 func (mkt *marketEngine) Start(ctx context.Context) {
 	// load last market snapshot from the backup files and update offset for the trading engine consumer
 	mkt.LoadMarketFromBackup()
+	if err := mkt.loadDirectCommands(); err != nil {
+		log.Fatal().Err(err).Str("section", "init:market").Str("action", "load_direct_commands").Str("market", mkt.name).Msg("Unable to load direct commands from journal")
+	}
+	// start streaming the price levels from the ones loaded from the backup
+	if mkt.feed != nil {
+		snapshot := mkt.engine.GetOrderBook().GetDepthSnapshot(0)
+		mkt.feed.Reset(&snapshot)
+	}
 	if err := mkt.producer.Start(); err != nil {
 		log.Fatal().Err(err).Str("section", "init:market").Str("action", "start_producer").Str("market", mkt.name).Msg("Unable to start producer")
 	}
+	started := make(map[net.Producer]bool)
+	for eventType, producer := range mkt.config.eventProducers {
+		if started[producer] {
+			continue
+		}
+		if err := producer.Start(); err != nil {
+			log.Fatal().Err(err).Str("section", "init:market").Str("action", "start_event_producer").Str("market", mkt.name).Str("event_type", eventType.String()).Msg("Unable to start event producer")
+		}
+		started[producer] = true
+	}
+	outputAvailable.WithLabelValues(mkt.name).Set(1)
 	if err := mkt.consumer.Start(ctx); err != nil {
 		log.Fatal().Err(err).Str("section", "init:market").Str("action", "start_consumer").Str("market", mkt.name).Msg("Unable to start consumer")
 	}
@@ -83,36 +282,150 @@ func (mkt *marketEngine) Start(ctx context.Context) {
 	go mkt.PublishEvents()
 	// start the backup scheduler
 	go mkt.ScheduleBackup()
+	// publish market depth on the depth topic
+	if mkt.config.depthProducer != nil {
+		if err := mkt.config.depthProducer.Start(); err != nil {
+			log.Fatal().Err(err).Str("section", "init:market").Str("action", "start_depth_producer").Str("market", mkt.name).Msg("Unable to start depth producer")
+		}
+		go mkt.PublishDepth()
+		go mkt.ScheduleDepthSnapshots()
+	}
+	// publish the order feed on the order feed topic
+	if mkt.config.orderFeedProducer != nil {
+		if err := mkt.config.orderFeedProducer.Start(); err != nil {
+			log.Fatal().Err(err).Str("section", "init:market").Str("action", "start_order_feed_producer").Str("market", mkt.name).Msg("Unable to start order feed producer")
+		}
+		go mkt.PublishOrderFeed()
+		go mkt.ScheduleOrderFeedSnapshots()
+	}
+	// publish closed candles on the candles topic
+	if mkt.candleAggregator != nil {
+		if err := mkt.config.candlesProducer.Start(); err != nil {
+			log.Fatal().Err(err).Str("section", "init:market").Str("action", "start_candles_producer").Str("market", mkt.name).Msg("Unable to start candles producer")
+		}
+		go mkt.PublishCandles()
+		go mkt.ScheduleCandles()
+	}
+	// publish the rolling 24h statistics on the ticker topic
+	if mkt.ticker != nil {
+		if err := mkt.config.tickerProducer.Start(); err != nil {
+			log.Fatal().Err(err).Str("section", "init:market").Str("action", "start_ticker_producer").Str("market", mkt.name).Msg("Unable to start ticker producer")
+		}
+		go mkt.PublishTicker()
+		go mkt.ScheduleTicker()
+	}
+	// publish the events of each owner on the private streams
+	if mkt.privateStream != nil {
+		if err := mkt.config.privateProducer.Start(); err != nil {
+			log.Fatal().Err(err).Str("section", "init:market").Str("action", "start_private_producer").Str("market", mkt.name).Msg("Unable to start private stream producer")
+		}
+		go mkt.PublishPrivateEvents()
+	}
+	// publish the rejected input messages on the dead-letter topic
+	if mkt.config.deadLetterProducer != nil {
+		if err := mkt.config.deadLetterProducer.Start(); err != nil {
+			log.Fatal().Err(err).Str("section", "init:market").Str("action", "start_dead_letter_producer").Str("market", mkt.name).Msg("Unable to start dead-letter producer")
+		}
+		go mkt.PublishDeadLetters()
+	}
+	// write the commands received from the gRPC service on the input topic
+	if mkt.config.commandProducer != nil {
+		if err := mkt.config.commandProducer.Start(); err != nil {
+			log.Fatal().Err(err).Str("section", "init:market").Str("action", "start_command_producer").Str("market", mkt.name).Msg("Unable to start command producer")
+		}
+	}
+	// keep a snapshot of the market to answer the queries received on the monitoring listener
+	if mkt.config.config.Query.Enabled {
+		go mkt.IndexQuerySnapshots()
+		go mkt.ScheduleQuerySnapshots()
+	}
 }
 
 // Process a new message from the consumer
-func (mkt *marketEngine) Process(msg kafka.Message) {
+func (mkt *marketEngine) Process(msg net.Message) {
 	if mkt.config.maxOffset != 0 && msg.Offset >= mkt.config.maxOffset {
 		log.Warn().Str("section", "market").Str("action", "process").Str("market", mkt.name).Int64("offset", mkt.config.maxOffset).Msg("Maximum offset reached for development version. Market shutting down.")
 		mkt.Close()
 		return
 	}
-	// Monitor: Increment the number of messages that has been received by the market
-	messagesQueued.WithLabelValues(mkt.name).Inc()
-	mkt.messages <- engine.NewEvent(msg)
+	// the messages received after the market was stopped are processed again when it is restored from the backup
+	if mkt.output.isStopped() {
+		return
+	}
+	mkt.queueMessage(msg)
+}
+
+// queueMessage sends a message to the decoder and returns false if the market was closed
+func (mkt *marketEngine) queueMessage(msg net.Message) bool {
+	mkt.closeLock.RLock()
+	defer mkt.closeLock.RUnlock()
+	select {
+	case <-mkt.closing:
+		return false
+	default:
+	}
+	select {
+	case mkt.messages <- engine.NewEvent(msg):
+		// Monitor: Increment the number of messages that has been received by the market
+		messagesQueued.WithLabelValues(mkt.name).Inc()
+		return true
+	case <-mkt.closing:
+		return false
+	}
 }
 
 // Close the market by closing all communication channels
+// - The messages received after the market was closed are ignored
 func (mkt *marketEngine) Close() {
-	close(mkt.messages)
-	close(mkt.orders)
-	close(mkt.events)
+	mkt.closeOnce.Do(func() {
+		close(mkt.closing)
+		mkt.halt()
+		// wait for the messages being queued to give up before closing the channels
+		mkt.closeLock.Lock()
+		defer mkt.closeLock.Unlock()
+		close(mkt.messages)
+		close(mkt.orders)
+		close(mkt.events)
+		close(mkt.depth)
+		close(mkt.orderFeed)
+		close(mkt.candles)
+		close(mkt.tickers)
+		close(mkt.querySources)
+		close(mkt.private)
+		close(mkt.deadLetters)
+	})
+}
+
+// halt ends the schedulers of the market
+func (mkt *marketEngine) halt() {
+	mkt.haltOnce.Do(func() { close(mkt.halted) })
+}
+
+// waitSchedule waits for the interval of a scheduler and returns false once the market was halted
+func (mkt *marketEngine) waitSchedule(interval time.Duration) bool {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-mkt.halted:
+		return false
+	}
 }
 
 // ScheduleBackup sets up an interval at which to automatically back up the market on Kafka
+// - Returns once the market is closed or its output stopped
 func (mkt *marketEngine) ScheduleBackup() {
 	if mkt.config.config.Backup.Interval == 0 {
 		log.Warn().Str("section", "backup").Str("action", "schedule").Str("market", mkt.name).Msg("Backup disabled for market")
 		return
 	}
-	for {
-		time.Sleep(time.Duration(mkt.config.config.Backup.Interval) * time.Minute)
-		mkt.backup <- true
+	for mkt.waitSchedule(time.Duration(mkt.config.config.Backup.Interval) * time.Minute) {
+		select {
+		case mkt.backup <- true:
+		case <-mkt.halted:
+			return
+		}
 	}
 }
 
@@ -123,7 +436,15 @@ func (mkt *marketEngine) ScheduleBackup() {
 func (mkt *marketEngine) DecodeMessage() {
 	log.Debug().Str("section", "server").Str("action", "init").Str("market", mkt.name).Msg("Starting message decoder process")
 	for event := range mkt.messages {
-		event.Decode()
+		if err := event.Decode(); err != nil {
+			log.Warn().Err(err).
+				Str("section", "server").Str("action", "decode").
+				Str("market", mkt.name).
+				Str("kafka_topic", event.Msg.Topic).
+				Int("kafka_partition", event.Msg.Partition).
+				Int64("kafka_offset", event.Msg.Offset).
+				Msg("Unable to decode message")
+		}
 		messagesQueued.WithLabelValues(mkt.name).Dec()
 		// Monitor: Increment the number of orders that are waiting to be processed
 		ordersQueued.WithLabelValues(mkt.name).Inc()
@@ -137,111 +458,209 @@ func (mkt *marketEngine) DecodeMessage() {
 // Message flow is unidirectional from the orders channel to the events channel
 func (mkt *marketEngine) ProcessOrder() {
 	log.Debug().Str("section", "server").Str("action", "init").Str("market", mkt.name).Msg("Starting order matching process")
-	var lastTopic string
-	var lastPartition int32
-	var lastOffset int64
-	var prevOffset int64
+	// position of the last input message processed, starting from the one restored from the backup
+	lastTopic := mkt.inputTopic
+	lastPartition := mkt.inputPartition
+	lastOffset := mkt.inputOffset
+	prevOffset := lastOffset
+	prevDirectOffset := mkt.directOffset
+	// number of commands sent to the event publisher
+	var processed uint64
+	// commands received directly while the journaled ones are replayed
+	held := make([]engine.Event, 0)
+	// replay the direct commands processed before the first input message after the backup
+	processed += mkt.replayDirectCommands(inputPosition(lastTopic, lastOffset))
 	for {
 		select {
 		case <-mkt.backup:
 			// Generate backup event
-			if lastTopic != "" && lastOffset != prevOffset {
+			if (lastTopic != "" && lastOffset != prevOffset) || mkt.directOffset != prevDirectOffset {
+				// the backup must not include commands whose events were not published yet
+				if !mkt.output.waitPublished(processed) {
+					return
+				}
 				market := mkt.engine.BackupMarket()
 				market.Topic = lastTopic
 				market.Partition = lastPartition
 				market.Offset = lastOffset
+				market.DirectOffset = mkt.directOffset
+				if mkt.candleAggregator != nil {
+					market.Candles, market.ClosedCandles = mkt.candleAggregator.Backup()
+				}
+				if mkt.ticker != nil {
+					market.TickerBuckets = mkt.ticker.Backup()
+				}
+				if mkt.privateStream != nil {
+					market.OwnerSeqIDs = mkt.privateStream.Backup()
+				}
 				prevOffset = lastOffset
+				prevDirectOffset = mkt.directOffset
 				mkt.BackupMarket(market)
 				log.Debug().Str("section", "backup").Str("action", "export").Str("market", mkt.name).Msg("Snapshot created")
 			}
+		case <-mkt.depthSnapshot:
+			mkt.publishDepthSnapshot()
+		case <-mkt.orderFeedSnapshot:
+			mkt.publishOrderFeedSnapshot()
+		case now := <-mkt.candleTick:
+			mkt.closeCandles(now)
+		case now := <-mkt.tickerTick:
+			mkt.refreshTicker(now)
+		case now := <-mkt.queryTick:
+			mkt.takeQuerySnapshot(now)
 		case event, more := <-mkt.orders:
 			if !more {
 				log.Debug().Str("section", "server").Str("action", "terminate").Str("market", mkt.name).Msg("Closed order matching process")
 				return
 			}
-			order := event.Order
-			if !order.Valid() {
-				log.Warn().
-					Str("section", "server").Str("action", "process_order").
-					Str("market", mkt.name).
-					Str("kafka_topic", event.Msg.Topic).
-					Int("kafka_partition", event.Msg.Partition).
-					Int64("kafka_offset", event.Msg.Offset).
-					Dict("event", zerolog.Dict().
-						Str("event_type", order.EventType.String()).
-						Str("side", order.Side.String()).
-						Str("type", order.Type.String()).
-						Str("event_type", order.EventType.String()).
-						Str("market", order.Market).
-						Uint64("id", order.ID).
-						Uint64("amount", order.Amount).
-						Str("stop", order.Stop.String()).
-						Uint64("stop_price", order.StopPrice).
-						Uint64("funds", order.Funds).
-						Uint64("price", order.Price),
-					).
-					Msg("Invalid order received, ignoring")
-				// send invalid notification
-				events := make([]model.Event, 0, 1)
-				mkt.engine.AppendInvalidOrder(order, &events)
-				event.SetEvents(events)
-
-				// Monitor: Update order count for monitoring with prometheus
-				engineOrderCount.WithLabelValues(mkt.name).Inc()
-				ordersQueued.WithLabelValues(mkt.name).Dec()
-				eventsQueued.WithLabelValues(mkt.name).Add(float64(len(event.Events)))
-				// send generated events for storage
-				mkt.events <- event
-				lastTopic = event.Msg.Topic
-				lastPartition = int32(event.Msg.Partition)
-				lastOffset = event.Msg.Offset
+			// wait for the events to be published again before moving the order book forward
+			if !mkt.output.waitAvailable() {
+				log.Warn().Str("section", "server").Str("action", "terminate").Str("market", mkt.name).Msg("Market stopped, closed order matching process")
+				return
+			}
+			// commands received directly are not on the input topic and are replayed from the direct journal
+			if event.Msg.Topic == "" {
+				if len(mkt.directPending) > 0 {
+					held = append(held, event)
+					continue
+				}
+				if mkt.processDirectCommand(event, inputPosition(lastTopic, lastOffset)) {
+					processed++
+				}
 				continue
 			}
-			log.Debug().
-				Str("section", "server").Str("action", "process_order").
-				Str("market", mkt.name).
-				Str("kafka_topic", event.Msg.Topic).
-				Int("kafka_partition", event.Msg.Partition).
-				Int64("kafka_offset", event.Msg.Offset).
-				Dict("event", zerolog.Dict().
-					Str("event_type", order.EventType.String()).
-					Str("side", order.Side.String()).
-					Str("type", order.Type.String()).
-					Str("event_type", order.EventType.String()).
-					Str("market", order.Market).
-					Uint64("id", order.ID).
-					Uint64("amount", order.Amount).
-					Str("stop", order.Stop.String()).
-					Uint64("stop_price", order.StopPrice).
-					Uint64("funds", order.Funds).
-					Uint64("price", order.Price),
-				).
-				Msg("New order")
-			events := make([]model.Event, 0, 5)
-			// Process each order and generate events
-			mkt.engine.ProcessEvent(event.Order, &events)
-			event.SetEvents(events)
-			// Monitor: Update order count for monitoring with prometheus
-			engineOrderCount.WithLabelValues(mkt.name).Inc()
-			ordersQueued.WithLabelValues(mkt.name).Dec()
-			eventsQueued.WithLabelValues(mkt.name).Add(float64(len(event.Events)))
-			// send generated events for storage
-			mkt.events <- event
+			if mkt.processCommand(event) {
+				processed++
+			}
 			lastTopic = event.Msg.Topic
 			lastPartition = int32(event.Msg.Partition)
 			lastOffset = event.Msg.Offset
+			if len(mkt.directPending) > 0 {
+				processed += mkt.replayDirectCommands(lastOffset)
+				// the direct commands received during the replay are processed after the journaled ones
+				if len(mkt.directPending) == 0 {
+					for _, direct := range held {
+						if mkt.processDirectCommand(direct, lastOffset) {
+							processed++
+						}
+					}
+					held = held[:0]
+				}
+			}
 		}
 	}
 }
 
+// processCommand runs an input message through the trading engine and sends the generated events for publishing
+// - Returns false if the message is not an order and no events were sent
+func (mkt *marketEngine) processCommand(event engine.Event) bool {
+	if event.Err != nil {
+		// the message is not an order so no event can be sent back to its owner
+		mkt.rejectMessage(&event, DeadLetterReasonDecode, event.Err)
+		ordersQueued.WithLabelValues(mkt.name).Dec()
+		return false
+	}
+	order := event.Order
+	if !order.Valid() {
+		log.Warn().
+			Str("section", "server").Str("action", "process_order").
+			Str("market", mkt.name).
+			Str("kafka_topic", event.Msg.Topic).
+			Int("kafka_partition", event.Msg.Partition).
+			Int64("kafka_offset", event.Msg.Offset).
+			Dict("event", zerolog.Dict().
+				Str("event_type", order.EventType.String()).
+				Str("side", order.Side.String()).
+				Str("type", order.Type.String()).
+				Str("event_type", order.EventType.String()).
+				Str("market", order.Market).
+				Uint64("id", order.ID).
+				Str("client_order_id", order.ClientOrderID).
+				Uint64("amount", order.Amount).
+				Str("stop", order.Stop.String()).
+				Uint64("stop_price", order.StopPrice).
+				Uint64("funds", order.Funds).
+				Uint64("price", order.Price),
+			).
+			Msg("Invalid order received, ignoring")
+		mkt.rejectMessage(&event, DeadLetterReasonInvalid, ErrInvalidOrder)
+		// send invalid notification
+		events := make([]model.Event, 0, 1)
+		mkt.engine.AppendInvalidOrder(order, &events)
+		event.SetEvents(events)
+		// notify the owner of the invalid order on its private stream
+		mkt.routePrivateEvents(events)
+
+		// Monitor: Update order count for monitoring with prometheus
+		engineOrderCount.WithLabelValues(mkt.name).Inc()
+		ordersQueued.WithLabelValues(mkt.name).Dec()
+		eventsQueued.WithLabelValues(mkt.name).Add(float64(len(event.Events)))
+		// send generated events for storage
+		mkt.events <- event
+		return true
+	}
+	log.Debug().
+		Str("section", "server").Str("action", "process_order").
+		Str("market", mkt.name).
+		Str("kafka_topic", event.Msg.Topic).
+		Int("kafka_partition", event.Msg.Partition).
+		Int64("kafka_offset", event.Msg.Offset).
+		Dict("event", zerolog.Dict().
+			Str("event_type", order.EventType.String()).
+			Str("side", order.Side.String()).
+			Str("type", order.Type.String()).
+			Str("event_type", order.EventType.String()).
+			Str("market", order.Market).
+			Uint64("id", order.ID).
+			Str("client_order_id", order.ClientOrderID).
+			Uint64("amount", order.Amount).
+			Str("stop", order.Stop.String()).
+			Uint64("stop_price", order.StopPrice).
+			Uint64("funds", order.Funds).
+			Uint64("price", order.Price),
+		).
+		Msg("New order")
+	events := make([]model.Event, 0, 5)
+	// Process each order and generate events
+	mkt.engine.ProcessEvent(event.Order, &events)
+	// the market data is aggregated by the time of the input message so a replay generates the same candles and ticker
+	at := inputTime(event.Msg)
+	event.SetEvents(events)
+	// publish the price levels changed by the order
+	mkt.publishDepthUpdate(&event)
+	// publish the changes of the open orders generated by the order
+	mkt.publishOrderFeedUpdates()
+	// update the candles with the generated trades
+	mkt.aggregateCandles(events, at)
+	// update the rolling 24h statistics with the generated trades
+	mkt.aggregateTicker(events, at)
+	// send the events to the private streams of their owners
+	mkt.routePrivateEvents(events)
+	// Monitor: Update order count for monitoring with prometheus
+	engineOrderCount.WithLabelValues(mkt.name).Inc()
+	ordersQueued.WithLabelValues(mkt.name).Dec()
+	eventsQueued.WithLabelValues(mkt.name).Add(float64(len(event.Events)))
+	// send generated events for storage
+	mkt.events <- event
+	return true
+}
+
+// inputTime returns the time of an input message, which is kept by the transport and doesn't change when the message
+// is replayed, or the current time for a message without one
+func inputTime(msg net.Message) time.Time {
+	if msg.Time.IsZero() {
+		return time.Now()
+	}
+	return msg.Time
+}
+
 // PublishEvents listens for new events from the trading engine and publishes them to the Kafka server
 func (mkt *marketEngine) PublishEvents() {
 	log.Debug().Str("section", "server").Str("action", "init").Str("market", mkt.name).Msg("Starting event publisher process")
 	var lastAskID uint64
 	var lastBidID uint64
 	for event := range mkt.events {
-		events := make([]kafka.Message, len(event.Events))
-		for index, ev := range event.Events {
+		for _, ev := range event.Events {
 			logEvent := zerolog.Dict()
 			switch ev.Type {
 			case model.EventType_OrderStatusChange:
@@ -250,9 +669,12 @@ func (mkt *marketEngine) PublishEvents() {
 					logEvent = logEvent.
 						Uint64("id", payload.ID).
 						Uint64("owner_id", payload.OwnerID).
+						Str("client_order_id", payload.ClientOrderID).
 						Str("type", payload.Type.String()).
 						Str("side", payload.Side.String()).
 						Str("status", payload.Status.String()).
+						Str("reason", payload.Reason.String()).
+						Str("liquidity", payload.Liquidity.String()).
 						Uint64("price", payload.Price).
 						Uint64("funds", payload.Funds).
 						Uint64("amount", payload.Amount)
@@ -263,6 +685,7 @@ func (mkt *marketEngine) PublishEvents() {
 					logEvent = logEvent.
 						Uint64("id", payload.ID).
 						Uint64("owner_id", payload.OwnerID).
+						Str("client_order_id", payload.ClientOrderID).
 						Str("type", payload.Type.String()).
 						Str("side", payload.Side.String()).
 						Str("status", payload.Status.String()).
@@ -276,6 +699,7 @@ func (mkt *marketEngine) PublishEvents() {
 					logEvent = logEvent.
 						Uint64("order_id", payload.OrderID).
 						Uint64("owner_id", payload.OwnerID).
+						Str("client_order_id", payload.ClientOrderID).
 						Str("type", payload.Type.String()).
 						Str("side", payload.Side.String()).
 						Str("err_code", payload.Code.String()).
@@ -294,7 +718,10 @@ func (mkt *marketEngine) PublishEvents() {
 						Uint64("bid_id", trade.BidID).
 						Uint64("bid_owner_id", trade.BidOwnerID).
 						Uint64("price", trade.Price).
-						Uint64("amount", trade.Amount)
+						Uint64("amount", trade.Amount).
+						Uint64("ask_remaining", trade.AskRemaining).
+						Uint64("bid_remaining", trade.BidRemaining).
+						Uint64("maker_placed_seqid", trade.MakerPlacedSeqID)
 					if lastAskID == trade.AskID && lastBidID == trade.BidID {
 						log.Error().Str("section", "engine").Str("action", "post:trade:check").
 							Str("market", mkt.name).
@@ -311,6 +738,41 @@ func (mkt *marketEngine) PublishEvents() {
 					lastBidID = trade.BidID
 					lastAskID = trade.AskID
 				}
+			case model.EventType_TradeBusted:
+				{
+					payload := ev.GetTradeBust()
+					logEvent = logEvent.
+						Uint64("request_id", payload.RequestID).
+						Uint64("operator_id", payload.OperatorID).
+						Uint64("seqid", payload.Trade.GetSeqID()).
+						Uint64("ask_id", payload.Trade.GetAskID()).
+						Uint64("bid_id", payload.Trade.GetBidID()).
+						Uint64("price", payload.Trade.GetPrice()).
+						Uint64("amount", payload.Trade.GetAmount()).
+						Int("restored_orders", len(payload.RestoredOrderIDs))
+				}
+			case model.EventType_BestBidOffer:
+				{
+					payload := ev.GetBBO()
+					logEvent = logEvent.
+						Uint64("bid_price", payload.BidPrice).
+						Uint64("bid_amount", payload.BidAmount).
+						Uint64("ask_price", payload.AskPrice).
+						Uint64("ask_amount", payload.AskAmount)
+				}
+			case model.EventType_TradeCorrected:
+				{
+					payload := ev.GetTradeCorrect()
+					logEvent = logEvent.
+						Uint64("request_id", payload.RequestID).
+						Uint64("operator_id", payload.OperatorID).
+						Uint64("seqid", payload.Original.GetSeqID()).
+						Uint64("original_price", payload.Original.GetPrice()).
+						Uint64("original_amount", payload.Original.GetAmount()).
+						Uint64("price", payload.Corrected.GetPrice()).
+						Uint64("amount", payload.Corrected.GetAmount()).
+						Int("restored_orders", len(payload.RestoredOrderIDs))
+				}
 			}
 			log.Debug().Str("section", "server").Str("action", "publish").
 				Str("market", mkt.name).
@@ -323,14 +785,42 @@ func (mkt *marketEngine) PublishEvents() {
 				Int64("event_timestamp", ev.CreatedAt).
 				Dict("event", logEvent).
 				Msg("Generated event")
-			rawTrade, _ := ev.ToBinary() // @todo add better error handling on encoding
-			events[index] = kafka.Message{
-				Value: rawTrade,
+		}
+		// publish the events on the topic of their type or on the topic of the market
+		// - The events a topic received before the market was restored are skipped. Delivery is still at-least-once:
+		// the messages of a write that failed after reaching the broker are sent again, consumers dedupe them by SeqID.
+		// - The skipped events are selected before any write so the topics of the command don't skip each other's events
+		producers, groups := mkt.groupEventsByProducer(event.Events)
+		topics := make([]string, len(producers))
+		for i, producer := range producers {
+			topics[i] = mkt.eventTopic(groups[producer][0].Type)
+			groups[producer] = mkt.unpublishedEvents(topics[i], groups[producer])
+		}
+		for i, producer := range producers {
+			if len(groups[producer]) == 0 {
+				continue
+			}
+			if err := mkt.writeOutput(producer, "server", mkt.eventMessages(&event, groups[producer])...); err != nil {
+				mkt.stopOutput("server", err)
+				return
 			}
+			mkt.output.markTopicPublished(topics[i], lastEventSeqID(groups[producer]))
+		}
+		var lastSeqID uint64
+		for i := range event.Events {
+			if !event.Events[i].Replay {
+				lastSeqID = event.Events[i].SeqID
+			}
+		}
+		if lastSeqID != 0 {
+			lastPublishedSeqID.WithLabelValues(mkt.name).Set(float64(lastSeqID))
 		}
-		err := mkt.producer.WriteMessages(context.Background(), events...)
-		if err != nil {
-			log.Fatal().Err(err).Str("section", "server").Str("action", "publish").Str("market", mkt.name).Msg("Unable to publish events")
+		mkt.output.markPublished(lastSeqID)
+		// send the published events to the callers waiting for them and to the subscribers
+		mkt.publishToSubscribers(&event)
+		// send the public market data to the websocket clients
+		if mkt.feed != nil {
+			mkt.feed.Publish(event.Events, event.Depth)
 		}
 
 		// Monitor: Update the number of events processed after sending them back to Kafka
@@ -12,11 +12,46 @@ import (
 // BackupMarket saves the given snapshot of the order book as binary into the backups folder with the name of the market pair
 // - It first saves into a temporary file before moving the file to the final localtion
 func (mkt *marketEngine) BackupMarket(market model.MarketBackup) error {
+	mkt.backupLock.Lock()
+	defer mkt.backupLock.Unlock()
+	market.LastPublishedSeqID = mkt.output.lastPublishedSeqID()
+	market.PublishedSeqIDs = mkt.output.publishedTopics()
+	return mkt.writeBackup(&market)
+}
+
+// savePublishedSeqIDs records the sequence ids of the last published events in the backup of a stopped market
+// - Only the sequence ids are changed since the order book may be ahead of the published events
+// - A market that was never backed up gets a backup with an empty order book and no input position, so it is
+// restored as a new market that does not publish again the events it already published
+func (mkt *marketEngine) savePublishedSeqIDs() error {
+	mkt.backupLock.Lock()
+	defer mkt.backupLock.Unlock()
+	market := model.MarketBackup{MarketID: mkt.name}
+	content, err := ioutil.ReadFile(mkt.config.config.Backup.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := market.FromBinary(content); err != nil {
+			return err
+		}
+	}
+	market.LastPublishedSeqID = mkt.output.lastPublishedSeqID()
+	market.PublishedSeqIDs = mkt.output.publishedTopics()
+	return mkt.writeBackup(&market)
+}
+
+// writeBackup writes the backup into a temporary file before moving it to the backup path
+func (mkt *marketEngine) writeBackup(market *model.MarketBackup) error {
 	file := mkt.config.config.Backup.Path + ".tmp"
-	rawMarket, _ := market.ToBinary()
-	ioutil.WriteFile(file, rawMarket, 0644)
-	os.Rename(mkt.config.config.Backup.Path+".tmp", mkt.config.config.Backup.Path)
-	return nil
+	rawMarket, err := market.ToBinary()
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(file, rawMarket, 0644); err != nil {
+		return err
+	}
+	return os.Rename(file, mkt.config.config.Backup.Path)
 }
 
 // LoadMarketFromBackup from a backup file and update the order book with the given data
@@ -48,8 +83,33 @@ func (mkt *marketEngine) LoadMarketFromBackup() (err error) {
 		Int("market_sell_count", len(market.GetSellMarketEntries())).
 		Int("stop_entry_count", len(market.GetStopEntryOrders())).
 		Int("stop_loss_count", len(market.GetStopLossOrders())).
+		Uint64("last_published_seqid", market.LastPublishedSeqID).
 		Msg("Loading market from backup")
 	mkt.LoadMarket(market)
+	if mkt.candleAggregator != nil {
+		mkt.candleAggregator.Load(market.Candles, market.ClosedCandles)
+	}
+	if mkt.ticker != nil {
+		mkt.ticker.Load(market.TickerBuckets)
+	}
+	if mkt.privateStream != nil {
+		mkt.privateStream.Load(market.OwnerSeqIDs)
+	}
+	if mkt.eventStream != nil {
+		mkt.eventStream.Reset(market.EventSeqID)
+	}
+	mkt.directOffset = market.DirectOffset
+	mkt.output.restore(market.LastPublishedSeqID, market.PublishedSeqIDs)
+	if market.LastPublishedSeqID != 0 {
+		lastPublishedSeqID.WithLabelValues(mkt.name).Set(float64(market.LastPublishedSeqID))
+	}
+	// a market that only processed direct commands has no position on the input topic
+	if market.Topic == "" {
+		return nil
+	}
+	mkt.inputTopic = market.Topic
+	mkt.inputPartition = market.Partition
+	mkt.inputOffset = market.Offset
 	// mark the last message that has been processed by the engine to the one saved in the backup file
 	err = mkt.consumer.SetOffset(offset)
 	if err != nil {
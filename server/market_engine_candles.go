@@ -0,0 +1,88 @@
+package server
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/marketdata"
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// newCandleAggregator creates the candle aggregator for the intervals set in the market configuration
+func newCandleAggregator(config MarketConfig) *marketdata.CandleAggregator {
+	intervals := make([]time.Duration, 0, len(config.Candles.Intervals))
+	for _, value := range config.Candles.Intervals {
+		interval, err := marketdata.ParseCandleInterval(value)
+		if err != nil {
+			log.Fatal().Err(err).Str("section", "init:market").Str("action", "candles").Str("market", config.MarketID).Msg("Invalid candle interval")
+		}
+		intervals = append(intervals, interval)
+	}
+	return marketdata.NewCandleAggregator(config.MarketID, config.PricePrecision, config.VolumePrecision, intervals)
+}
+
+// ScheduleCandles sends the current time every second to close the candles whose interval ended
+func (mkt *marketEngine) ScheduleCandles() {
+	for mkt.waitSchedule(time.Second) {
+		select {
+		case mkt.candleTick <- time.Now():
+		case <-mkt.halted:
+			return
+		}
+	}
+}
+
+// aggregateCandles updates the candles with the trades generated, busted or corrected by a command and sends the
+// closed and the amended candles to the publisher
+// - Must be called from the order matching process so that the open candles match the market backup
+func (mkt *marketEngine) aggregateCandles(events []model.Event, at time.Time) {
+	if mkt.candleAggregator == nil {
+		return
+	}
+	for _, candle := range mkt.candleAggregator.AddEvents(events, at) {
+		mkt.candles <- candle
+	}
+	if marketdata.AmendsTrades(events) {
+		for _, candle := range mkt.candleAggregator.AmendTrades(events, mkt.tradeWindow()) {
+			mkt.candles <- candle
+		}
+	}
+}
+
+// tradeWindow returns the recent trades of the market used to amend the candles of a busted or corrected trade
+func (mkt *marketEngine) tradeWindow() marketdata.TradeWindow {
+	trades, fromSeqID := mkt.engine.GetOrderBook().GetRecentTrades()
+	return marketdata.TradeWindow{FromSeqID: fromSeqID, Trades: trades}
+}
+
+// closeCandles sends the candles whose interval ended at the given time to the publisher
+func (mkt *marketEngine) closeCandles(now time.Time) {
+	if mkt.candleAggregator == nil {
+		return
+	}
+	for _, candle := range mkt.candleAggregator.Close(now) {
+		mkt.candles <- candle
+	}
+}
+
+// PublishCandles listens for closed candles and publishes them to the candles topic
+func (mkt *marketEngine) PublishCandles() {
+	log.Debug().Str("section", "server").Str("action", "init").Str("market", mkt.name).Msg("Starting candles publisher process")
+	for candle := range mkt.candles {
+		raw, err := candle.ToBinary()
+		if err != nil {
+			log.Error().Err(err).Str("section", "candles").Str("action", "encode").Str("market", mkt.name).Msg("Unable to encode candle")
+			continue
+		}
+		if err := mkt.writeOutput(mkt.config.candlesProducer, "candles", net.Message{Value: raw}); err != nil {
+			mkt.stopOutput("candles", err)
+			continue
+		}
+		if mkt.feed != nil {
+			mkt.feed.PublishCandle(candle)
+		}
+	}
+	log.Info().Str("section", "server").Str("action", "terminate").Str("market", mkt.name).Msg("Closing candles publisher process")
+}
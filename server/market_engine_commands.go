@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync/atomic"
+
+	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// RequestIDHeader is the header of the input messages that identifies the commands waiting for their events
+const RequestIDHeader = "request_id"
+
+// ErrSubscriptionsDisabled is returned when commands are sent to a market that does not publish its events to subscribers
+var ErrSubscriptionsDisabled = errors.New("market does not publish events to subscribers")
+
+// ErrDirectCommandsDisabled is returned when commands are sent directly to a market that only accepts them on the input topic
+var ErrDirectCommandsDisabled = errors.New("market does not accept direct commands")
+
+// ErrCommandLimitReached is returned when the maximum number of commands of the development version was reached
+var ErrCommandLimitReached = errors.New("maximum number of commands reached for development version")
+
+// commandReply is sent to the caller of a command once the command was processed or rejected
+type commandReply struct {
+	events []*model.Event
+	err    error
+}
+
+// newRequestID generates a random id used to match a command written on the input topic with its events
+func newRequestID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// getRequestID returns the request id header of an input message if it was set
+func getRequestID(msg net.Message) string {
+	for _, header := range msg.Headers {
+		if header.Key == RequestIDHeader {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// SubmitCommand writes the order on the input topic of the market and waits for the events generated by the engine
+// - The command is processed in the same order as the ones received directly on the input topic and is replayed
+// like any other command when the market is restored from a backup
+func (mkt *marketEngine) SubmitCommand(ctx context.Context, order *model.Order) ([]*model.Event, error) {
+	if mkt.config.commandProducer == nil {
+		return nil, ErrSubscriptionsDisabled
+	}
+	if mkt.output.isStopped() {
+		return nil, ErrMarketStopped
+	}
+	requestID, err := newRequestID()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := order.ToBinary()
+	if err != nil {
+		return nil, err
+	}
+	reply := make(chan commandReply, 1)
+	mkt.requests.Store(requestID, reply)
+	defer mkt.requests.Delete(requestID)
+
+	err = mkt.config.commandProducer.WriteMessages(ctx, net.Message{
+		Value:   raw,
+		Headers: []net.Header{{Key: RequestIDHeader, Value: []byte(requestID)}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return waitForReply(ctx, reply)
+}
+
+// SubmitDirectCommand sends the order for processing by the market without writing it on the input topic and waits
+// for the events generated by the engine
+//
+// The command is processed between the ones received on the input topic and its events are published on the event
+// topic like any other events. Before it is processed the command is written on the direct journal of the market, so
+// the commands received after the last backup are replayed in the same order when the market is restored.
+func (mkt *marketEngine) SubmitDirectCommand(ctx context.Context, order *model.Order) ([]*model.Event, error) {
+	if !mkt.config.directCommands {
+		return nil, ErrDirectCommandsDisabled
+	}
+	if mkt.output.isStopped() {
+		return nil, ErrMarketStopped
+	}
+	if mkt.config.maxOffset != 0 && atomic.AddInt64(&mkt.directCommands, 1) > mkt.config.maxOffset {
+		return nil, ErrCommandLimitReached
+	}
+	requestID, err := newRequestID()
+	if err != nil {
+		return nil, err
+	}
+	raw, err := order.ToBinary()
+	if err != nil {
+		return nil, err
+	}
+	reply := make(chan commandReply, 1)
+	mkt.requests.Store(requestID, reply)
+	defer mkt.requests.Delete(requestID)
+
+	queued := mkt.queueMessage(net.Message{
+		Value:   raw,
+		Headers: []net.Header{{Key: RequestIDHeader, Value: []byte(requestID)}},
+	})
+	if !queued {
+		return nil, ErrMarketStopped
+	}
+	return waitForReply(ctx, reply)
+}
+
+// waitForReply waits for the events generated by a command until the context is done
+func waitForReply(ctx context.Context, reply chan commandReply) ([]*model.Event, error) {
+	select {
+	case result := <-reply:
+		return result.events, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// replyToCommand sends the generated events to the caller that submitted the command if it is still waiting for them
+func (mkt *marketEngine) replyToCommand(msg net.Message, events []*model.Event) {
+	mkt.sendReply(msg, commandReply{events: events})
+}
+
+// failCommand sends the reason why a command was not processed to the caller that submitted it
+func (mkt *marketEngine) failCommand(msg net.Message, err error) {
+	mkt.sendReply(msg, commandReply{err: err})
+}
+
+func (mkt *marketEngine) sendReply(msg net.Message, result commandReply) {
+	requestID := getRequestID(msg)
+	if requestID == "" {
+		return
+	}
+	reply, ok := mkt.requests.Load(requestID)
+	if !ok {
+		return
+	}
+	select {
+	case reply.(chan commandReply) <- result:
+	default:
+		// the command was delivered again by the consumer and the caller already received its events
+	}
+}
+
+// publishToSubscribers sends the events published on the event topic to the callers and the subscribers of the market
+func (mkt *marketEngine) publishToSubscribers(event *engine.Event) {
+	if mkt.eventStream == nil && !mkt.config.directCommands {
+		return
+	}
+	events := make([]*model.Event, len(event.Events))
+	for i := range event.Events {
+		events[i] = &event.Events[i]
+	}
+	mkt.replyToCommand(event.Msg, events)
+	if mkt.eventStream != nil {
+		mkt.eventStream.Publish(events)
+	}
+}
+
+// SubscribeEvents creates a subscription for the events generated by the market after the given sequence id
+func (mkt *marketEngine) SubscribeEvents(fromSeqID uint64) (*EventSubscription, error) {
+	if mkt.eventStream == nil {
+		return nil, ErrSubscriptionsDisabled
+	}
+	return mkt.eventStream.Subscribe(fromSeqID)
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// Reasons for which an input message is sent to the dead-letter topic
+const (
+	DeadLetterReasonDecode  = "decode"
+	DeadLetterReasonInvalid = "invalid"
+)
+
+// Headers added to the original headers of a rejected input message
+const (
+	HeaderDeadLetterReason    = "dead_letter_reason"
+	HeaderDeadLetterError     = "dead_letter_error"
+	HeaderDeadLetterTopic     = "dead_letter_topic"
+	HeaderDeadLetterPartition = "dead_letter_partition"
+	HeaderDeadLetterOffset    = "dead_letter_offset"
+)
+
+// ErrInvalidOrder is the error of the input messages that were decoded but failed validation
+var ErrInvalidOrder = errors.New("invalid order")
+
+// rejectMessage counts an input message that could not be processed and sends it to the dead-letter topic
+// - The original key and value are kept so the message can be written back on the input topic once fixed
+func (mkt *marketEngine) rejectMessage(event *engine.Event, reason string, err error) {
+	deadLetterCount.WithLabelValues(mkt.name, reason).Inc()
+	if mkt.config.deadLetterProducer == nil {
+		return
+	}
+	headers := make([]net.Header, 0, len(event.Msg.Headers)+6)
+	headers = append(headers, event.Msg.Headers...)
+	headers = append(headers,
+		net.Header{Key: HeaderMarket, Value: []byte(mkt.name)},
+		net.Header{Key: HeaderDeadLetterReason, Value: []byte(reason)},
+		net.Header{Key: HeaderDeadLetterError, Value: []byte(err.Error())},
+		net.Header{Key: HeaderDeadLetterTopic, Value: []byte(event.Msg.Topic)},
+		net.Header{Key: HeaderDeadLetterPartition, Value: []byte(strconv.Itoa(event.Msg.Partition))},
+		net.Header{Key: HeaderDeadLetterOffset, Value: []byte(strconv.FormatInt(event.Msg.Offset, 10))},
+	)
+	mkt.deadLetters <- net.Message{
+		Key:     event.Msg.Key,
+		Value:   event.Msg.Value,
+		Headers: headers,
+	}
+}
+
+// PublishDeadLetters listens for rejected input messages and publishes them to the dead-letter topic
+func (mkt *marketEngine) PublishDeadLetters() {
+	log.Debug().Str("section", "server").Str("action", "init").Str("market", mkt.name).Msg("Starting dead-letter publisher process")
+	for msg := range mkt.deadLetters {
+		if err := mkt.writeOutput(mkt.config.deadLetterProducer, "dead_letter", msg); err != nil {
+			mkt.stopOutput("dead_letter", err)
+		}
+	}
+	log.Info().Str("section", "server").Str("action", "terminate").Str("market", mkt.name).Msg("Closing dead-letter publisher process")
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDeadLetters(t *testing.T) {
+	Convey("Given a market that routes the rejected input messages to a dead-letter topic", t, func() {
+		dir, err := ioutil.TempDir("", "dead_letters")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		input := net.NewMemoryTopic("btcusd")
+		deadLetters := net.NewMemoryTopic("dead_letters")
+		decodeCount := testutil.ToFloat64(deadLetterCount.WithLabelValues("btcusd", DeadLetterReasonDecode))
+		invalidCount := testutil.ToFloat64(deadLetterCount.WithLabelValues("btcusd", DeadLetterReasonInvalid))
+		output := net.NewMemoryTopic("events")
+		mkt := startTestMarket(dir, input, output, MarketEngineConfig{deadLetterProducer: net.NewMemoryProducer(deadLetters)})
+		defer mkt.Close()
+		defer mkt.consumer.Close()
+
+		invalid := newTestOrder(2, model.MarketSide_Buy)
+		invalid.Amount = 0
+		raw, err := invalid.ToBinary()
+		So(err, ShouldBeNil)
+		So(net.NewMemoryProducer(input).WriteMessages(context.Background(),
+			net.Message{Key: []byte("btcusd"), Value: []byte{0xff, 0xff, 0xff}, Headers: []net.Header{{Key: "request_id", Value: []byte("abc")}}},
+			net.Message{Value: raw},
+		), ShouldBeNil)
+		msgs := waitMessages(deadLetters, 2)
+		So(msgs, ShouldHaveLength, 2)
+		// the owner of the invalid order is still notified
+		So(waitMessages(output, 1), ShouldHaveLength, 1)
+
+		Convey("An undecodable message should be published unchanged with the reason and its input position", func() {
+			So(string(msgs[0].Key), ShouldEqual, "btcusd")
+			So(msgs[0].Value, ShouldResemble, []byte{0xff, 0xff, 0xff})
+			So(headerValue(msgs[0], "request_id"), ShouldEqual, "abc")
+			So(headerValue(msgs[0], HeaderMarket), ShouldEqual, "btcusd")
+			So(headerValue(msgs[0], HeaderDeadLetterReason), ShouldEqual, DeadLetterReasonDecode)
+			So(headerValue(msgs[0], HeaderDeadLetterError), ShouldNotBeEmpty)
+			So(headerValue(msgs[0], HeaderDeadLetterTopic), ShouldEqual, "btcusd")
+			So(headerValue(msgs[0], HeaderDeadLetterOffset), ShouldEqual, "0")
+			So(testutil.ToFloat64(deadLetterCount.WithLabelValues("btcusd", DeadLetterReasonDecode)), ShouldEqual, decodeCount+1)
+		})
+
+		Convey("An order that fails validation should be published with the invalid reason", func() {
+			So(msgs[1].Value, ShouldResemble, raw)
+			So(headerValue(msgs[1], HeaderDeadLetterReason), ShouldEqual, DeadLetterReasonInvalid)
+			So(headerValue(msgs[1], HeaderDeadLetterError), ShouldEqual, ErrInvalidOrder.Error())
+			So(headerValue(msgs[1], HeaderDeadLetterOffset), ShouldEqual, "1")
+			So(testutil.ToFloat64(deadLetterCount.WithLabelValues("btcusd", DeadLetterReasonInvalid)), ShouldEqual, invalidCount+1)
+		})
+	})
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// ScheduleDepthSnapshots sets up an interval at which to publish a full snapshot of the market depth
+func (mkt *marketEngine) ScheduleDepthSnapshots() {
+	if mkt.config.config.Depth.Interval == 0 {
+		log.Warn().Str("section", "depth").Str("action", "schedule").Str("market", mkt.name).Msg("Depth snapshots disabled for market")
+		return
+	}
+	for mkt.waitSchedule(time.Duration(mkt.config.config.Depth.Interval) * time.Second) {
+		select {
+		case mkt.depthSnapshot <- true:
+		case <-mkt.halted:
+			return
+		}
+	}
+}
+
+// publishDepthSnapshot sends a full snapshot of the price levels to the depth publisher
+// - Must be called from the order matching process since it reads the order book
+func (mkt *marketEngine) publishDepthSnapshot() {
+	if mkt.config.depthProducer == nil {
+		return
+	}
+	book := mkt.engine.GetOrderBook()
+	snapshot := book.GetDepthSnapshot(mkt.config.config.Depth.Levels)
+	snapshot.Checksum = book.GetDepthChecksum(mkt.config.config.Depth.ChecksumLevels)
+	mkt.depth <- model.NewDepthSnapshotMessage(mkt.name, snapshot)
+}
+
+// publishDepthUpdate sends the price levels changed since the last update to the depth publisher
+// and forwards them with the events of the order when the market data is streamed to websocket clients
+// - The changed levels are always flushed so they don't accumulate when the depth topic is disabled
+func (mkt *marketEngine) publishDepthUpdate(event *engine.Event) {
+	book := mkt.engine.GetOrderBook()
+	update, changed := book.FlushDepthUpdate()
+	if !changed || (mkt.config.depthProducer == nil && mkt.feed == nil) {
+		return
+	}
+	update.Checksum = book.GetDepthChecksum(mkt.config.config.Depth.ChecksumLevels)
+	if mkt.feed != nil {
+		event.Depth = &update
+	}
+	if mkt.config.depthProducer != nil {
+		mkt.depth <- model.NewDepthUpdateMessage(mkt.name, update)
+	}
+}
+
+// PublishDepth listens for depth snapshots and updates and publishes them to the depth topic
+func (mkt *marketEngine) PublishDepth() {
+	log.Debug().Str("section", "server").Str("action", "init").Str("market", mkt.name).Msg("Starting depth publisher process")
+	for msg := range mkt.depth {
+		raw, err := msg.ToBinary()
+		if err != nil {
+			log.Error().Err(err).Str("section", "depth").Str("action", "encode").Str("market", mkt.name).Msg("Unable to encode depth message")
+			continue
+		}
+		if err := mkt.writeOutput(mkt.config.depthProducer, "depth", net.Message{Value: raw}); err != nil {
+			mkt.stopOutput("depth", err)
+		}
+	}
+	log.Info().Str("section", "server").Str("action", "terminate").Str("market", mkt.name).Msg("Closing depth publisher process")
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// DirectAfterOffsetHeader is the header of the journaled direct commands with the offset of the last input message
+// processed before them, or -1 if no input message was processed yet
+const DirectAfterOffsetHeader = "after_offset"
+
+// directJournalTopic returns the name of the journal that keeps the commands received directly by a market
+func directJournalTopic(market string) string {
+	return market + ".direct"
+}
+
+// inputPosition returns the offset of the last input message processed or -1 if no input message was processed
+func inputPosition(topic string, offset int64) int64 {
+	if topic == "" {
+		return -1
+	}
+	return offset
+}
+
+// processDirectCommand writes a command received directly on the journal of the market and processes it
+//
+// The journal keeps the position of the command between the input messages, so the commands received after the
+// last backup are replayed in the same order when the market is restored and generate the same sequence ids.
+// The command is rejected if it can't be written on the journal.
+func (mkt *marketEngine) processDirectCommand(event engine.Event, afterOffset int64) bool {
+	if event.Msg.Time.IsZero() {
+		// the time is journaled with the command so the replayed command updates the same candles
+		event.Msg.Time = time.Now()
+	}
+	if mkt.directProducer != nil {
+		if err := mkt.journalDirectCommand(event.Msg, afterOffset); err != nil {
+			log.Error().Err(err).Str("section", "server").Str("action", "journal_command").Str("market", mkt.name).Msg("Unable to journal direct command")
+			ordersQueued.WithLabelValues(mkt.name).Dec()
+			mkt.failCommand(event.Msg, err)
+			return false
+		}
+		mkt.directOffset++
+	}
+	return mkt.processCommand(event)
+}
+
+// journalDirectCommand appends the command and the offset of the input message processed before it to the journal
+func (mkt *marketEngine) journalDirectCommand(msg net.Message, afterOffset int64) error {
+	headers := make([]net.Header, 0, len(msg.Headers)+1)
+	headers = append(headers, msg.Headers...)
+	headers = append(headers, net.Header{Key: DirectAfterOffsetHeader, Value: []byte(strconv.FormatInt(afterOffset, 10))})
+	onRetry := func(attempt int, err error, wait time.Duration) {
+		log.Warn().Err(err).Str("section", "server").Str("action", "journal_command").Str("market", mkt.name).
+			Int("attempt", attempt).Dur("retry_in", wait).Msg("Unable to journal direct command. Retrying")
+	}
+	return net.WriteMessagesWithRetry(context.Background(), mkt.directProducer, mkt.config.retry, onRetry, net.Message{Value: msg.Value, Headers: headers, Time: msg.Time})
+}
+
+// loadDirectCommands reads the journaled direct commands that were not included in the backup of the market
+func (mkt *marketEngine) loadDirectCommands() error {
+	if mkt.config.directJournal == nil {
+		return nil
+	}
+	pending, err := mkt.config.directJournal.Messages(mkt.directOffset)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		log.Info().Str("section", "backup").Str("action", "import").Str("market", mkt.name).
+			Int64("direct_offset", mkt.directOffset).Int("direct_commands", len(pending)).
+			Msg("Replaying direct commands from journal")
+	}
+	mkt.directPending = pending
+	return nil
+}
+
+// replayDirectCommands processes the journaled direct commands that followed the input message with the given offset
+// and returns the number of commands sent to the event publisher
+func (mkt *marketEngine) replayDirectCommands(afterOffset int64) uint64 {
+	var processed uint64
+	for len(mkt.directPending) > 0 {
+		msg := mkt.directPending[0]
+		if directAfterOffset(msg) > afterOffset {
+			break
+		}
+		mkt.directPending = mkt.directPending[1:]
+		mkt.directOffset++
+		// the command is processed like it was when it was received directly
+		msg.Topic = ""
+		msg.Offset = 0
+		event := engine.NewEvent(msg)
+		event.Decode()
+		ordersQueued.WithLabelValues(mkt.name).Inc()
+		if mkt.processCommand(event) {
+			processed++
+		}
+	}
+	return processed
+}
+
+// directAfterOffset returns the offset of the input message processed before a journaled direct command
+func directAfterOffset(msg net.Message) int64 {
+	for _, header := range msg.Headers {
+		if header.Key == DirectAfterOffsetHeader {
+			if offset, err := strconv.ParseInt(string(header.Value), 10, 64); err == nil {
+				return offset
+			}
+		}
+	}
+	return -1
+}
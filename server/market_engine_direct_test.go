@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// newDirectMarket starts a market that journals its direct commands in the given directory
+func newDirectMarket(dir string, input, output *net.MemoryTopic) *marketEngine {
+	journal, err := net.OpenFileJournal(filepath.Join(dir, directJournalTopic("btcusd")+".journal"), directJournalTopic("btcusd"), true)
+	So(err, ShouldBeNil)
+	return startTestMarket(dir, input, output, MarketEngineConfig{directCommands: true, directJournal: journal})
+}
+
+// publishedEvents decodes the events published on the topic and returns their types and sequence ids
+func publishedEvents(topic *net.MemoryTopic, count int) []string {
+	for i := 0; i < 500 && len(topic.Messages()) < count; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	published := make([]string, 0, count)
+	for _, msg := range topic.Messages() {
+		var event model.Event
+		So(event.FromBinary(msg.Value), ShouldBeNil)
+		published = append(published, fmt.Sprintf("%s:%d", event.Type, event.SeqID))
+	}
+	return published
+}
+
+func TestDirectCommands(t *testing.T) {
+	Convey("Given a market that received direct commands between the commands of its input topic", t, func() {
+		dir, err := ioutil.TempDir("", "direct_commands")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		input := net.NewMemoryTopic("btcusd")
+		output := net.NewMemoryTopic("events")
+		mkt := newDirectMarket(dir, input, output)
+		_, err = mkt.SubmitDirectCommand(ctx, newTestOrder(1, model.MarketSide_Sell))
+		So(err, ShouldBeNil)
+		raw, err := newTestOrder(2, model.MarketSide_Sell).ToBinary()
+		So(err, ShouldBeNil)
+		So(net.NewMemoryProducer(input).WriteMessages(ctx, net.Message{Value: raw}), ShouldBeNil)
+		So(publishedEvents(output, 2), ShouldHaveLength, 2)
+		_, err = mkt.SubmitDirectCommand(ctx, newTestOrder(3, model.MarketSide_Buy))
+		So(err, ShouldBeNil)
+		expected := publishedEvents(output, 6)
+		So(expected, ShouldHaveLength, 6)
+		mkt.consumer.Close()
+		mkt.Close()
+
+		Convey("A restarted market should replay the direct commands in the same order", func() {
+			replayed := net.NewMemoryTopic("events")
+			restarted := newDirectMarket(dir, input, replayed)
+			defer restarted.Close()
+			defer restarted.consumer.Close()
+			So(publishedEvents(replayed, 6), ShouldResemble, expected)
+
+			Convey("and continue with the sequence ids of the new commands", func() {
+				events, err := restarted.SubmitDirectCommand(ctx, newTestOrder(4, model.MarketSide_Buy))
+				So(err, ShouldBeNil)
+				So(events, ShouldNotBeEmpty)
+				So(events[0].SeqID, ShouldEqual, 7)
+				So(restarted.directOffset, ShouldEqual, 3)
+			})
+		})
+
+		Convey("Commands sent after the market was closed should be refused", func() {
+			_, err := mkt.SubmitDirectCommand(ctx, newTestOrder(4, model.MarketSide_Buy))
+			So(err, ShouldEqual, ErrMarketStopped)
+		})
+	})
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// ScheduleOrderFeedSnapshots sets up an interval at which to publish a snapshot of all open orders
+func (mkt *marketEngine) ScheduleOrderFeedSnapshots() {
+	if mkt.config.config.OrderFeed.Interval == 0 {
+		log.Warn().Str("section", "order_feed").Str("action", "schedule").Str("market", mkt.name).Msg("Order feed snapshots disabled for market")
+		return
+	}
+	for mkt.waitSchedule(time.Duration(mkt.config.config.OrderFeed.Interval) * time.Second) {
+		select {
+		case mkt.orderFeedSnapshot <- true:
+		case <-mkt.halted:
+			return
+		}
+	}
+}
+
+// publishOrderFeedSnapshot sends a snapshot of all open orders to the order feed publisher
+// - Must be called from the order matching process since it reads the order book
+func (mkt *marketEngine) publishOrderFeedSnapshot() {
+	if mkt.config.orderFeedProducer == nil {
+		return
+	}
+	snapshot := mkt.engine.GetOrderBook().GetOrderFeedSnapshot()
+	mkt.orderFeed <- model.NewOrderFeedSnapshotMessage(mkt.name, snapshot)
+}
+
+// publishOrderFeedUpdates sends the order feed updates generated since the last call to the order feed publisher
+// - The updates are always flushed so they don't accumulate when the order feed topic is disabled
+func (mkt *marketEngine) publishOrderFeedUpdates() {
+	updates := mkt.engine.GetOrderBook().FlushOrderFeed()
+	if len(updates) == 0 || mkt.config.orderFeedProducer == nil {
+		return
+	}
+	mkt.orderFeed <- model.NewOrderFeedUpdatesMessage(mkt.name, updates)
+}
+
+// PublishOrderFeed listens for order feed snapshots and updates, anonymises the order ids and publishes them
+func (mkt *marketEngine) PublishOrderFeed() {
+	log.Debug().Str("section", "server").Str("action", "init").Str("market", mkt.name).Msg("Starting order feed publisher process")
+	key := []byte(mkt.config.config.OrderFeed.AnonymiseKey)
+	for msg := range mkt.orderFeed {
+		anonymiseOrderFeed(key, &msg)
+		raw, err := msg.ToBinary()
+		if err != nil {
+			log.Error().Err(err).Str("section", "order_feed").Str("action", "encode").Str("market", mkt.name).Msg("Unable to encode order feed message")
+			continue
+		}
+		if err := mkt.writeOutput(mkt.config.orderFeedProducer, "order_feed", net.Message{Value: raw}); err != nil {
+			mkt.stopOutput("order_feed", err)
+		}
+	}
+	log.Info().Str("section", "server").Str("action", "terminate").Str("market", mkt.name).Msg("Closing order feed publisher process")
+}
+
+// anonymiseOrderFeed replaces all order ids from the message with their anonymised value
+func anonymiseOrderFeed(key []byte, msg *model.OrderFeedMessage) {
+	if snapshot := msg.GetSnapshot(); snapshot != nil {
+		for _, order := range snapshot.Bids {
+			order.OrderID = anonymiseOrderID(key, order.OrderID)
+		}
+		for _, order := range snapshot.Asks {
+			order.OrderID = anonymiseOrderID(key, order.OrderID)
+		}
+	}
+	if updates := msg.GetUpdates(); updates != nil {
+		for _, update := range updates.Updates {
+			update.OrderID = anonymiseOrderID(key, update.OrderID)
+		}
+	}
+}
+
+// anonymiseOrderID returns a stable public id for the order that can't be traced back to the order id without the key
+func anonymiseOrderID(key []byte, id uint64) uint64 {
+	raw := make([]byte, 8)
+	binary.BigEndian.PutUint64(raw, id)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(raw)
+	return binary.BigEndian.Uint64(mac.Sum(nil)[:8])
+}
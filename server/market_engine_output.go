@@ -0,0 +1,211 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// ErrMarketStopped is returned for the commands sent to a market that was stopped because its events could not be published
+var ErrMarketStopped = errors.New("market stopped")
+
+// outputBreaker pauses the processing of a market while its events can't be published
+//
+// The breaker opens when a write of the events or of the market data fails and closes once all the publishers write
+// again. No command is processed while it is open so the order book does not move ahead of the published messages.
+// A market whose messages could not be published after all the retries is stopped and is restored from its last
+// backup on restart. The breaker keeps the sequence id of the last event written on each event topic so the events
+// regenerated on restore are not published again on the topics that already received them.
+type outputBreaker struct {
+	lock    sync.Mutex
+	changed *sync.Cond
+	// number of publishers retrying a failed write
+	failing int
+	stopped bool
+	// number of commands whose events were published
+	published uint64
+	// sequence id of the last event published on all the topics
+	lastSeqID uint64
+	// sequence id of the last event written on each event topic
+	topics map[string]uint64
+}
+
+// newOutputBreaker creates a closed breaker
+func newOutputBreaker() *outputBreaker {
+	breaker := &outputBreaker{topics: make(map[string]uint64)}
+	breaker.changed = sync.NewCond(&breaker.lock)
+	return breaker
+}
+
+// trip opens the breaker while a publisher retries a failed write
+func (breaker *outputBreaker) trip() {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	breaker.failing++
+}
+
+// reset marks the write of a publisher as done and returns true if the breaker closed and the processing of commands
+// resumed
+func (breaker *outputBreaker) reset() bool {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	if breaker.failing == 0 {
+		return false
+	}
+	breaker.failing--
+	if breaker.failing == 0 {
+		breaker.changed.Broadcast()
+		return true
+	}
+	return false
+}
+
+// stop marks the market as stopped and releases the processes waiting for the breaker
+// - Returns false if the market was already stopped
+func (breaker *outputBreaker) stop() bool {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	if breaker.stopped {
+		return false
+	}
+	breaker.stopped = true
+	breaker.changed.Broadcast()
+	return true
+}
+
+// isStopped checks if the market was stopped
+func (breaker *outputBreaker) isStopped() bool {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	return breaker.stopped
+}
+
+// published marks the events of a command as published
+func (breaker *outputBreaker) markPublished(lastSeqID uint64) {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	breaker.published++
+	if lastSeqID > breaker.lastSeqID {
+		breaker.lastSeqID = lastSeqID
+	}
+	breaker.changed.Broadcast()
+}
+
+// markTopicPublished records the sequence id of the last event written on an event topic
+func (breaker *outputBreaker) markTopicPublished(topic string, seqID uint64) {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	if seqID > breaker.topics[topic] {
+		breaker.topics[topic] = seqID
+	}
+}
+
+// restore sets the sequence ids of the last published events saved in the backup of the market
+func (breaker *outputBreaker) restore(lastSeqID uint64, topics map[string]uint64) {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	breaker.lastSeqID = lastSeqID
+	breaker.topics = make(map[string]uint64, len(topics))
+	for topic, seqID := range topics {
+		breaker.topics[topic] = seqID
+	}
+}
+
+// topicSeqID returns the sequence id of the last event published on an event topic
+func (breaker *outputBreaker) topicSeqID(topic string) uint64 {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	if breaker.topics[topic] > breaker.lastSeqID {
+		return breaker.topics[topic]
+	}
+	return breaker.lastSeqID
+}
+
+// publishedTopics returns a copy of the sequence ids of the last events written on each event topic
+func (breaker *outputBreaker) publishedTopics() map[string]uint64 {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	topics := make(map[string]uint64, len(breaker.topics))
+	for topic, seqID := range breaker.topics {
+		topics[topic] = seqID
+	}
+	return topics
+}
+
+// lastPublishedSeqID returns the sequence id of the last published event
+func (breaker *outputBreaker) lastPublishedSeqID() uint64 {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	return breaker.lastSeqID
+}
+
+// waitAvailable blocks while the breaker is open and returns false if the market was stopped
+func (breaker *outputBreaker) waitAvailable() bool {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	for breaker.failing > 0 && !breaker.stopped {
+		breaker.changed.Wait()
+	}
+	return !breaker.stopped
+}
+
+// waitPublished blocks until the events of the given number of commands are published and returns false if the
+// market was stopped before
+func (breaker *outputBreaker) waitPublished(count uint64) bool {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+	for breaker.published < count && !breaker.stopped {
+		breaker.changed.Wait()
+	}
+	return !breaker.stopped
+}
+
+// writeOutput publishes the messages with the configured retry policy and opens the breaker while the writes fail
+// - The section identifies the publisher in the logs
+// - Nothing is written once the market was stopped, the messages are generated again when it is restored
+func (mkt *marketEngine) writeOutput(producer net.Producer, section string, msgs ...net.Message) error {
+	if mkt.output.isStopped() {
+		return ErrMarketStopped
+	}
+	tripped := false
+	onRetry := func(attempt int, err error, wait time.Duration) {
+		if attempt == 1 {
+			tripped = true
+			mkt.output.trip()
+			outputAvailable.WithLabelValues(mkt.name).Set(0)
+		}
+		publishRetryCount.WithLabelValues(mkt.name).Inc()
+		log.Warn().Err(err).Str("section", section).Str("action", "publish").Str("market", mkt.name).
+			Int("attempt", attempt).Dur("retry_in", wait).Msg("Unable to publish messages. Retrying")
+	}
+	err := net.WriteMessagesWithRetry(context.Background(), producer, mkt.config.retry, onRetry, msgs...)
+	if err == nil && tripped && mkt.output.reset() {
+		outputAvailable.WithLabelValues(mkt.name).Set(1)
+	}
+	return err
+}
+
+// stopOutput stops the market after the messages of a publisher could not be published
+// - The events that were not published are generated again when the market is restored from its last backup
+// - The sequence ids of the last published events are saved in that backup and restored with the market
+func (mkt *marketEngine) stopOutput(section string, err error) {
+	if !mkt.output.stop() {
+		return
+	}
+	mkt.halt()
+	if err := mkt.savePublishedSeqIDs(); err != nil {
+		log.Error().Err(err).Str("section", "backup").Str("action", "export").Str("market", mkt.name).Msg("Unable to save the last published sequence ids")
+	}
+	publishFailureCount.WithLabelValues(mkt.name).Inc()
+	log.Error().Err(err).Str("section", section).Str("action", "publish").Str("market", mkt.name).
+		Uint64("last_published_seqid", mkt.output.lastPublishedSeqID()).
+		Msg("Unable to publish messages. Market stopped")
+	if err := mkt.consumer.Close(); err != nil {
+		log.Error().Err(err).Str("section", "server").Str("action", "terminate").Str("market", mkt.name).Msg("Unable to close consumer")
+	}
+}
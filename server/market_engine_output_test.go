@@ -0,0 +1,267 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// flakyProducer fails the given number of writes before storing the messages in a memory topic
+type flakyProducer struct {
+	net.Producer
+	lock     sync.Mutex
+	failures int
+	writes   int
+}
+
+func (producer *flakyProducer) WriteMessages(ctx context.Context, msgs ...net.Message) error {
+	producer.lock.Lock()
+	producer.writes++
+	failed := producer.writes <= producer.failures
+	producer.lock.Unlock()
+	if failed {
+		return errors.New("broker not available")
+	}
+	return producer.Producer.WriteMessages(ctx, msgs...)
+}
+
+// fail makes all the next writes of the producer fail
+func (producer *flakyProducer) fail() {
+	producer.lock.Lock()
+	defer producer.lock.Unlock()
+	producer.failures = producer.writes + 1000
+}
+
+// waitStopped waits until the output of the market is stopped
+func waitStopped(mkt *marketEngine) bool {
+	for i := 0; i < 500 && !mkt.output.isStopped(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return mkt.output.isStopped()
+}
+
+// waitBackup waits until the backup of the market is written in the directory
+func waitBackup(dir string) bool {
+	for i := 0; i < 500; i++ {
+		if _, err := os.Stat(filepath.Join(dir, "btcusd.backup")); err == nil {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestOutputBreaker(t *testing.T) {
+	Convey("Given a breaker opened by two publishers", t, func() {
+		breaker := newOutputBreaker()
+		breaker.trip()
+		breaker.trip()
+
+		Convey("It should stay open until both publishers write again", func() {
+			So(breaker.reset(), ShouldBeFalse)
+			So(breaker.failing, ShouldEqual, 1)
+			So(breaker.reset(), ShouldBeTrue)
+			So(breaker.waitAvailable(), ShouldBeTrue)
+		})
+
+		Convey("Only the first stop should be reported", func() {
+			So(breaker.stop(), ShouldBeTrue)
+			So(breaker.stop(), ShouldBeFalse)
+			So(breaker.waitAvailable(), ShouldBeFalse)
+		})
+	})
+}
+
+func TestMarketDataOutput(t *testing.T) {
+	Convey("Given a market that publishes its depth with a failing producer", t, func() {
+		dir, err := ioutil.TempDir("", "market_data_output")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		input := net.NewMemoryTopic("engine.orders.btcusd")
+		output := net.NewMemoryTopic("engine.events.btcusd")
+		depth := net.NewMemoryTopic("engine.depth.btcusd")
+		producer := &flakyProducer{Producer: net.NewMemoryProducer(depth), failures: 1}
+		retry := net.RetryPolicy{Attempts: 3, Backoff: 1, MaxBackoff: 2}
+
+		Convey("The depth should be published once the producer recovers", func() {
+			mkt := startTestMarket(dir, input, output, MarketEngineConfig{depthProducer: producer, retry: retry})
+			defer mkt.Close()
+			defer mkt.consumer.Close()
+			writeTestOrders(input, newTestOrder(1, model.MarketSide_Sell), newTestOrder(2, model.MarketSide_Sell))
+			So(waitMessages(depth, 2), ShouldHaveLength, 2)
+			So(waitMessages(output, 2), ShouldHaveLength, 2)
+			So(mkt.output.isStopped(), ShouldBeFalse)
+		})
+
+		Convey("The market should be stopped when all the retries fail", func() {
+			producer.failures = 10
+			mkt := startTestMarket(dir, input, output, MarketEngineConfig{depthProducer: producer, retry: retry, directCommands: true})
+			defer mkt.Close()
+			defer mkt.consumer.Close()
+			writeTestOrders(input, newTestOrder(1, model.MarketSide_Sell))
+			So(waitStopped(mkt), ShouldBeTrue)
+			So(depth.Messages(), ShouldBeEmpty)
+
+			Convey("and refuse the commands sent directly", func() {
+				_, err := mkt.SubmitDirectCommand(context.Background(), newTestOrder(2, model.MarketSide_Sell))
+				So(err, ShouldEqual, ErrMarketStopped)
+			})
+		})
+	})
+}
+
+func TestLastPublishedSeqID(t *testing.T) {
+	Convey("Given a backed up market whose depth producer fails after a few commands", t, func() {
+		dir, err := ioutil.TempDir("", "last_published")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		input := net.NewMemoryTopic("engine.orders.btcusd")
+		output := net.NewMemoryTopic("engine.events.btcusd")
+		depth := net.NewMemoryTopic("engine.depth.btcusd")
+		producer := &flakyProducer{Producer: net.NewMemoryProducer(depth)}
+		// the events of the last command are published while the depth write is retried
+		retry := net.RetryPolicy{Attempts: 2, Backoff: 200, MaxBackoff: 200}
+		mkt := startTestMarket(dir, input, output, MarketEngineConfig{depthProducer: producer, retry: retry})
+		defer mkt.Close()
+
+		writeTestOrders(input, newTestOrder(1, model.MarketSide_Sell), newTestOrder(2, model.MarketSide_Sell))
+		So(waitMessages(depth, 2), ShouldHaveLength, 2)
+		mkt.backup <- true
+		So(waitBackup(dir), ShouldBeTrue)
+		producer.fail()
+		writeTestOrders(input, newTestOrder(3, model.MarketSide_Sell))
+		So(waitMessages(output, 3), ShouldHaveLength, 3)
+		So(waitStopped(mkt), ShouldBeTrue)
+
+		Convey("The last published sequence id should be saved in the backup without changing the order book", func() {
+			content, err := ioutil.ReadFile(filepath.Join(dir, "btcusd.backup"))
+			So(err, ShouldBeNil)
+			var market model.MarketBackup
+			So(market.FromBinary(content), ShouldBeNil)
+			So(market.LastPublishedSeqID, ShouldEqual, 3)
+			So(market.Offset, ShouldEqual, 1)
+			So(market.SellOrders, ShouldHaveLength, 2)
+
+			Convey("and restored with the market", func() {
+				restarted := startTestMarket(dir, net.NewMemoryTopic("engine.orders.btcusd"), net.NewMemoryTopic("engine.events.btcusd"), MarketEngineConfig{})
+				defer restarted.Close()
+				defer restarted.consumer.Close()
+				So(restarted.output.lastPublishedSeqID(), ShouldEqual, market.LastPublishedSeqID)
+			})
+
+			Convey("and the events up to it should not be published again when the input is replayed", func() {
+				restarted := startTestMarket(dir, input, output, MarketEngineConfig{})
+				defer restarted.Close()
+				defer restarted.consumer.Close()
+				writeTestOrders(input, newTestOrder(4, model.MarketSide_Sell))
+				msgs := waitMessages(output, 4)
+				time.Sleep(50 * time.Millisecond)
+				So(output.Messages(), ShouldHaveLength, 4)
+				var ev model.Event
+				So(ev.FromBinary(msgs[3].Value), ShouldBeNil)
+				So(ev.SeqID, ShouldEqual, 4)
+			})
+		})
+	})
+}
+
+func TestSchedulersStop(t *testing.T) {
+	Convey("Given a market with the backup and the ticker scheduled", t, func() {
+		dir, err := ioutil.TempDir("", "schedulers_stop")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		config := MarketEngineConfig{
+			consumer:       net.NewMemoryConsumer(net.NewMemoryTopic("engine.orders.btcusd"), 1),
+			tickerProducer: net.NewMemoryProducer(net.NewMemoryTopic("engine.ticker.btcusd")),
+		}
+		config.config.MarketID = "btcusd"
+		config.config.Backup.Interval = 1
+		config.config.Backup.Path = filepath.Join(dir, "btcusd.backup")
+		config.config.Ticker.Throttle = 10
+		mkt := NewMarketEngine(config).(*marketEngine)
+		stopped := make(chan bool, 2)
+		go func() { mkt.ScheduleBackup(); stopped <- true }()
+		go func() { mkt.ScheduleTicker(); stopped <- true }()
+
+		Convey("The schedulers should return once the output of the market is stopped", func() {
+			// the matching process is not running so the ticker scheduler is blocked on its tick
+			time.Sleep(50 * time.Millisecond)
+			mkt.stopOutput("server", errors.New("broker not available"))
+			returned := 0
+			timeout := time.After(time.Second)
+			for returned < 2 {
+				select {
+				case <-stopped:
+					returned++
+					continue
+				case <-timeout:
+				}
+				break
+			}
+			So(returned, ShouldEqual, 2)
+		})
+	})
+}
+
+func TestPublishedSeqIDsByTopic(t *testing.T) {
+	Convey("Given a market that was never backed up whose trade topic fails while its other events are published", t, func() {
+		dir, err := ioutil.TempDir("", "published_topics")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		input := net.NewMemoryTopic("engine.orders.btcusd")
+		output := net.NewMemoryTopic("engine.events.btcusd")
+		trades := net.NewMemoryTopic("engine.trades.btcusd")
+		tradeProducer := &flakyProducer{Producer: net.NewMemoryProducer(trades)}
+		config := MarketEngineConfig{
+			retry:          net.RetryPolicy{Attempts: 2, Backoff: 1, MaxBackoff: 1},
+			eventProducers: map[model.EventType]net.Producer{model.EventType_NewTrade: tradeProducer},
+		}
+		config.config.Publish.Topic = "engine.events.btcusd"
+		config.config.EventTopics.Trades.Topic = "engine.trades.btcusd"
+		mkt := startTestMarket(dir, input, output, config)
+		defer mkt.Close()
+
+		writeTestOrders(input, newTestOrder(1, model.MarketSide_Sell))
+		So(waitMessages(output, 1), ShouldHaveLength, 1)
+		tradeProducer.fail()
+		writeTestOrders(input, newTestOrder(2, model.MarketSide_Buy))
+		So(waitStopped(mkt), ShouldBeTrue)
+		So(output.Messages(), ShouldHaveLength, 4)
+		So(trades.Messages(), ShouldBeEmpty)
+
+		Convey("The sequence ids published on each topic should be saved in a new backup", func() {
+			content, err := ioutil.ReadFile(filepath.Join(dir, "btcusd.backup"))
+			So(err, ShouldBeNil)
+			var market model.MarketBackup
+			So(market.FromBinary(content), ShouldBeNil)
+			So(market.LastPublishedSeqID, ShouldEqual, 1)
+			So(market.PublishedSeqIDs, ShouldResemble, map[string]uint64{"engine.events.btcusd": 5})
+			So(market.Topic, ShouldBeEmpty)
+			So(market.SellOrders, ShouldBeEmpty)
+
+			Convey("and the replayed events should only be published on the topic that did not receive them", func() {
+				config.eventProducers = map[model.EventType]net.Producer{model.EventType_NewTrade: net.NewMemoryProducer(trades)}
+				restarted := startTestMarket(dir, input, output, config)
+				defer restarted.Close()
+				defer restarted.consumer.Close()
+				tradeMsgs := waitMessages(trades, 1)
+				So(tradeMsgs, ShouldHaveLength, 1)
+				var trade model.Event
+				So(trade.FromBinary(tradeMsgs[0].Value), ShouldBeNil)
+				So(trade.Type, ShouldEqual, model.EventType_NewTrade)
+				So(trade.SeqID, ShouldEqual, 3)
+				So(output.Messages(), ShouldHaveLength, 4)
+			})
+		})
+	})
+}
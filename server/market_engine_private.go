@@ -0,0 +1,66 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// Headers added to the events of the private streams
+const (
+	HeaderOwnerID    = "owner_id"
+	HeaderOwnerSeqID = "owner_seq_id"
+	HeaderDropCopy   = "drop_copy"
+)
+
+// routePrivateEvents sends the events generated by an order to the private streams of their owners
+// - Must be called from the order matching process so that the owner sequences match the market backup
+func (mkt *marketEngine) routePrivateEvents(events []model.Event) {
+	if mkt.privateStream == nil {
+		return
+	}
+	routed := mkt.privateStream.Route(events)
+	if len(routed) > 0 {
+		mkt.private <- routed
+	}
+}
+
+// PublishPrivateEvents listens for the events of the private streams and publishes them keyed by owner
+func (mkt *marketEngine) PublishPrivateEvents() {
+	log.Debug().Str("section", "server").Str("action", "init").Str("market", mkt.name).Msg("Starting private stream publisher process")
+	for routed := range mkt.private {
+		msgs := make([]net.Message, 0, len(routed))
+		for _, ownerEvent := range routed {
+			raw, err := ownerEvent.ToBinary()
+			if err != nil {
+				log.Error().Err(err).Str("section", "private").Str("action", "encode").Str("market", mkt.name).Uint64("owner_id", ownerEvent.OwnerID).Msg("Unable to encode owner event")
+				continue
+			}
+			msgs = append(msgs, net.Message{
+				Key:     []byte(strconv.FormatUint(ownerEvent.OwnerID, 10)),
+				Value:   raw,
+				Headers: ownerEventHeaders(mkt.name, ownerEvent),
+			})
+		}
+		if err := mkt.writeOutput(mkt.config.privateProducer, "private", msgs...); err != nil {
+			mkt.stopOutput("private", err)
+		}
+	}
+	log.Info().Str("section", "server").Str("action", "terminate").Str("market", mkt.name).Msg("Closing private stream publisher process")
+}
+
+// ownerEventHeaders returns the headers of the message of an event published on the stream of an owner
+func ownerEventHeaders(market string, ownerEvent *model.OwnerEvent) []net.Header {
+	return []net.Header{
+		{Key: HeaderOwnerID, Value: []byte(strconv.FormatUint(ownerEvent.OwnerID, 10))},
+		{Key: HeaderOwnerSeqID, Value: []byte(strconv.FormatUint(ownerEvent.OwnerSeqID, 10))},
+		{Key: HeaderDropCopy, Value: []byte(strconv.FormatBool(ownerEvent.DropCopy))},
+		{Key: HeaderEventType, Value: []byte(ownerEvent.Event.GetType().String())},
+		{Key: HeaderMarket, Value: []byte(market)},
+		{Key: HeaderSeqID, Value: []byte(strconv.FormatUint(ownerEvent.Event.GetSeqID(), 10))},
+		{Key: HeaderSchemaVersion, Value: []byte(strconv.Itoa(model.EventSchemaVersion))},
+	}
+}
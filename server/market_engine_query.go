@@ -0,0 +1,171 @@
+package server
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+// DefaultQueryInterval is the default number of milliseconds between two query snapshots
+const DefaultQueryInterval = 100
+
+// MarketSnapshot is a read only copy of the market state used to answer queries without blocking the order matching process
+type MarketSnapshot struct {
+	seqIDs         *model.QuerySeqIDs
+	depth          *model.DepthSnapshot
+	checksumLevels int
+	orders         map[uint64]*model.Order
+	owners         map[uint64][]*model.Order
+	stops          []*model.Order
+}
+
+// querySnapshotSource contains the copies of the market state taken by the order matching process
+type querySnapshotSource struct {
+	backup    *model.MarketBackup
+	depth     *model.DepthSnapshot
+	createdAt time.Time
+}
+
+// newMarketSnapshot indexes the orders of a market backup to answer the queries
+func newMarketSnapshot(source *querySnapshotSource, checksumLevels int) *MarketSnapshot {
+	backup := source.backup
+	snapshot := &MarketSnapshot{
+		seqIDs: &model.QuerySeqIDs{
+			Market:         backup.MarketID,
+			EventSeqID:     backup.EventSeqID,
+			TradeSeqID:     backup.TradeSeqID,
+			OrderFeedSeqID: backup.OrderFeedSeqID,
+			CreatedAt:      source.createdAt.UnixNano(),
+		},
+		depth:          source.depth,
+		checksumLevels: checksumLevels,
+		orders:         make(map[uint64]*model.Order),
+		owners:         make(map[uint64][]*model.Order),
+		stops:          make([]*model.Order, 0, len(backup.StopEntryOrders)+len(backup.StopLossOrders)),
+	}
+	for _, list := range [][]*model.Order{backup.BuyOrders, backup.SellOrders, backup.BuyMarketEntries, backup.SellMarketEntries} {
+		for _, order := range list {
+			snapshot.orders[order.ID] = order
+			snapshot.owners[order.OwnerID] = append(snapshot.owners[order.OwnerID], order)
+		}
+	}
+	snapshot.stops = append(snapshot.stops, backup.StopEntryOrders...)
+	snapshot.stops = append(snapshot.stops, backup.StopLossOrders...)
+	// the pending stop orders can be queried by id as well
+	for _, order := range snapshot.stops {
+		snapshot.orders[order.ID] = order
+	}
+	for _, orders := range snapshot.owners {
+		sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+	}
+	return snapshot
+}
+
+// SeqIDs returns the last sequence ids included in the snapshot
+func (snapshot *MarketSnapshot) SeqIDs() *model.QuerySeqIDs {
+	return snapshot.seqIDs
+}
+
+// Depth returns the price levels of the order book sorted from the best price
+// If limit is greater than zero only the given number of levels is returned for each side
+func (snapshot *MarketSnapshot) Depth(limit int) *model.DepthSnapshot {
+	bids := snapshot.depth.Bids
+	asks := snapshot.depth.Asks
+	if limit > 0 && len(bids) > limit {
+		bids = bids[:limit]
+	}
+	if limit > 0 && len(asks) > limit {
+		asks = asks[:limit]
+	}
+	return &model.DepthSnapshot{
+		SeqID:    snapshot.depth.SeqID,
+		Bids:     bids,
+		Asks:     asks,
+		Checksum: engine.DepthChecksum(snapshot.depth.Bids, snapshot.depth.Asks, snapshot.checksumLevels),
+	}
+}
+
+// Order returns the open or pending stop order with the given id or nil if the order is not in the order book
+func (snapshot *MarketSnapshot) Order(id uint64) *model.QueryOrder {
+	order, ok := snapshot.orders[id]
+	if !ok {
+		return nil
+	}
+	return &model.QueryOrder{SeqID: snapshot.seqIDs.EventSeqID, Order: order}
+}
+
+// OwnerOrders returns the open orders of the given owner sorted by id
+func (snapshot *MarketSnapshot) OwnerOrders(owner uint64) *model.QueryOrders {
+	orders, ok := snapshot.owners[owner]
+	if !ok {
+		orders = make([]*model.Order, 0)
+	}
+	return &model.QueryOrders{SeqID: snapshot.seqIDs.EventSeqID, Orders: orders}
+}
+
+// StopOrders returns the pending stop orders
+func (snapshot *MarketSnapshot) StopOrders() *model.QueryOrders {
+	return &model.QueryOrders{SeqID: snapshot.seqIDs.EventSeqID, Orders: snapshot.stops}
+}
+
+// ScheduleQuerySnapshots sends the current time at the configured interval to refresh the query snapshot
+func (mkt *marketEngine) ScheduleQuerySnapshots() {
+	interval := mkt.config.config.Query.Interval
+	if interval <= 0 {
+		interval = DefaultQueryInterval
+	}
+	for mkt.waitSchedule(time.Duration(interval) * time.Millisecond) {
+		select {
+		case mkt.queryTick <- time.Now():
+		case <-mkt.halted:
+			return
+		}
+	}
+}
+
+// takeQuerySnapshot copies the open orders and the depth of the market if they changed since the last snapshot
+// - Must be called from the order matching process so that the copy is consistent
+// - The windows of recent orders and trades kept in the backups are not needed by the queries and are not copied
+// - The copy is indexed by a separate process so the matching process only pays for the copy
+func (mkt *marketEngine) takeQuerySnapshot(now time.Time) {
+	book := mkt.engine.GetOrderBook()
+	if mkt.querySnapshotTaken && mkt.querySeqID == book.GetLastEventSeqID() {
+		return
+	}
+	backup := book.GetOpenOrders()
+	depth := book.GetDepthSnapshot(0)
+	select {
+	case mkt.querySources <- &querySnapshotSource{backup: &backup, depth: &depth, createdAt: now}:
+		mkt.querySnapshotTaken = true
+		mkt.querySeqID = backup.EventSeqID
+	default:
+		// the previous snapshot is still being indexed so try again on the next tick
+	}
+}
+
+// IndexQuerySnapshots builds the snapshots used to answer the queries from the copies of the market state
+func (mkt *marketEngine) IndexQuerySnapshots() {
+	log.Debug().Str("section", "server").Str("action", "init").Str("market", mkt.name).Msg("Starting query snapshot process")
+	checksumLevels := mkt.config.config.Depth.ChecksumLevels
+	if checksumLevels <= 0 {
+		checksumLevels = engine.DefaultChecksumLevels
+	}
+	for source := range mkt.querySources {
+		snapshot := newMarketSnapshot(source, checksumLevels)
+		mkt.querySnapshotLock.Lock()
+		mkt.querySnapshot = snapshot
+		mkt.querySnapshotLock.Unlock()
+	}
+	log.Info().Str("section", "server").Str("action", "terminate").Str("market", mkt.name).Msg("Closing query snapshot process")
+}
+
+// GetSnapshot returns the last snapshot of the market used to answer queries or nil if none is available
+func (mkt *marketEngine) GetSnapshot() *MarketSnapshot {
+	mkt.querySnapshotLock.RLock()
+	defer mkt.querySnapshotLock.RUnlock()
+	return mkt.querySnapshot
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// startTestMarket starts a market that reads its commands from the input topic and publishes its events on the output
+// topic, the backup of the market is kept in the given directory
+func startTestMarket(dir string, input, output *net.MemoryTopic, config MarketEngineConfig) *marketEngine {
+	config.config.MarketID = "btcusd"
+	config.config.PricePrecision = 8
+	config.config.VolumePrecision = 8
+	config.config.Backup.Path = filepath.Join(dir, "btcusd.backup")
+	config.producer = net.NewMemoryProducer(output)
+	config.consumer = net.NewMemoryConsumer(input, 100)
+	mkt := NewMarketEngine(config).(*marketEngine)
+	mkt.Start(context.Background())
+	go loopMarketReceive("btcusd", mkt)
+	return mkt
+}
+
+func newTestOrder(id uint64, side model.MarketSide) *model.Order {
+	return &model.Order{
+		ID:        id,
+		EventType: model.CommandType_NewOrder,
+		Market:    "btcusd",
+		Type:      model.OrderType_Limit,
+		Side:      side,
+		Price:     100,
+		Amount:    1,
+		OwnerID:   id,
+	}
+}
+
+// writeTestOrders writes the orders on the input topic of a market
+func writeTestOrders(input *net.MemoryTopic, orders ...*model.Order) {
+	msgs := make([]net.Message, len(orders))
+	for i, order := range orders {
+		raw, err := order.ToBinary()
+		So(err, ShouldBeNil)
+		msgs[i] = net.Message{Value: raw}
+	}
+	So(net.NewMemoryProducer(input).WriteMessages(context.Background(), msgs...), ShouldBeNil)
+}
+
+// waitMessages waits until the topic has the given number of messages
+func waitMessages(topic *net.MemoryTopic, count int) []net.Message {
+	for i := 0; i < 500 && len(topic.Messages()) < count; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return topic.Messages()
+}
+
+// headerValue returns the value of a header of the message
+func headerValue(msg net.Message, key string) string {
+	for _, header := range msg.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func TestBatchOutput(t *testing.T) {
+	Convey("Given a market that publishes the events of each command as a batch", t, func() {
+		dir, err := ioutil.TempDir("", "batch_output")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		input := net.NewMemoryTopic("engine.orders.btcusd")
+		output := net.NewMemoryTopic("engine.events.btcusd")
+		mkt := startTestMarket(dir, input, output, MarketEngineConfig{batchOutput: true})
+		defer mkt.Close()
+		defer mkt.consumer.Close()
+
+		writeTestOrders(input, newTestOrder(1, model.MarketSide_Sell), newTestOrder(2, model.MarketSide_Buy))
+
+		Convey("A single Events message should be published for each command", func() {
+			msgs := waitMessages(output, 2)
+			So(msgs, ShouldHaveLength, 2)
+			batches := make([]model.Events, len(msgs))
+			for i, msg := range msgs {
+				So(batches[i].FromBinary(msg.Value), ShouldBeNil)
+				So(batches[i].Market, ShouldEqual, "btcusd")
+				So(batches[i].Topic, ShouldEqual, "engine.orders.btcusd")
+				So(batches[i].Offset, ShouldEqual, int64(i))
+				So(headerValue(msg, HeaderEventType), ShouldEqual, BatchEventType)
+				So(headerValue(msg, HeaderInputOffset), ShouldEqual, []string{"0", "1"}[i])
+			}
+
+			Convey("with the sequence ids of the first and the last event of the command", func() {
+				So(batches[0].Events, ShouldHaveLength, 1)
+				So(batches[0].FirstSeqID, ShouldEqual, 1)
+				So(batches[0].LastSeqID, ShouldEqual, 1)
+				So(batches[1].Events, ShouldHaveLength, 4)
+				So(batches[1].FirstSeqID, ShouldEqual, 2)
+				So(batches[1].LastSeqID, ShouldEqual, 5)
+				So(headerValue(msgs[1], HeaderFirstSeqID), ShouldEqual, "2")
+				So(headerValue(msgs[1], HeaderLastSeqID), ShouldEqual, "5")
+			})
+		})
+
+		Convey("A replayed acknowledgement should not be counted in the sequence ids of the batch", func() {
+			writeTestOrders(input, newTestOrder(1, model.MarketSide_Sell))
+			msgs := waitMessages(output, 3)
+			So(msgs, ShouldHaveLength, 3)
+			var batch model.Events
+			So(batch.FromBinary(msgs[2].Value), ShouldBeNil)
+			So(batch.Events, ShouldHaveLength, 1)
+			So(batch.Events[0].Replay, ShouldBeTrue)
+			So(batch.FirstSeqID, ShouldEqual, 0)
+			So(batch.LastSeqID, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestIncrementUnits(t *testing.T) {
+	Convey("The increments of the market should be converted into units", t, func() {
+		So(incrementUnits(0.01, 8), ShouldEqual, 1000000)
+		So(incrementUnits(0.0001, 4), ShouldEqual, 1)
+		So(incrementUnits(0, 8), ShouldEqual, 0)
+	})
+}
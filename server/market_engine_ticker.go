@@ -0,0 +1,92 @@
+package server
+
+import (
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/marketdata"
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// DefaultTickerThrottle is the minimum number of milliseconds between two published tickers
+const DefaultTickerThrottle = 1000
+
+// ScheduleTicker sends the current time at the throttle interval to refresh the ticker statistics
+func (mkt *marketEngine) ScheduleTicker() {
+	throttle := mkt.config.config.Ticker.Throttle
+	if throttle <= 0 {
+		throttle = DefaultTickerThrottle
+	}
+	for mkt.waitSchedule(time.Duration(throttle) * time.Millisecond) {
+		select {
+		case mkt.tickerTick <- time.Now():
+		case <-mkt.halted:
+			return
+		}
+	}
+}
+
+// aggregateTicker updates the ticker with the trades generated, busted or corrected by a command
+// - Must be called from the order matching process so that the ticker matches the market backup
+func (mkt *marketEngine) aggregateTicker(events []model.Event, at time.Time) {
+	if mkt.ticker == nil {
+		return
+	}
+	mkt.ticker.AddEvents(events, at)
+	if marketdata.AmendsTrades(events) {
+		mkt.ticker.AmendTrades(events, mkt.tradeWindow())
+	}
+}
+
+// refreshTicker computes the ticker statistics at the given time and sends them to the publisher if they changed
+func (mkt *marketEngine) refreshTicker(now time.Time) {
+	if mkt.ticker == nil {
+		return
+	}
+	ticker := mkt.ticker.Stats(now)
+	book := mkt.engine.GetOrderBook()
+	ticker.BestBid = book.GetHighestBid()
+	ticker.BestAsk = book.GetLowestAsk()
+
+	mkt.lastTickerLock.Lock()
+	last := mkt.lastTicker
+	mkt.lastTicker = ticker
+	mkt.lastTickerLock.Unlock()
+
+	// the window moves on every refresh so only the statistics are compared
+	if last != nil {
+		previous := proto.Clone(last).(*model.Ticker)
+		previous.OpenTime = ticker.OpenTime
+		previous.CloseTime = ticker.CloseTime
+		if proto.Equal(previous, ticker) {
+			return
+		}
+	}
+	mkt.tickers <- ticker
+}
+
+// GetTicker returns the last computed ticker statistics of the market or nil if the ticker is disabled
+func (mkt *marketEngine) GetTicker() *model.Ticker {
+	mkt.lastTickerLock.RLock()
+	defer mkt.lastTickerLock.RUnlock()
+	return mkt.lastTicker
+}
+
+// PublishTicker listens for changed ticker statistics and publishes them to the ticker topic
+func (mkt *marketEngine) PublishTicker() {
+	log.Debug().Str("section", "server").Str("action", "init").Str("market", mkt.name).Msg("Starting ticker publisher process")
+	for ticker := range mkt.tickers {
+		raw, err := ticker.ToBinary()
+		if err != nil {
+			log.Error().Err(err).Str("section", "ticker").Str("action", "encode").Str("market", mkt.name).Msg("Unable to encode ticker")
+			continue
+		}
+		if err := mkt.writeOutput(mkt.config.tickerProducer, "ticker", net.Message{Value: raw}); err != nil {
+			mkt.stopOutput("ticker", err)
+		}
+	}
+	log.Info().Str("section", "server").Str("action", "terminate").Str("market", mkt.name).Msg("Closing ticker publisher process")
+}
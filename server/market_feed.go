@@ -0,0 +1,379 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/jsonpb"
+	proto "github.com/golang/protobuf/proto"
+
+	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/model"
+)
+
+// Market data channels that the websocket clients can subscribe to
+const (
+	FeedChannelTrades  = "trades"
+	FeedChannelBBO     = "bbo"
+	FeedChannelDepth   = "depth"
+	FeedChannelCandles = "candles"
+)
+
+// Types of the messages sent to the websocket clients
+const (
+	FeedMessageSnapshot     = "snapshot"
+	FeedMessageUpdate       = "update"
+	FeedMessageUnsubscribed = "unsubscribed"
+	FeedMessageError        = "error"
+)
+
+// feedRecentTrades is the number of recent trades sent when a client subscribes to the trades channel
+const feedRecentTrades = 50
+
+// ErrFeedChannelUnavailable is returned when subscribing to a channel that is not published by the market
+var ErrFeedChannelUnavailable = errors.New("channel not available for market")
+
+// feedMessage is the JSON message sent to the websocket clients
+type feedMessage struct {
+	Type    string          `json:"type"`
+	Market  string          `json:"market,omitempty"`
+	Channel string          `json:"channel,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
+
+// FeedClient receives the encoded market data messages of the channels it subscribed to
+type FeedClient struct {
+	messages chan []byte
+	done     chan struct{}
+	once     sync.Once
+	dropped  int32
+}
+
+// NewFeedClient creates a client that can have the given number of messages waiting to be sent before it is dropped
+func NewFeedClient(buffer int) *FeedClient {
+	if buffer <= 0 {
+		buffer = DefaultWebSocketBuffer
+	}
+	return &FeedClient{
+		messages: make(chan []byte, buffer),
+		done:     make(chan struct{}),
+	}
+}
+
+// Messages returns the messages waiting to be sent to the client
+func (client *FeedClient) Messages() <-chan []byte {
+	return client.messages
+}
+
+// Done is closed when the client was dropped for not keeping up with the markets or when it was closed
+func (client *FeedClient) Done() <-chan struct{} {
+	return client.done
+}
+
+// Dropped returns true if the client was closed for not keeping up with the markets
+func (client *FeedClient) Dropped() bool {
+	return atomic.LoadInt32(&client.dropped) == 1
+}
+
+// Close stops sending messages to the client
+func (client *FeedClient) Close() {
+	client.once.Do(func() { close(client.done) })
+}
+
+// deliver queues a message for the client and drops the client if too many messages are waiting
+// Returns false if the client was closed or dropped
+func (client *FeedClient) deliver(msg []byte) bool {
+	select {
+	case <-client.done:
+		return false
+	default:
+	}
+	select {
+	case client.messages <- msg:
+		return true
+	default:
+		atomic.StoreInt32(&client.dropped, 1)
+		client.Close()
+		return false
+	}
+}
+
+// MarketFeed keeps the public market data of a market and sends the new data to the subscribed clients
+//
+// The feed receives the events and the changed price levels after they were published on the event topic, so the
+// clients never see data that could be lost if the engine is restarted.
+type MarketFeed struct {
+	lock           sync.Mutex
+	market         string
+	bboEvents      bool
+	candles        bool
+	checksumLevels int
+
+	depthSeqID uint64
+	bids       map[uint64]uint64
+	asks       map[uint64]uint64
+	trades     []*model.Event
+	bbo        *model.Event
+	// last closed candle of each interval
+	lastCandles map[int64]*model.Candle
+
+	subscribers map[string]map[*FeedClient]bool
+}
+
+// newMarketFeed creates the feed of the market with the channels enabled by the market configuration
+func newMarketFeed(config MarketConfig) *MarketFeed {
+	checksumLevels := config.Depth.ChecksumLevels
+	if checksumLevels <= 0 {
+		checksumLevels = engine.DefaultChecksumLevels
+	}
+	return &MarketFeed{
+		market:         config.MarketID,
+		bboEvents:      config.BBOEvents,
+		candles:        config.Candles.Enabled,
+		checksumLevels: checksumLevels,
+		bids:           make(map[uint64]uint64),
+		asks:           make(map[uint64]uint64),
+		trades:         make([]*model.Event, 0, feedRecentTrades),
+		lastCandles:    make(map[int64]*model.Candle),
+		subscribers: map[string]map[*FeedClient]bool{
+			FeedChannelTrades:  make(map[*FeedClient]bool),
+			FeedChannelBBO:     make(map[*FeedClient]bool),
+			FeedChannelDepth:   make(map[*FeedClient]bool),
+			FeedChannelCandles: make(map[*FeedClient]bool),
+		},
+	}
+}
+
+// Reset replaces the price levels of the feed with a full snapshot of the order book
+// - Must be called before the market starts processing orders
+func (feed *MarketFeed) Reset(snapshot *model.DepthSnapshot) {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	feed.depthSeqID = snapshot.SeqID
+	feed.bids = make(map[uint64]uint64, len(snapshot.Bids))
+	feed.asks = make(map[uint64]uint64, len(snapshot.Asks))
+	applyDepthLevels(feed.bids, snapshot.Bids)
+	applyDepthLevels(feed.asks, snapshot.Asks)
+}
+
+// Publish updates the feed with the events and the price levels changed by a command and sends them to the clients
+func (feed *MarketFeed) Publish(events []model.Event, depth *model.DepthUpdate) {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	trades := make([]proto.Message, 0)
+	var bbo *model.Event
+	for i := range events {
+		switch events[i].Type {
+		case model.EventType_NewTrade:
+			trade := publicTrade(&events[i])
+			if len(feed.trades) == feedRecentTrades {
+				copy(feed.trades, feed.trades[1:])
+				feed.trades = feed.trades[:feedRecentTrades-1]
+			}
+			feed.trades = append(feed.trades, trade)
+			trades = append(trades, trade)
+		case model.EventType_BestBidOffer:
+			bbo = &events[i]
+		}
+	}
+	if len(trades) > 0 && feed.hasSubscribers(FeedChannelTrades) {
+		if data, err := encodeFeedList(trades); err == nil {
+			feed.broadcast(FeedChannelTrades, data)
+		}
+	}
+	if bbo != nil {
+		feed.bbo = bbo
+		if feed.hasSubscribers(FeedChannelBBO) {
+			if data, err := encodeFeedData(bbo); err == nil {
+				feed.broadcast(FeedChannelBBO, data)
+			}
+		}
+	}
+	if depth != nil {
+		feed.depthSeqID = depth.SeqID
+		applyDepthLevels(feed.bids, depth.Bids)
+		applyDepthLevels(feed.asks, depth.Asks)
+		if feed.hasSubscribers(FeedChannelDepth) {
+			if data, err := encodeFeedData(depth); err == nil {
+				feed.broadcast(FeedChannelDepth, data)
+			}
+		}
+	}
+}
+
+// PublishCandle sends a closed candle to the clients
+func (feed *MarketFeed) PublishCandle(candle *model.Candle) {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	// an amended candle of an older interval doesn't replace the last candle of the interval
+	if last, ok := feed.lastCandles[candle.Interval]; !ok || last.OpenTime <= candle.OpenTime {
+		feed.lastCandles[candle.Interval] = candle
+	}
+	if !feed.hasSubscribers(FeedChannelCandles) {
+		return
+	}
+	if data, err := encodeFeedData(candle); err == nil {
+		feed.broadcast(FeedChannelCandles, data)
+	}
+}
+
+// Subscribe sends a snapshot of the channel to the client followed by every update published after it
+func (feed *MarketFeed) Subscribe(client *FeedClient, channel string) error {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	subscribers, ok := feed.subscribers[channel]
+	if !ok || (channel == FeedChannelBBO && !feed.bboEvents) || (channel == FeedChannelCandles && !feed.candles) {
+		return ErrFeedChannelUnavailable
+	}
+	data, err := feed.snapshot(channel)
+	if err != nil {
+		return err
+	}
+	msg, err := json.Marshal(feedMessage{Type: FeedMessageSnapshot, Market: feed.market, Channel: channel, Data: data})
+	if err != nil {
+		return err
+	}
+	if client.deliver(msg) {
+		subscribers[client] = true
+	}
+	return nil
+}
+
+// Unsubscribe stops sending the updates of the channel to the client
+func (feed *MarketFeed) Unsubscribe(client *FeedClient, channel string) {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	if subscribers, ok := feed.subscribers[channel]; ok {
+		delete(subscribers, client)
+	}
+}
+
+// snapshot encodes the current state of a channel
+func (feed *MarketFeed) snapshot(channel string) (json.RawMessage, error) {
+	switch channel {
+	case FeedChannelTrades:
+		trades := make([]proto.Message, len(feed.trades))
+		for i, trade := range feed.trades {
+			trades[i] = trade
+		}
+		return encodeFeedList(trades)
+	case FeedChannelBBO:
+		if feed.bbo == nil {
+			return nil, nil
+		}
+		return encodeFeedData(feed.bbo)
+	case FeedChannelDepth:
+		snapshot := &model.DepthSnapshot{
+			SeqID: feed.depthSeqID,
+			Bids:  sortedDepthLevels(feed.bids, true),
+			Asks:  sortedDepthLevels(feed.asks, false),
+		}
+		snapshot.Checksum = engine.DepthChecksum(snapshot.Bids, snapshot.Asks, feed.checksumLevels)
+		return encodeFeedData(snapshot)
+	default:
+		intervals := make([]int64, 0, len(feed.lastCandles))
+		for interval := range feed.lastCandles {
+			intervals = append(intervals, interval)
+		}
+		sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+		candles := make([]proto.Message, len(intervals))
+		for i, interval := range intervals {
+			candles[i] = feed.lastCandles[interval]
+		}
+		return encodeFeedList(candles)
+	}
+}
+
+// hasSubscribers returns true if at least one client subscribed to the channel
+func (feed *MarketFeed) hasSubscribers(channel string) bool {
+	return len(feed.subscribers[channel]) > 0
+}
+
+// broadcast sends an update to the subscribers of the channel and removes the ones that were dropped
+func (feed *MarketFeed) broadcast(channel string, data json.RawMessage) {
+	subscribers := feed.subscribers[channel]
+	msg, err := json.Marshal(feedMessage{Type: FeedMessageUpdate, Market: feed.market, Channel: channel, Data: data})
+	if err != nil {
+		return
+	}
+	for client := range subscribers {
+		if !client.deliver(msg) {
+			delete(subscribers, client)
+		}
+	}
+}
+
+// publicTrade removes the order and owner details from a trade event
+func publicTrade(event *model.Event) *model.Event {
+	trade := event.GetTrade()
+	return &model.Event{
+		Type:      event.Type,
+		Market:    event.Market,
+		CreatedAt: event.CreatedAt,
+		SeqID:     event.SeqID,
+		Payload: &model.Event_Trade{Trade: &model.Trade{
+			Price:     trade.Price,
+			Amount:    trade.Amount,
+			TakerSide: trade.TakerSide,
+			SeqID:     trade.SeqID,
+		}},
+	}
+}
+
+// applyDepthLevels sets the new amount of the given price levels and removes the empty ones
+func applyDepthLevels(levels map[uint64]uint64, changed []*model.DepthLevel) {
+	for _, level := range changed {
+		if level.Amount == 0 {
+			delete(levels, level.Price)
+			continue
+		}
+		levels[level.Price] = level.Amount
+	}
+}
+
+// sortedDepthLevels returns the price levels sorted from the best price
+func sortedDepthLevels(levels map[uint64]uint64, descending bool) []*model.DepthLevel {
+	list := make([]*model.DepthLevel, 0, len(levels))
+	for price, amount := range levels {
+		list = append(list, &model.DepthLevel{Price: price, Amount: amount})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if descending {
+			return list[i].Price > list[j].Price
+		}
+		return list[i].Price < list[j].Price
+	})
+	return list
+}
+
+// encodeFeedData encodes a message as JSON leaving out the fields with default values
+func encodeFeedData(msg proto.Message) (json.RawMessage, error) {
+	var buffer bytes.Buffer
+	marshaler := jsonpb.Marshaler{}
+	if err := marshaler.Marshal(&buffer, msg); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// encodeFeedList encodes a list of messages as a JSON array
+func encodeFeedList(list []proto.Message) (json.RawMessage, error) {
+	var buffer bytes.Buffer
+	marshaler := jsonpb.Marshaler{}
+	buffer.WriteByte('[')
+	for i, msg := range list {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		if err := marshaler.Marshal(&buffer, msg); err != nil {
+			return nil, err
+		}
+	}
+	buffer.WriteByte(']')
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// newTestFeed creates the feed of a market that publishes every channel
+func newTestFeed() *MarketFeed {
+	config := MarketConfig{MarketID: "btcusd", BBOEvents: true}
+	config.Candles.Enabled = true
+	return newMarketFeed(config)
+}
+
+// nextFeedMessage decodes the next message queued for the client
+func nextFeedMessage(client *FeedClient) feedMessage {
+	var msg feedMessage
+	select {
+	case raw := <-client.Messages():
+		So(json.Unmarshal(raw, &msg), ShouldBeNil)
+	default:
+	}
+	return msg
+}
+
+func TestMarketFeed(t *testing.T) {
+	Convey("Given the feed of a market with a trade", t, func() {
+		feed := newTestFeed()
+		feed.Reset(&model.DepthSnapshot{SeqID: 1, Bids: []*model.DepthLevel{{Price: 100, Amount: 5}}})
+		feed.Publish([]model.Event{model.NewTradeEvent(2, "btcusd", 1, model.MarketSide_Buy, 1, 2, 10, 20, "", "", 1, 100)}, nil)
+
+		Convey("A subscriber should receive a snapshot followed by the updates", func() {
+			client := NewFeedClient(10)
+			So(feed.Subscribe(client, FeedChannelTrades), ShouldBeNil)
+			feed.Publish([]model.Event{model.NewTradeEvent(3, "btcusd", 2, model.MarketSide_Sell, 3, 4, 10, 20, "", "", 2, 99)}, nil)
+
+			snapshot := nextFeedMessage(client)
+			So(snapshot.Type, ShouldEqual, FeedMessageSnapshot)
+			So(snapshot.Channel, ShouldEqual, FeedChannelTrades)
+			var trades []map[string]interface{}
+			So(json.Unmarshal(snapshot.Data, &trades), ShouldBeNil)
+			So(len(trades), ShouldEqual, 1)
+			So(trades[0], ShouldNotContainKey, "Payload")
+			So(string(snapshot.Data), ShouldNotContainSubstring, "OwnerID")
+
+			update := nextFeedMessage(client)
+			So(update.Type, ShouldEqual, FeedMessageUpdate)
+			So(string(update.Data), ShouldContainSubstring, `"SeqID":"3"`)
+		})
+
+		Convey("The depth snapshot should include the levels published before the subscription", func() {
+			feed.Publish(nil, &model.DepthUpdate{SeqID: 3, PrevSeqID: 1, Asks: []*model.DepthLevel{{Price: 110, Amount: 2}}})
+			client := NewFeedClient(10)
+			So(feed.Subscribe(client, FeedChannelDepth), ShouldBeNil)
+			var snapshot model.DepthSnapshot
+			msg := nextFeedMessage(client)
+			So(msg.Type, ShouldEqual, FeedMessageSnapshot)
+			So(jsonpb.Unmarshal(bytes.NewReader(msg.Data), &snapshot), ShouldBeNil)
+			So(snapshot.SeqID, ShouldEqual, 3)
+			So(len(snapshot.Bids), ShouldEqual, 1)
+			So(len(snapshot.Asks), ShouldEqual, 1)
+		})
+
+		Convey("An unsubscribed client should not receive updates", func() {
+			client := NewFeedClient(10)
+			So(feed.Subscribe(client, FeedChannelBBO), ShouldBeNil)
+			So(nextFeedMessage(client).Type, ShouldEqual, FeedMessageSnapshot)
+			feed.Unsubscribe(client, FeedChannelBBO)
+			feed.Publish([]model.Event{model.NewBBOEvent(3, "btcusd", 100, 5, 110, 2)}, nil)
+			So(len(client.Messages()), ShouldEqual, 0)
+		})
+
+		Convey("A client that does not keep up should be dropped", func() {
+			client := NewFeedClient(2)
+			So(feed.Subscribe(client, FeedChannelBBO), ShouldBeNil)
+			for seqID := uint64(3); seqID < 6; seqID++ {
+				feed.Publish([]model.Event{model.NewBBOEvent(seqID, "btcusd", 100, 5, 110, 2)}, nil)
+			}
+			So(client.Dropped(), ShouldBeTrue)
+			So(feed.hasSubscribers(FeedChannelBBO), ShouldBeFalse)
+			<-client.Done()
+		})
+
+		Convey("Channels disabled for the market should not be available", func() {
+			feed := newMarketFeed(MarketConfig{MarketID: "btcusd"})
+			client := NewFeedClient(10)
+			So(feed.Subscribe(client, FeedChannelBBO), ShouldEqual, ErrFeedChannelUnavailable)
+			So(feed.Subscribe(client, FeedChannelCandles), ShouldEqual, ErrFeedChannelUnavailable)
+			So(feed.Subscribe(client, "orders"), ShouldEqual, ErrFeedChannelUnavailable)
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"sync/atomic"
+
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// stubMarket is a market used to test the servers without starting the order matching process
+type stubMarket struct {
+	snapshot *MarketSnapshot
+	ticker   *model.Ticker
+	feed     *MarketFeed
+	stream   *eventStream
+	submit   func(ctx context.Context, order *model.Order) ([]*model.Event, error)
+	received []*model.Order
+	// number of calls to SubscribeEvents
+	subscriptions int32
+}
+
+func (mkt *stubMarket) Start(context.Context)              {}
+func (mkt *stubMarket) Close()                             {}
+func (mkt *stubMarket) GetMessageChan() <-chan net.Message { return nil }
+func (mkt *stubMarket) LoadMarketFromBackup() error        { return nil }
+func (mkt *stubMarket) Process(net.Message)                {}
+func (mkt *stubMarket) GetTicker() *model.Ticker           { return mkt.ticker }
+func (mkt *stubMarket) GetSnapshot() *MarketSnapshot       { return mkt.snapshot }
+func (mkt *stubMarket) GetFeed() *MarketFeed               { return mkt.feed }
+
+func (mkt *stubMarket) SubmitCommand(ctx context.Context, order *model.Order) ([]*model.Event, error) {
+	mkt.received = append(mkt.received, order)
+	if mkt.submit == nil {
+		return nil, ErrSubscriptionsDisabled
+	}
+	return mkt.submit(ctx, order)
+}
+
+func (mkt *stubMarket) SubmitDirectCommand(ctx context.Context, order *model.Order) ([]*model.Event, error) {
+	return mkt.SubmitCommand(ctx, order)
+}
+
+func (mkt *stubMarket) SubscribeEvents(fromSeqID uint64) (*EventSubscription, error) {
+	atomic.AddInt32(&mkt.subscriptions, 1)
+	if mkt.stream == nil {
+		return nil, ErrSubscriptionsDisabled
+	}
+	return mkt.stream.Subscribe(fromSeqID)
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	proto "github.com/golang/protobuf/proto"
+	"github.com/rs/zerolog/log"
+)
+
+// QueryPathPrefix is the path under which the market queries are served on the monitoring listener
+const QueryPathPrefix = "/api/v1/markets/"
+
+// queryHandler serves the market data queries as JSON
+type queryHandler struct {
+	markets map[string]MarketEngine
+}
+
+// newQueryHandler creates a new handler for the queries of the given markets
+func newQueryHandler(markets map[string]MarketEngine) http.Handler {
+	return &queryHandler{markets: markets}
+}
+
+// ServeHTTP routes requests like /api/v1/markets/{market}/{resource} to the market
+//
+// Supported resources:
+// - depth?limit={levels}: the price levels of the order book
+// - orders/{id}: an open order or a pending stop order
+// - orders?owner={id}: the open orders of an owner
+// - stops: the pending stop orders
+// - seqids: the last sequence ids generated by the market
+// - ticker: the statistics of the last 24 hours
+func (handler *queryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, QueryPathPrefix), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		http.NotFound(w, r)
+		return
+	}
+	market, ok := handler.markets[parts[0]]
+	if !ok {
+		http.Error(w, "unknown market", http.StatusNotFound)
+		return
+	}
+	if parts[1] == "ticker" && len(parts) == 2 {
+		ticker := market.GetTicker()
+		if ticker == nil {
+			http.Error(w, "ticker not available", http.StatusNotFound)
+			return
+		}
+		writeQueryResponse(w, ticker)
+		return
+	}
+	snapshot := market.GetSnapshot()
+	if snapshot == nil {
+		http.Error(w, "market snapshot not available", http.StatusServiceUnavailable)
+		return
+	}
+	switch {
+	case parts[1] == "depth" && len(parts) == 2:
+		limit, err := parseQueryUint(r.URL.Query().Get("limit"))
+		if err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		writeQueryResponse(w, snapshot.Depth(int(limit)))
+	case parts[1] == "orders" && len(parts) == 3:
+		id, err := strconv.ParseUint(parts[2], 10, 64)
+		if err != nil {
+			http.Error(w, "invalid order id", http.StatusBadRequest)
+			return
+		}
+		order := snapshot.Order(id)
+		if order == nil {
+			http.Error(w, "order not found", http.StatusNotFound)
+			return
+		}
+		writeQueryResponse(w, order)
+	case parts[1] == "orders" && len(parts) == 2:
+		owner, err := strconv.ParseUint(r.URL.Query().Get("owner"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid owner", http.StatusBadRequest)
+			return
+		}
+		writeQueryResponse(w, snapshot.OwnerOrders(owner))
+	case parts[1] == "stops" && len(parts) == 2:
+		writeQueryResponse(w, snapshot.StopOrders())
+	case parts[1] == "seqids" && len(parts) == 2:
+		writeQueryResponse(w, snapshot.SeqIDs())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// parseQueryUint parses an optional numeric query parameter that defaults to zero
+func parseQueryUint(value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// writeQueryResponse encodes the message as JSON including the fields with default values
+func writeQueryResponse(w http.ResponseWriter, msg proto.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	marshaler := jsonpb.Marshaler{EmitDefaults: true}
+	if err := marshaler.Marshal(w, msg); err != nil {
+		log.Error().Err(err).Str("section", "query").Str("action", "encode").Msg("Unable to encode query response")
+	}
+}
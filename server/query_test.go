@@ -0,0 +1,116 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	proto "github.com/golang/protobuf/proto"
+
+	"gitlab.com/around25/products/matching-engine/engine"
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// newTestSnapshot indexes the open orders of an order book like the query snapshot process
+func newTestSnapshot(book engine.OrderBook) *MarketSnapshot {
+	market := book.GetOpenOrders()
+	depth := book.GetDepthSnapshot(0)
+	return newMarketSnapshot(&querySnapshotSource{backup: &market, depth: &depth, createdAt: time.Now()}, engine.DefaultChecksumLevels)
+}
+
+// query sends a request to the handler and decodes the JSON response in the message
+func query(handler http.Handler, method, path string, msg proto.Message) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	if recorder.Code == http.StatusOK && msg != nil {
+		So(jsonpb.Unmarshal(strings.NewReader(recorder.Body.String()), msg), ShouldBeNil)
+	}
+	return recorder
+}
+
+func TestQueryHandler(t *testing.T) {
+	Convey("Given a market with open orders and a pending stop order", t, func() {
+		book := engine.NewOrderBook("btcusd", 8, 8)
+		events := make([]model.Event, 0, 10)
+		for _, order := range []model.Order{
+			model.NewOrder(1, uint64(100000000), uint64(100000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder),
+			model.NewOrder(2, uint64(90000000), uint64(200000000), model.MarketSide_Buy, model.OrderType_Limit, model.CommandType_NewOrder),
+			model.NewOrder(3, uint64(110000000), uint64(300000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder),
+		} {
+			order.OwnerID = 10 + order.ID%2
+			book.Process(order, &events)
+		}
+		stop := model.NewOrder(4, uint64(80000000), uint64(100000000), model.MarketSide_Sell, model.OrderType_Limit, model.CommandType_NewOrder)
+		stop.Stop = model.StopLoss_Loss
+		stop.StopPrice = uint64(85000000)
+		book.Process(stop, &events)
+		market := &stubMarket{snapshot: newTestSnapshot(book)}
+		handler := newQueryHandler(map[string]MarketEngine{"btcusd": market})
+
+		Convey("The depth should be limited to the requested number of levels", func() {
+			depth := &model.DepthSnapshot{}
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/depth?limit=1", depth).Code, ShouldEqual, http.StatusOK)
+			So(depth.Bids, ShouldHaveLength, 1)
+			So(depth.Bids[0].Price, ShouldEqual, 100000000)
+			So(depth.Asks, ShouldHaveLength, 1)
+			So(depth.Checksum, ShouldEqual, book.GetDepthChecksum(engine.DefaultChecksumLevels))
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/depth?limit=x", nil).Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("An open order should be found by id", func() {
+			order := &model.QueryOrder{}
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/orders/3", order).Code, ShouldEqual, http.StatusOK)
+			So(order.Order.ID, ShouldEqual, 3)
+			So(order.SeqID, ShouldEqual, book.GetLastEventSeqID())
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/orders/5", nil).Code, ShouldEqual, http.StatusNotFound)
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/orders/x", nil).Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("A pending stop order should be found by id", func() {
+			order := &model.QueryOrder{}
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/orders/4", order).Code, ShouldEqual, http.StatusOK)
+			So(order.Order.ID, ShouldEqual, 4)
+			So(order.Order.Stop, ShouldEqual, model.StopLoss_Loss)
+			So(order.Order.StopPrice, ShouldEqual, 85000000)
+		})
+
+		Convey("The open orders of an owner should be sorted by id", func() {
+			orders := &model.QueryOrders{}
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/orders?owner=11", orders).Code, ShouldEqual, http.StatusOK)
+			So(orders.Orders, ShouldHaveLength, 2)
+			So(orders.Orders[0].ID, ShouldEqual, 1)
+			So(orders.Orders[1].ID, ShouldEqual, 3)
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/orders?owner=99", orders).Code, ShouldEqual, http.StatusOK)
+			So(orders.Orders, ShouldBeEmpty)
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/orders", nil).Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("The pending stop orders and the sequence ids should be returned", func() {
+			stops := &model.QueryOrders{}
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/stops", stops).Code, ShouldEqual, http.StatusOK)
+			So(stops.Orders, ShouldHaveLength, 1)
+			So(stops.Orders[0].ID, ShouldEqual, 4)
+			seqIDs := &model.QuerySeqIDs{}
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/seqids", seqIDs).Code, ShouldEqual, http.StatusOK)
+			So(seqIDs.Market, ShouldEqual, "btcusd")
+			So(seqIDs.EventSeqID, ShouldEqual, book.GetLastEventSeqID())
+		})
+
+		Convey("Invalid requests should be rejected", func() {
+			So(query(handler, http.MethodPost, "/api/v1/markets/btcusd/depth", nil).Code, ShouldEqual, http.StatusMethodNotAllowed)
+			So(query(handler, http.MethodGet, "/api/v1/markets/ethbtc/depth", nil).Code, ShouldEqual, http.StatusNotFound)
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/unknown", nil).Code, ShouldEqual, http.StatusNotFound)
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/ticker", nil).Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Queries should be unavailable until the first snapshot is taken", func() {
+			market.snapshot = nil
+			So(query(handler, http.MethodGet, "/api/v1/markets/btcusd/depth", nil).Code, ShouldEqual, http.StatusServiceUnavailable)
+		})
+	})
+}
@@ -35,6 +35,8 @@ type server struct {
 	ctx     context.Context
 	markets map[string]MarketEngine
 	checker *license.LicenseChecker
+	// the FIX gateway when it is enabled
+	fixGateway *fixGateway
 }
 
 var (
@@ -73,6 +75,43 @@ var (
 		// Which market are the events from?
 		"market",
 	})
+	publishRetryCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "engine_publish_retry_count",
+		Help: "Number of failed writes of events that were retried.",
+	}, []string{
+		// Which market are the events from?
+		"market",
+	})
+	publishFailureCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "engine_publish_failure_count",
+		Help: "Number of writes of events that failed after all the retries and stopped the market.",
+	}, []string{
+		// Which market are the events from?
+		"market",
+	})
+	outputAvailable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "engine_output_available",
+		Help: "Set to 0 while the events of the market can't be published and the processing of commands is paused.",
+	}, []string{
+		// Which market are the events from?
+		"market",
+	})
+	lastPublishedSeqID = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "engine_last_published_seqid",
+		Help: "Sequence id of the last event published by the market.",
+	}, []string{
+		// Which market are the events from?
+		"market",
+	})
+	deadLetterCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "engine_dead_letter_count",
+		Help: "Number of input messages that could not be decoded or failed validation.",
+	}, []string{
+		// Which market are the messages from?
+		"market",
+		// Why was the message rejected? decode or invalid
+		"reason",
+	})
 )
 
 func init() {
@@ -81,6 +120,11 @@ func init() {
 	prometheus.MustRegister(messagesQueued)
 	prometheus.MustRegister(ordersQueued)
 	prometheus.MustRegister(eventsQueued)
+	prometheus.MustRegister(publishRetryCount)
+	prometheus.MustRegister(publishFailureCount)
+	prometheus.MustRegister(outputAvailable)
+	prometheus.MustRegister(lastPublishedSeqID)
+	prometheus.MustRegister(deadLetterCount)
 }
 
 // NewServer constructor
@@ -92,12 +136,59 @@ func NewServer(config Config) Server {
 		maxOffset = EnvDevMaxOffset
 		log.Info().Str("section", "server").Str("action", "init").Msg("Running in development mode. Limited to max 20k commands per market.")
 	}
+	transports := newTransports(config)
 	for key, marketCfg := range config.Markets {
+		transport := marketCfg.Transport
 		marketEngineConfig := MarketEngineConfig{
 			config:    marketCfg,
-			producer:  NewProducer(config.Kafka.Writer, config.Brokers.Producers[marketCfg.Publish.Broker], config.Kafka.UseTLS, marketCfg.Publish.Topic),
-			consumer:  NewConsumer(config.Kafka.Reader, config.Brokers.Consumers[marketCfg.Listen.Broker], config.Kafka.UseTLS, marketCfg.Listen.Topic),
+			producer:  transports.producer(transport, config.Brokers.Producers[marketCfg.Publish.Broker], marketCfg.Publish.Topic),
+			consumer:  transports.consumer(transport, config.Brokers.Consumers[marketCfg.Listen.Broker], marketCfg.Listen.Topic),
 			maxOffset: maxOffset,
+			retry:     config.Retry,
+		}
+		switch marketCfg.Output {
+		case "", OutputEvent:
+		case OutputBatch:
+			marketEngineConfig.batchOutput = true
+		default:
+			log.Fatal().Str("section", "init:market").Str("action", "set_output").Str("market", key).Str("output", marketCfg.Output).Msg("Unknown output mode")
+		}
+		if !validEventKey(marketCfg.EventKey) {
+			log.Fatal().Str("section", "init:market").Str("action", "set_event_key").Str("market", key).Str("event_key", marketCfg.EventKey).Msg("Unknown event key")
+		}
+		marketEngineConfig.eventProducers = newEventProducers(transports, transport, config.Brokers.Producers, marketCfg)
+		if marketCfg.Depth.Enabled {
+			marketEngineConfig.depthProducer = transports.producer(transport, config.Brokers.Producers[marketCfg.Depth.Publish.Broker], marketCfg.Depth.Publish.Topic)
+		}
+		if marketCfg.OrderFeed.Enabled {
+			if marketCfg.OrderFeed.AnonymiseKey == "" {
+				log.Fatal().Str("section", "init:market").Str("action", "set_order_feed").Str("market", key).Msg("The order feed requires an anonymise key")
+			}
+			marketEngineConfig.orderFeedProducer = transports.producer(transport, config.Brokers.Producers[marketCfg.OrderFeed.Publish.Broker], marketCfg.OrderFeed.Publish.Topic)
+		}
+		if marketCfg.Candles.Enabled {
+			marketEngineConfig.candlesProducer = transports.producer(transport, config.Brokers.Producers[marketCfg.Candles.Publish.Broker], marketCfg.Candles.Publish.Topic)
+		}
+		if marketCfg.Private.Enabled {
+			marketEngineConfig.privateProducer = transports.producer(transport, config.Brokers.Producers[marketCfg.Private.Publish.Broker], marketCfg.Private.Publish.Topic)
+		}
+		if marketCfg.DeadLetter.Enabled {
+			marketEngineConfig.deadLetterProducer = transports.producer(transport, config.Brokers.Producers[marketCfg.DeadLetter.Publish.Broker], marketCfg.DeadLetter.Publish.Topic)
+		}
+		if marketCfg.Ticker.Enabled {
+			marketEngineConfig.tickerProducer = transports.producer(transport, config.Brokers.Producers[marketCfg.Ticker.Publish.Broker], marketCfg.Ticker.Publish.Topic)
+		}
+		if config.Server.GRPC.Enabled || config.Server.FIX.Enabled {
+			// commands received over gRPC or FIX are written on the input topic of the market using the consumer brokers
+			inputBroker := ProducerConfig{Hosts: config.Brokers.Consumers[marketCfg.Listen.Broker].Hosts}
+			marketEngineConfig.commandProducer = transports.producer(transport, inputBroker, marketCfg.Listen.Topic)
+			marketEngineConfig.eventHistory = config.Server.GRPC.History
+		}
+		marketEngineConfig.marketFeed = config.Server.WebSocket.Enabled
+		marketEngineConfig.directCommands = config.Server.Socket.Enabled
+		if config.Server.Socket.Enabled {
+			// commands received over the socket are journaled so they are replayed when the market is restored
+			marketEngineConfig.directJournal = transports.journal(directJournalTopic(marketCfg.MarketID))
 		}
 		markets[key] = NewMarketEngine(marketEngineConfig)
 	}
@@ -140,7 +231,7 @@ func (srv *server) checkLicense() {
 // Listen for new events that affect the market and process them
 func (srv *server) Listen() {
 	// start prometheus profilling metrics
-	go loopProfillingServer(srv.config.Server.Monitoring)
+	go loopProfillingServer(srv.config.Server.Monitoring, srv.markets)
 	// check license
 	if srv.config.Environment != EnvDev && version.Variant != VariantDev {
 		srv.checkLicense()
@@ -153,6 +244,14 @@ func (srv *server) Listen() {
 
 	// listen for messages and ditribute them to the correct markets
 	go srv.ReceiveMessages()
+	// accept commands and subscriptions over gRPC
+	go loopGRPCServer(srv.config.Server.GRPC, srv.markets)
+	// stream the public market data to websocket clients
+	go loopWebSocketServer(srv.config.Server.WebSocket, srv.markets)
+	// accept orders from colocated gateways over the Unix domain socket
+	go loopSocketServer(srv.config.Server.Socket, srv.markets)
+	// accept orders from FIX clients
+	srv.fixGateway = loopFIXGateway(srv.config, srv.markets)
 	srv.stopOnSignal()
 }
 
@@ -172,6 +271,9 @@ func (srv *server) stopOnSignal() {
 
 func (srv *server) shutdown(code int) {
 	log.Info().Str("section", "server").Str("action", "terminate").Msg("Received shutdown signal. Starting graceful shutdown...")
+	if srv.fixGateway != nil {
+		srv.fixGateway.Stop()
+	}
 	log.Debug().Str("section", "server").Str("action", "terminate").Msg("Markets closing...")
 	srv.closeMarkets()
 	log.Debug().Str("section", "server").Str("action", "terminate").Msg("Waiting a few seconds to finish")
@@ -200,7 +302,7 @@ func loopMarketReceive(key string, market MarketEngine) {
 		Msg("Closing market consumer channel")
 }
 
-func loopProfillingServer(config MonitoringConfig) {
+func loopProfillingServer(config MonitoringConfig, markets map[string]MarketEngine) {
 	if !config.Enabled {
 		return
 	}
@@ -213,6 +315,7 @@ func loopProfillingServer(config MonitoringConfig) {
 		Str("path", "/metrics").
 		Msg("Starting profilling server")
 	http.Handle("/metrics", promhttp.Handler())
+	http.Handle(QueryPathPrefix, newQueryHandler(markets))
 	err := http.ListenAndServe(config.Host+":"+config.Port, nil)
 	if err != nil {
 		log.Error().Err(err).
@@ -227,11 +330,11 @@ func loopProfillingServer(config MonitoringConfig) {
 }
 
 // NewConsumer starts a new consumer based on the config
-func NewConsumer(rCfg net.KafkaReaderConfig, config ConsumerConfig, useTLS bool, topic string) net.KafkaConsumer {
+func NewConsumer(rCfg net.KafkaReaderConfig, config ConsumerConfig, useTLS bool, topic string) net.Consumer {
 	return net.NewKafkaConsumer(rCfg, config.Hosts, useTLS, topic, 0)
 }
 
 // NewProducer starts a new producer based on the config
-func NewProducer(wCfg net.KafkaWriterConfig, config ProducerConfig, useTLS bool, topic string) net.KafkaProducer {
+func NewProducer(wCfg net.KafkaWriterConfig, config ProducerConfig, useTLS bool, topic string) net.Producer {
 	return net.NewKafkaProducer(wCfg, config.Hosts, useTLS, topic)
 }
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/model"
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// DefaultSocketPath is the default path of the Unix domain socket on which orders are accepted
+const DefaultSocketPath = "/tmp/matching_engine.sock"
+
+// socketResponse is sent to the client for each order read from the socket
+type socketResponse struct {
+	Events []json.RawMessage `json:"events,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// socketServer accepts orders from colocated gateways over a Unix domain socket
+type socketServer struct {
+	markets map[string]MarketEngine
+	timeout time.Duration
+}
+
+// newSocketServer creates the handler of the socket connections
+func newSocketServer(config SocketConfig, markets map[string]MarketEngine) *socketServer {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultGRPCTimeout
+	}
+	return &socketServer{
+		markets: markets,
+		timeout: time.Duration(timeout) * time.Millisecond,
+	}
+}
+
+// serve processes the orders received on a connection one by one and sends back the events generated by each of
+// them in the order in which the orders were received
+//
+// Each order is a JSON document on a single line using the field names of the Order message. A line that can't be
+// decoded is answered with an error and the connection continues with the next line.
+func (srv *socketServer) serve(conn net.Socket) {
+	defer conn.Close()
+	// connections stay open between orders
+	conn.SetTimeout(0)
+	for {
+		var raw json.RawMessage
+		err := conn.Read(&raw)
+		if err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.Is(err, net.ErrMessageTooLarge) || errors.As(err, &syntaxErr) {
+				if err := srv.sendError(conn, "invalid message: "+err.Error()); err != nil {
+					return
+				}
+				continue
+			}
+			return
+		}
+		order := &model.Order{}
+		if err := jsonpb.UnmarshalString(string(raw), order); err != nil {
+			if err := srv.sendError(conn, "invalid order: "+err.Error()); err != nil {
+				return
+			}
+			continue
+		}
+		if err := srv.submit(conn, order); err != nil {
+			log.Debug().Err(err).Str("section", "socket").Str("action", "reply").Str("market", order.Market).Msg("Unable to send events to socket client")
+			return
+		}
+	}
+}
+
+// submit sends the order to its market and replies with the generated events
+// - Trade busts and corrections are reserved to the operators and are only accepted from the input topic
+func (srv *socketServer) submit(conn net.Socket, order *model.Order) error {
+	if order.EventType != model.CommandType_NewOrder && order.EventType != model.CommandType_CancelOrder {
+		return srv.sendError(conn, "only new orders and cancel requests are accepted")
+	}
+	market, ok := srv.markets[order.Market]
+	if !ok {
+		return srv.sendError(conn, "unknown market")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), srv.timeout)
+	defer cancel()
+	events, err := market.SubmitDirectCommand(ctx, order)
+	if err != nil {
+		return srv.sendError(conn, err.Error())
+	}
+	marshaler := jsonpb.Marshaler{EmitDefaults: true}
+	response := socketResponse{Events: make([]json.RawMessage, len(events))}
+	for i, event := range events {
+		raw, err := marshaler.MarshalToString(event)
+		if err != nil {
+			return srv.sendError(conn, err.Error())
+		}
+		response.Events[i] = json.RawMessage(raw)
+	}
+	return conn.Send(response)
+}
+
+// sendError replies to the client with an error
+func (srv *socketServer) sendError(conn net.Socket, message string) error {
+	return conn.Send(socketResponse{Error: message})
+}
+
+// loopSocketServer accepts the connections on the configured Unix domain socket
+func loopSocketServer(config SocketConfig, markets map[string]MarketEngine) {
+	if !config.Enabled {
+		return
+	}
+	path := config.Path
+	if path == "" {
+		path = DefaultSocketPath
+	}
+	log.Debug().Str("section", "server").Str("action", "init").Str("goroutine", "server.socket").Str("path", path).Msg("Starting socket server")
+	listener := net.NewSocket(path)
+	if err := listener.Listen(); err != nil {
+		log.Fatal().Err(err).Str("section", "server").Str("action", "init").Str("goroutine", "server.socket").Str("path", path).Msg("Unable to listen on socket")
+		return
+	}
+	srv := newSocketServer(config, markets)
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Error().Err(err).Str("section", "server").Str("action", "accept").Str("goroutine", "server.socket").Str("path", path).Msg("Unable to accept socket connection")
+			return
+		}
+		go srv.serve(conn)
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/around25/products/matching-engine/net"
+)
+
+// Transports that can be used for the topics of a market
+const (
+	TransportKafka   = "kafka"
+	TransportJournal = "journal"
+	TransportMemory  = "memory"
+	TransportNATS    = "nats"
+	TransportRedis   = "redis"
+)
+
+// transports creates the producers and the consumers of the topics with the transport configured for each market
+// - The journals and the memory topics are shared by the producers and the consumers of the same topic
+type transports struct {
+	config   Config
+	journals map[string]*net.FileJournal
+	topics   map[string]*net.MemoryTopic
+}
+
+// newTransports creates the transports of the server
+func newTransports(config Config) *transports {
+	return &transports{
+		config:   config,
+		journals: make(map[string]*net.FileJournal),
+		topics:   make(map[string]*net.MemoryTopic),
+	}
+}
+
+// producer creates a producer for the topic using the given transport
+func (t *transports) producer(transport string, broker ProducerConfig, topic string) net.Producer {
+	switch transport {
+	case "", TransportKafka:
+		return NewProducer(t.config.Kafka.Writer, broker, t.config.Kafka.UseTLS, topic)
+	case TransportJournal:
+		return net.NewFileJournalProducer(t.journal(topic))
+	case TransportMemory:
+		return net.NewMemoryProducer(t.memoryTopic(topic))
+	case TransportNATS:
+		return net.NewNATSProducer(t.config.NATS, broker.Hosts, topic)
+	case TransportRedis:
+		return net.NewRedisProducer(t.config.Redis, broker.Hosts, topic)
+	}
+	log.Fatal().Str("section", "init:market").Str("action", "create_producer").Str("transport", transport).Str("topic", topic).Msg("Unknown transport")
+	return nil
+}
+
+// consumer creates a consumer for the topic using the given transport
+func (t *transports) consumer(transport string, broker ConsumerConfig, topic string) net.Consumer {
+	switch transport {
+	case "", TransportKafka:
+		return NewConsumer(t.config.Kafka.Reader, broker, t.config.Kafka.UseTLS, topic)
+	case TransportJournal:
+		return net.NewFileJournalConsumer(t.journal(topic), t.config.Kafka.Reader.ChannelSize)
+	case TransportMemory:
+		return net.NewMemoryConsumer(t.memoryTopic(topic), t.config.Kafka.Reader.ChannelSize)
+	case TransportNATS:
+		return net.NewNATSConsumer(t.config.NATS, broker.Hosts, topic)
+	case TransportRedis:
+		return net.NewRedisConsumer(t.config.Redis, broker.Hosts, topic)
+	}
+	log.Fatal().Str("section", "init:market").Str("action", "create_consumer").Str("transport", transport).Str("topic", topic).Msg("Unknown transport")
+	return nil
+}
+
+// journal opens the journal of the topic in the configured directory
+func (t *transports) journal(topic string) *net.FileJournal {
+	if journal, ok := t.journals[topic]; ok {
+		return journal
+	}
+	path := filepath.Join(t.config.Journal.Path, topic+".journal")
+	journal, err := net.OpenFileJournal(path, topic, t.config.Journal.Sync)
+	if err != nil {
+		log.Fatal().Err(err).Str("section", "init:market").Str("action", "open_journal").Str("path", path).Msg("Unable to open journal")
+	}
+	t.journals[topic] = journal
+	return journal
+}
+
+// memoryTopic returns the in-memory topic with the given name
+func (t *transports) memoryTopic(topic string) *net.MemoryTopic {
+	if memoryTopic, ok := t.topics[topic]; ok {
+		return memoryTopic
+	}
+	memoryTopic := net.NewMemoryTopic(topic)
+	t.topics[topic] = memoryTopic
+	return memoryTopic
+}
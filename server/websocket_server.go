@@ -0,0 +1,191 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultWebSocketPath is the default path on which the websocket connections are accepted
+const DefaultWebSocketPath = "/ws"
+
+// DefaultWebSocketBuffer is the default number of messages that can wait to be sent to a client before it is dropped
+const DefaultWebSocketBuffer = 1000
+
+const (
+	// time allowed to write a message to the client
+	websocketWriteWait = 10 * time.Second
+	// time allowed to read the next pong message from the client
+	websocketPongWait = 60 * time.Second
+	// interval at which pings are sent to the client, must be less than the pong wait
+	websocketPingPeriod = websocketPongWait * 9 / 10
+	// maximum size of a message received from the client
+	websocketMaxMessageSize = 4096
+)
+
+// Operations that the websocket clients can send
+const (
+	FeedOpSubscribe   = "subscribe"
+	FeedOpUnsubscribe = "unsubscribe"
+)
+
+// feedRequest is the JSON message received from the websocket clients
+type feedRequest struct {
+	Op      string `json:"op"`
+	Market  string `json:"market"`
+	Channel string `json:"channel"`
+}
+
+// feedSubscription identifies a channel of a market to which a client subscribed
+type feedSubscription struct {
+	market  string
+	channel string
+}
+
+// websocketServer streams the public market data of the markets to the websocket clients
+type websocketServer struct {
+	config   WebSocketConfig
+	markets  map[string]MarketEngine
+	upgrader websocket.Upgrader
+}
+
+// newWebSocketServer creates the handler of the websocket connections
+func newWebSocketServer(config WebSocketConfig, markets map[string]MarketEngine) *websocketServer {
+	return &websocketServer{
+		config:  config,
+		markets: markets,
+		upgrader: websocket.Upgrader{
+			// public market data can be read from any origin
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// ServeHTTP upgrades the connection and handles the subscriptions of the client until it disconnects
+//
+// Clients send messages like {"op": "subscribe", "market": "ltcbtc", "channel": "depth"} and receive a snapshot of
+// the channel followed by the updates published by the market. Clients that do not read their messages fast enough
+// are disconnected.
+func (srv *websocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := srv.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Debug().Err(err).Str("section", "websocket").Str("action", "upgrade").Str("remote", r.RemoteAddr).Msg("Unable to upgrade connection")
+		return
+	}
+	client := NewFeedClient(srv.config.Buffer)
+	go srv.writeMessages(conn, client)
+	subscriptions := srv.readRequests(conn, client)
+	for subscription := range subscriptions {
+		srv.markets[subscription.market].GetFeed().Unsubscribe(client, subscription.channel)
+	}
+	client.Close()
+}
+
+// readRequests handles the messages of the client until the connection is closed and returns its subscriptions
+func (srv *websocketServer) readRequests(conn *websocket.Conn, client *FeedClient) map[feedSubscription]bool {
+	subscriptions := make(map[feedSubscription]bool)
+	conn.SetReadLimit(websocketMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(websocketPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(websocketPongWait))
+	})
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return subscriptions
+		}
+		var request feedRequest
+		if err := json.Unmarshal(raw, &request); err != nil {
+			srv.sendError(client, request, "invalid message")
+			continue
+		}
+		market, ok := srv.markets[request.Market]
+		if !ok || market.GetFeed() == nil {
+			srv.sendError(client, request, "unknown market")
+			continue
+		}
+		subscription := feedSubscription{market: request.Market, channel: request.Channel}
+		switch request.Op {
+		case FeedOpSubscribe:
+			if subscriptions[subscription] {
+				continue
+			}
+			if err := market.GetFeed().Subscribe(client, request.Channel); err != nil {
+				srv.sendError(client, request, err.Error())
+				continue
+			}
+			subscriptions[subscription] = true
+		case FeedOpUnsubscribe:
+			market.GetFeed().Unsubscribe(client, request.Channel)
+			delete(subscriptions, subscription)
+			srv.send(client, feedMessage{Type: FeedMessageUnsubscribed, Market: request.Market, Channel: request.Channel})
+		default:
+			srv.sendError(client, request, "unknown operation")
+		}
+	}
+}
+
+// writeMessages sends the queued messages and the pings to the client until it is closed or dropped
+func (srv *websocketServer) writeMessages(conn *websocket.Conn, client *FeedClient) {
+	ticker := time.NewTicker(websocketPingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+	for {
+		select {
+		case msg := <-client.Messages():
+			conn.SetWriteDeadline(time.Now().Add(websocketWriteWait))
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				client.Close()
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(websocketWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				client.Close()
+				return
+			}
+		case <-client.Done():
+			if client.Dropped() {
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client not keeping up"), time.Now().Add(websocketWriteWait))
+			}
+			return
+		}
+	}
+}
+
+// sendError sends an error message about a request to the client
+func (srv *websocketServer) sendError(client *FeedClient, request feedRequest, message string) {
+	srv.send(client, feedMessage{Type: FeedMessageError, Market: request.Market, Channel: request.Channel, Message: message})
+}
+
+// send queues a message for the client
+func (srv *websocketServer) send(client *FeedClient, msg feedMessage) {
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	client.deliver(raw)
+}
+
+// loopWebSocketServer accepts the websocket connections on the configured address
+func loopWebSocketServer(config WebSocketConfig, markets map[string]MarketEngine) {
+	if !config.Enabled {
+		return
+	}
+	address := config.Host + ":" + config.Port
+	path := config.Path
+	if path == "" {
+		path = DefaultWebSocketPath
+	}
+	log.Debug().Str("section", "server").Str("action", "init").Str("goroutine", "server.websocket").Str("address", address).Str("path", path).Msg("Starting websocket server")
+	mux := http.NewServeMux()
+	mux.Handle(path, newWebSocketServer(config, markets))
+	if err := http.ListenAndServe(address, mux); err != nil {
+		log.Error().Err(err).Str("section", "server").Str("action", "init").Str("goroutine", "server.websocket").Str("address", address).Str("path", path).Msg("Error starting websocket server")
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"gitlab.com/around25/products/matching-engine/model"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// readFeedMessage reads the next message sent by the websocket server
+func readFeedMessage(conn *websocket.Conn) feedMessage {
+	var msg feedMessage
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	So(conn.ReadJSON(&msg), ShouldBeNil)
+	return msg
+}
+
+// waitFeedSubscribers waits until the channel of the feed has the given number of subscribers
+func waitFeedSubscribers(feed *MarketFeed, channel string, count int) bool {
+	for i := 0; i < 500; i++ {
+		feed.lock.Lock()
+		subscribers := len(feed.subscribers[channel])
+		feed.lock.Unlock()
+		if subscribers == count {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestWebSocketServer(t *testing.T) {
+	Convey("Given a websocket server for a market", t, func() {
+		feed := newTestFeed()
+		markets := map[string]MarketEngine{"btcusd": &stubMarket{feed: feed}}
+		server := httptest.NewServer(newWebSocketServer(WebSocketConfig{Buffer: 2}, markets))
+		defer server.Close()
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		So(err, ShouldBeNil)
+		defer conn.Close()
+
+		Convey("A subscription should receive a snapshot followed by the updates", func() {
+			So(conn.WriteJSON(feedRequest{Op: FeedOpSubscribe, Market: "btcusd", Channel: FeedChannelBBO}), ShouldBeNil)
+			snapshot := readFeedMessage(conn)
+			So(snapshot.Type, ShouldEqual, FeedMessageSnapshot)
+			So(snapshot.Market, ShouldEqual, "btcusd")
+			So(snapshot.Channel, ShouldEqual, FeedChannelBBO)
+
+			feed.Publish([]model.Event{model.NewBBOEvent(1, "btcusd", 100, 5, 110, 2)}, nil)
+			update := readFeedMessage(conn)
+			So(update.Type, ShouldEqual, FeedMessageUpdate)
+			So(string(update.Data), ShouldContainSubstring, `"SeqID":"1"`)
+
+			Convey("and no more updates after unsubscribing", func() {
+				So(conn.WriteJSON(feedRequest{Op: FeedOpUnsubscribe, Market: "btcusd", Channel: FeedChannelBBO}), ShouldBeNil)
+				So(readFeedMessage(conn).Type, ShouldEqual, FeedMessageUnsubscribed)
+				So(waitFeedSubscribers(feed, FeedChannelBBO, 0), ShouldBeTrue)
+			})
+		})
+
+		Convey("Invalid requests should be answered with an error", func() {
+			So(conn.WriteJSON(feedRequest{Op: FeedOpSubscribe, Market: "ethusd", Channel: FeedChannelBBO}), ShouldBeNil)
+			msg := readFeedMessage(conn)
+			So(msg.Type, ShouldEqual, FeedMessageError)
+			So(msg.Message, ShouldEqual, "unknown market")
+
+			So(conn.WriteJSON(feedRequest{Op: "resubscribe", Market: "btcusd", Channel: FeedChannelBBO}), ShouldBeNil)
+			So(readFeedMessage(conn).Message, ShouldEqual, "unknown operation")
+
+			So(conn.WriteMessage(websocket.TextMessage, []byte("{")), ShouldBeNil)
+			So(readFeedMessage(conn).Message, ShouldEqual, "invalid message")
+		})
+
+		Convey("The subscriptions should be removed when the client disconnects", func() {
+			So(conn.WriteJSON(feedRequest{Op: FeedOpSubscribe, Market: "btcusd", Channel: FeedChannelTrades}), ShouldBeNil)
+			readFeedMessage(conn)
+			So(conn.Close(), ShouldBeNil)
+			So(waitFeedSubscribers(feed, FeedChannelTrades, 0), ShouldBeTrue)
+		})
+
+		Convey("A client that does not read its messages should be disconnected", func() {
+			So(conn.WriteJSON(feedRequest{Op: FeedOpSubscribe, Market: "btcusd", Channel: FeedChannelBBO}), ShouldBeNil)
+			So(waitFeedSubscribers(feed, FeedChannelBBO, 1), ShouldBeTrue)
+			feed.lock.Lock()
+			var client *FeedClient
+			for subscriber := range feed.subscribers[FeedChannelBBO] {
+				client = subscriber
+			}
+			feed.lock.Unlock()
+			// publish faster than the server can write until the queue of the client overflows
+			for seqID := uint64(1); !client.Dropped() && seqID < 100000; seqID++ {
+				feed.Publish([]model.Event{model.NewBBOEvent(seqID, "btcusd", 100, 5, 110, 2)}, nil)
+			}
+			So(client.Dropped(), ShouldBeTrue)
+			So(waitFeedSubscribers(feed, FeedChannelBBO, 0), ShouldBeTrue)
+
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			for {
+				if _, _, err = conn.ReadMessage(); err != nil {
+					break
+				}
+			}
+			So(websocket.IsCloseError(err, websocket.CloseTryAgainLater), ShouldBeTrue)
+		})
+	})
+}
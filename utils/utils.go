@@ -32,7 +32,7 @@ var DecimalAwayFromZeroCtx = decimal.Context{
 func Divide(x, y uint64, xprec, yprec, prec int) uint64 {
 	xDec := decimal.WithContext(DecimalToZeroCtx).SetUint64(x)
 	xDec.Quo(xDec, decimal.WithContext(DecimalToZeroCtx).SetUint64(y))
-	xDec.Mul(xDec, decimal.New(10, -1*(xprec-yprec+prec-1))).Quantize(0)
+	xDec.Mul(xDec, decimal.New(10, -1*(yprec-xprec+prec-1))).Quantize(0)
 	z, _ := xDec.Uint64()
 	return z
 }
@@ -48,6 +48,9 @@ func TestDivide(t *testing.T) {
 			So(utils.Divide(1, 3, 8, 8, 8), ShouldEqual, 33333333)
 			So(utils.Divide(15, 5, 8, 8, 8), ShouldEqual, 300000000)
 			So(utils.Divide(500000, 100000, 2, 2, 8), ShouldEqual, 500000000)
+			So(utils.Divide(10000, 100000000, 2, 8, 2), ShouldEqual, 10000)
+			So(utils.Divide(123450, 200000000, 2, 8, 2), ShouldEqual, 61725)
+			So(utils.Divide(300000000, 150, 8, 2, 8), ShouldEqual, 200000000)
 			So(utils.Divide(1278543132023424178, 999636900000000, 8, 8, 8), ShouldEqual, 127900753966)
 		})
 	})